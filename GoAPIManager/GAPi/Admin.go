@@ -0,0 +1,566 @@
+package GoAPIManager
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/term"
+)
+
+// Размер страницы списка пользователей
+const (
+	defaultUsersPageSize = 20
+	maxUsersPageSize     = 100
+)
+
+// Тело запроса на смену глобальной роли пользователя
+type updateUserRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=User Admin"`
+}
+
+// Доступ только для администраторов сервиса
+func adminMiddleware(c *gin.Context) {
+	if !globalRoleCan(c.GetString("role"), PermAdminAccess) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// Пользователь из параметра :id; если его нет, ответ уже отправлен и возвращается nil
+func adminTargetUser(c *gin.Context) *User {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil
+	}
+
+	user, err := store.Users.GetByID(c.Request.Context(), uint(userID))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return nil
+	}
+	return user
+}
+
+var errLastAdmin = errors.New("service must have at least one admin")
+
+// Является ли пользователь userID единственным активным администратором. Проверка выполняется
+// в транзакции вместе с изменением: строки администраторов блокируются до её конца, иначе
+// два параллельных понижения разных администраторов оставили бы сервис без администраторов.
+func isLastAdmin(ctx context.Context, tx *Store, userID uint) (bool, error) {
+	admins, err := tx.Users.LockActiveAdmins(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(admins) == 1 && admins[0] == userID, nil
+}
+
+// Сохранение пользователя и завершение всех его сессий: новые роль, пароль или блокировка
+// должны действовать сразу, а не после истечения выданных токенов. Если пользователь
+// перестаёт быть активным администратором, а других нет, возвращается errLastAdmin.
+func updateUserAndRevokeSessions(ctx context.Context, user *User) error {
+	return store.Transaction(ctx, func(tx *Store) error {
+		if !isAdmin(user.Role) || user.DisabledAt != nil {
+			last, err := isLastAdmin(ctx, tx, user.ID)
+			if err != nil {
+				return err
+			}
+			if last {
+				return errLastAdmin
+			}
+		}
+		if err := tx.Users.Update(ctx, user); err != nil {
+			return err
+		}
+		_, err := tx.Sessions.RevokeAll(ctx, user.ID)
+		return err
+	})
+}
+
+// @Summary Список пользователей
+// @Description Возвращает пользователей постранично, с поиском по части имени. Только для администраторов.
+// @Tags Администрирование
+// @Produce json
+// @Param search query string false "Часть имени пользователя"
+// @Param page query int false "Номер страницы (с 1)"
+// @Param page_size query int false "Размер страницы (по умолчанию 20, не больше 100)"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Пользователи и общее количество"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/users [get]
+func listUsers(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page"})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultUsersPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxUsersPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid page_size, allowed values are 1-%d", maxUsersPageSize)})
+		return
+	}
+
+	users, total, err := store.Users.List(c.Request.Context(), UserFilter{
+		Search: strings.TrimSpace(c.Query("search")),
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Users": users, "Total": total, "Page": page, "PageSize": pageSize})
+}
+
+// @Summary Пользователь
+// @Description Возвращает пользователя, количество проектов и задач, которые придётся передать при его удалении, и счётчик неудачных попыток входа (LoginFailures, null — попыток не было). Только для администраторов.
+// @Tags Администрирование
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Пользователь"
+// @Failure 400 {object} map[string]string "Некорректный ID пользователя"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/users/{id} [get]
+func getUser(c *gin.Context) {
+	user := adminTargetUser(c)
+	if user == nil {
+		return
+	}
+
+	ownership, err := store.Users.Ownership(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	failures, err := userLoginFailure(c.Request.Context(), user.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"User": user, "Ownership": ownership, "LoginFailures": failures})
+}
+
+// @Summary Смена роли пользователя
+// @Description Назначает пользователю глобальную роль User или Admin. Сессии пользователя завершаются, чтобы новая роль действовала сразу. Последнего администратора понизить нельзя.
+// @Tags Администрирование
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param request body updateUserRoleRequest true "Новая роль"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Роль изменена"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 409 {object} map[string]string "Нельзя понизить последнего администратора"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/users/{id}/role [put]
+func updateUserRole(c *gin.Context) {
+	user := adminTargetUser(c)
+	if user == nil {
+		return
+	}
+
+	var req updateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role, allowed values are: User, Admin"})
+		return
+	}
+
+	before := *user
+	user.Role = req.Role
+	err := updateUserAndRevokeSessions(c.Request.Context(), user)
+	if errors.Is(err, errLastAdmin) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot demote the last admin"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "user.role", "user", user.ID, 0, &before, user)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Роль пользователя успешно изменена", "User": user})
+}
+
+// @Summary Блокировка пользователя
+// @Description Блокирует пользователя: он не может войти, его сессии завершаются, персональные токены перестают действовать. Последнего администратора заблокировать нельзя.
+// @Tags Администрирование
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Пользователь заблокирован"
+// @Failure 400 {object} map[string]string "Некорректный ID пользователя"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 409 {object} map[string]string "Нельзя заблокировать последнего администратора"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/users/{id}/disable [post]
+func disableUser(c *gin.Context) {
+	user := adminTargetUser(c)
+	if user == nil {
+		return
+	}
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Пользователь уже заблокирован", "User": user})
+		return
+	}
+
+	before := *user
+	now := time.Now()
+	user.DisabledAt = &now
+	err := updateUserAndRevokeSessions(c.Request.Context(), user)
+	if errors.Is(err, errLastAdmin) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot disable the last admin"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "user.disable", "user", user.ID, 0, &before, user)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пользователь успешно заблокирован", "User": user})
+}
+
+// @Summary Разблокировка пользователя
+// @Description Снимает блокировку, пользователь снова может войти. Только для администраторов.
+// @Tags Администрирование
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Пользователь разблокирован"
+// @Failure 400 {object} map[string]string "Некорректный ID пользователя"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/users/{id}/enable [post]
+func enableUser(c *gin.Context) {
+	user := adminTargetUser(c)
+	if user == nil {
+		return
+	}
+
+	before := *user
+	user.DisabledAt = nil
+	if err := store.Users.Update(c.Request.Context(), user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "user.enable", "user", user.ID, 0, &before, user)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пользователь успешно разблокирован", "User": user})
+}
+
+// @Summary Снятие блокировки входа
+// @Description Сбрасывает счётчик неудачных попыток входа пользователя и снимает временную блокировку входа после перебора пароля. Блокировка по IP не снимается, она истекает сама.
+// @Tags Администрирование
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Блокировка снята"
+// @Failure 400 {object} map[string]string "Некорректный ID пользователя"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/users/{id}/unlock [post]
+func unlockUser(c *gin.Context) {
+	user := adminTargetUser(c)
+	if user == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	failure, err := userLoginFailure(ctx, user.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err := resetLoginFailures(ctx, user.Username); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	auditEvent(c, "login.unlock", gin.H{"username": user.Username})
+	recordAudit(c, "user.unlock", "user", user.ID, 0, gin.H{"login_failures": failure}, gin.H{"login_failures": nil})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Блокировка входа снята"})
+}
+
+// @Summary Принудительный сброс пароля
+// @Description Заменяет пароль пользователя временным, завершает его сессии и отзывает персональные токены. Временный пароль возвращается один раз, администратор передаёт его пользователю; войти с ним нельзя, пока пользователь не задаст новый пароль через /auth/change-password.
+// @Tags Администрирование
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Пароль сброшен, возвращается временный пароль"
+// @Failure 400 {object} map[string]string "Некорректный ID пользователя"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /admin/users/{id}/reset-password [post]
+func resetUserPassword(c *gin.Context) {
+	user := adminTargetUser(c)
+	if user == nil {
+		return
+	}
+
+	temporary, err := newTemporaryPassword()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate password"})
+		return
+	}
+	hashed, err := hashPassword(temporary)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	before := *user
+	user.Password = hashed
+	user.MustChangePassword = true
+	// Пароль сбрасывают, когда учётная запись могла попасть в чужие руки, поэтому
+	// вместе с сессиями отзываются и персональные токены
+	ctx := c.Request.Context()
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Users.Update(ctx, user); err != nil {
+			return err
+		}
+		if _, err := tx.Sessions.RevokeAll(ctx, user.ID); err != nil {
+			return err
+		}
+		_, err := tx.PersonalTokens.RevokeAll(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "user.reset_password", "user", user.ID, 0, &before, user)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пароль сброшен. Передайте пользователю временный пароль, при входе он должен будет задать новый", "TemporaryPassword": temporary})
+}
+
+// @Summary Отключение 2FA пользователя
+// @Description Отключает двухфакторную аутентификацию пользователя, потерявшего доступ к приложению-аутентификатору и кодам восстановления, и завершает его сессии. Если 2FA для него обязательна, после входа он сможет только подключить её заново.
+// @Tags Администрирование
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "2FA отключена"
+// @Failure 400 {object} map[string]string "Некорректный ID пользователя или 2FA не включена"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/users/{id}/2fa [delete]
+func resetUserTwoFactor(c *gin.Context) {
+	user := adminTargetUser(c)
+	if user == nil {
+		return
+	}
+	if user.TOTPEnabledAt == nil && user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	before := *user
+	err := store.Transaction(ctx, func(tx *Store) error {
+		if err := clearTwoFactor(ctx, tx, user); err != nil {
+			return err
+		}
+		_, err := tx.Sessions.RevokeAll(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "user.reset_2fa", "user", user.ID, 0, &before, user)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Двухфакторная аутентификация пользователя отключена", "User": user})
+}
+
+// @Summary Удаление пользователя
+// @Description Удаляет пользователя вместе с его сессиями и токенами. Если пользователь владеет проектами или назначен на задачи, нужно указать reassign_to: этот пользователь станет владельцем проектов и исполнителем задач. Последнего администратора удалить нельзя.
+// @Tags Администрирование
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param reassign_to query int false "ID пользователя, которому передаются проекты и задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Пользователь удалён"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 409 {object} map[string]interface{} "Нужно указать reassign_to или это последний администратор"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/users/{id} [delete]
+func deleteUser(c *gin.Context) {
+	user := adminTargetUser(c)
+	if user == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	var reassignTo uint
+	if raw := c.Query("reassign_to"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 || uint(id) == user.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reassign_to"})
+			return
+		}
+		target, err := store.Users.GetByID(ctx, uint(id))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User to reassign to not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		if target.DisabledAt != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot reassign to a disabled user"})
+			return
+		}
+		reassignTo = target.ID
+	} else {
+		ownership, err := store.Users.Ownership(ctx, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		if ownership.Projects > 0 || ownership.Tasks > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "User owns projects or has tasks, pass reassign_to", "Ownership": ownership})
+			return
+		}
+	}
+
+	err := store.Transaction(ctx, func(tx *Store) error {
+		last, err := isLastAdmin(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if last {
+			return errLastAdmin
+		}
+		return tx.Users.Delete(ctx, user.ID, reassignTo)
+	})
+	if errors.Is(err, errLastAdmin) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot delete the last admin"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "user.delete", "user", user.ID, 0, user, gin.H{"reassigned_to": reassignTo})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пользователь успешно удалён"})
+}
+
+// CreateAdmin выполняет команду `create-admin USERNAME`: создаёт администратора или
+// назначает администратором существующего пользователя. Пароль берётся из переменной
+// ADMIN_PASSWORD, а если её нет — запрашивается в консоли (для существующего пользователя
+// без ADMIN_PASSWORD пароль не меняется).
+func CreateAdmin(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Использование: create-admin USERNAME (пароль в ADMIN_PASSWORD или вводится в консоли)")
+		os.Exit(2)
+	}
+	username := args[0]
+	if err := validateUsername(username); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(2)
+	}
+
+	initDB()
+	if err := createAdmin(username, os.Getenv("ADMIN_PASSWORD")); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+}
+
+func createAdmin(username, password string) error {
+	user, err := store.Users.GetByUsername(ctx, username)
+	if errors.Is(err, ErrNotFound) {
+		if password == "" {
+			if password, err = promptPassword(); err != nil {
+				return err
+			}
+		}
+		if err := validatePassword(password); err != nil {
+			return err
+		}
+		hashed, err := hashPassword(password)
+		if err != nil {
+			return err
+		}
+		if err := store.Users.Create(ctx, &User{Username: username, Password: hashed, Role: RoleAdmin}); err != nil {
+			return err
+		}
+		fmt.Printf("Администратор %s создан\n", username)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	user.Role = RoleAdmin
+	user.DisabledAt = nil
+	if password != "" {
+		if err := validatePassword(password); err != nil {
+			return err
+		}
+		if user.Password, err = hashPassword(password); err != nil {
+			return err
+		}
+		user.MustChangePassword = false
+	}
+	if err := updateUserAndRevokeSessions(ctx, user); err != nil {
+		return err
+	}
+	fmt.Printf("Пользователь %s назначен администратором\n", username)
+	return nil
+}
+
+// Ввод пароля в консоли: в терминале вводимые символы не отображаются
+func promptPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Пароль администратора: ")
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return string(password), nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.New("password is required (set ADMIN_PASSWORD)")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
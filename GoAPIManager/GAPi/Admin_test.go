@@ -0,0 +1,114 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Администратор, созданный командой create-admin; возвращает его access-токен
+func (s *testServer) admin(username string) string {
+	s.t.Helper()
+	if err := createAdmin(username, testPassword); err != nil {
+		s.t.Fatal(err)
+	}
+	return accessToken(s.t, s.loginTokens(username))
+}
+
+func TestAdminResetPasswordRevokesCredentials(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.admin("root")
+	bob := s.user("bobby")
+	pat, _ := s.personalToken(bob, ScopeTasksRead)
+	s.expect(http.StatusOK, http.MethodGet, "/user/tasks", pat, nil)
+
+	user, err := store.Users.GetByUsername(context.Background(), "bobby")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.expect(http.StatusOK, http.MethodPost, fmt.Sprintf("/admin/users/%d/reset-password", user.ID), admin, nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/sessions", bob, nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/tasks", pat, nil)
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/login", "", gin.H{"username": "bobby", "password": testPassword})
+}
+
+func TestAdminCannotRemoveLastAdmin(t *testing.T) {
+	s := newTestServer(t)
+	root := s.admin("root")
+	user, err := store.Users.GetByUsername(context.Background(), "root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/admin/users/%d", user.ID)
+
+	s.expect(http.StatusConflict, http.MethodPut, path+"/role", root, gin.H{"role": RoleUser})
+	s.expect(http.StatusConflict, http.MethodPost, path+"/disable", root, nil)
+	s.expect(http.StatusConflict, http.MethodDelete, path, root, nil)
+
+	// Со вторым администратором первого можно понизить, после этого последним становится второй
+	ops := s.admin("ops")
+	s.expect(http.StatusOK, http.MethodPut, path+"/role", ops, gin.H{"role": RoleUser})
+	other, err := store.Users.GetByUsername(context.Background(), "ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.expect(http.StatusConflict, http.MethodPost, fmt.Sprintf("/admin/users/%d/disable", other.ID), ops, nil)
+}
+
+// Два одновременных понижения разных администраторов: проверка и изменение выполняются
+// в одной транзакции, поэтому одно из них должно получить errLastAdmin
+func TestConcurrentAdminDemotionsKeepOneAdmin(t *testing.T) {
+	newTestServer(t)
+	ctx := context.Background()
+	var admins []*User
+	for _, name := range []string{"root", "ops"} {
+		if err := createAdmin(name, testPassword); err != nil {
+			t.Fatal(err)
+		}
+		user, err := store.Users.GetByUsername(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		admins = append(admins, user)
+	}
+
+	errs := make(chan error, len(admins))
+	for _, user := range admins {
+		go func(user *User) {
+			user.Role = RoleUser
+			errs <- updateUserAndRevokeSessions(ctx, user)
+		}(user)
+	}
+	var demoted, refused int
+	for range admins {
+		switch err := <-errs; {
+		case err == nil:
+			demoted++
+		case errors.Is(err, errLastAdmin):
+			refused++
+		default:
+			t.Fatal(err)
+		}
+	}
+	if demoted != 1 || refused != 1 {
+		t.Errorf("demoted %d, refused %d, want 1 and 1", demoted, refused)
+	}
+
+	var left []uint
+	err := store.Transaction(ctx, func(tx *Store) error {
+		var err error
+		left, err = tx.Users.LockActiveAdmins(ctx)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 1 {
+		t.Errorf("%d active admins left, want 1", len(left))
+	}
+}
@@ -0,0 +1,171 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Исполнители задачи (у задачи может быть несколько исполнителей)
+type TaskAssignee struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TaskID    uint      `gorm:"not null;uniqueIndex:idx_task_assignees_task_user" json:"task_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_task_assignees_task_user;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Тело запроса на назначение исполнителей
+type assignTaskRequest struct {
+	AssigneeID  uint   `json:"assignee_id"`
+	AssigneeIDs []uint `json:"assignee_ids"`
+}
+
+// Ошибка: исполнитель не является участником проекта
+type notProjectMemberError struct {
+	UserID uint
+}
+
+func (e *notProjectMemberError) Error() string {
+	return fmt.Sprintf("user %d is not a member of this project", e.UserID)
+}
+
+// Список исполнителей из запроса: assignee_id и assignee_ids объединяются без повторов
+func collectAssigneeIDs(assigneeID uint, assigneeIDs []uint) []uint {
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, id := range append([]uint{assigneeID}, assigneeIDs...) {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Проверка, что все исполнители состоят в проекте с правом работы над задачами (viewer не подходит)
+func validateAssignees(ctx context.Context, projectID uint, userIDs []uint) error {
+	roles, err := store.Projects.MemberRoles(ctx, projectID, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range userIDs {
+		if role, ok := roles[id]; !ok || !projectRoleAtLeast(role, ProjectRoleMember) {
+			return &notProjectMemberError{UserID: id}
+		}
+	}
+	return nil
+}
+
+// Ответ на ошибку проверки исполнителей
+func respondAssigneeError(c *gin.Context, err error) {
+	var notMember *notProjectMemberError
+	if errors.As(err, &notMember) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee must be a project member: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+}
+
+// @Summary Назначение исполнителей задачи
+// @Description Заменяет список исполнителей задачи. Исполнители должны быть участниками проекта с ролью не ниже member.
+// @Tags Задачи
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body assignTaskRequest true "Исполнители (assignee_id и/или assignee_ids)"
+// @Success 200 {object} map[string]interface{} "Исполнители назначены"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/assign [post]
+func assignTask(c *gin.Context) {
+	var req assignTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	userIDs := collectAssigneeIDs(req.AssigneeID, req.AssigneeIDs)
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one assignee is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	if err := validateAssignees(ctx, task.ProjectID, userIDs); err != nil {
+		respondAssigneeError(c, err)
+		return
+	}
+
+	// Задача до изменения (с исполнителями) для истории и журнала аудита
+	before := []Task{*task}
+	if err := store.Tasks.LoadAssignees(ctx, before); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err := loadCustomFields(ctx, before); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	task.CustomFields = before[0].CustomFields
+
+	err := store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Tasks.Update(ctx, task, userIDs); err != nil {
+			return err
+		}
+		return recordTaskChanges(ctx, tx, c, &before[0], task)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign task: " + err.Error()})
+		return
+	}
+	recordAudit(c, "task.assign", "task", task.ID, task.ProjectID, &before[0], task)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Исполнители задачи успешно назначены", "Task": task})
+}
+
+// @Summary Задачи текущего пользователя
+// @Description Возвращает все задачи, назначенные текущему пользователю, во всех проектах
+// @Tags Задачи
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Список задач"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/tasks [get]
+func getUserTasks(c *gin.Context) {
+	userID := c.GetUint("id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	tasks, err := store.Tasks.ListAssignedTo(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	if err := store.Tasks.LoadAssignees(ctx, tasks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err := loadCustomFields(ctx, tasks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Задачи": tasks})
+}
@@ -0,0 +1,281 @@
+package GoAPIManager
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Размер страницы журнала аудита
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+)
+
+// Изменение одного поля: значение до и после (null — поля не было, например при создании)
+type auditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Изменённые поля ресурса; в базе хранятся как JSON
+type auditChanges map[string]auditChange
+
+func (c auditChanges) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+func (c *auditChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case []byte:
+		return json.Unmarshal(v, c)
+	}
+	return fmt.Errorf("unsupported audit changes type %T", value)
+}
+
+// Событие журнала аудита: кто, когда и что изменил. Записи только добавляются.
+type AuditEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	// Пользователь, выполнивший действие (имя сохраняется, чтобы пережить удаление пользователя)
+	ActorID *uint  `json:"actor_id"`
+	Actor   string `gorm:"not null" json:"actor"`
+	// Действие в виде <тип ресурса>.<операция>, например task.update
+	Action       string `gorm:"not null" json:"action"`
+	ResourceType string `gorm:"not null" json:"resource_type"`
+	ResourceID   uint   `gorm:"not null" json:"resource_id"`
+	// Проект, к которому относится ресурс (null — ресурс вне проектов)
+	ProjectID *uint        `json:"project_id"`
+	Changes   auditChanges `gorm:"not null;type:text" json:"changes"`
+	IP        string       `gorm:"column:ip;not null" json:"ip"`
+	RequestID string       `gorm:"not null" json:"request_id"`
+}
+
+// Поля ресурса в том виде, в каком он отдаётся в API (скрытые поля вроде паролей не попадают)
+func auditFields(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Равны ли значения поля. Время сравнивается как момент времени: база может вернуть его
+// в другом часовом поясе, чем пришло в запросе.
+func auditEqual(a, b interface{}) bool {
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok {
+		at, aerr := time.Parse(time.RFC3339Nano, as)
+		bt, berr := time.Parse(time.RFC3339Nano, bs)
+		if aerr == nil && berr == nil {
+			return at.Equal(bt)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Поля, которые отличаются у before и after. before равен nil при создании ресурса,
+// after — при удалении.
+func auditDiff(before, after interface{}) (auditChanges, error) {
+	b, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := auditChanges{}
+	for k, v := range a {
+		if old, ok := b[k]; !ok || !auditEqual(old, v) {
+			changes[k] = auditChange{Before: old, After: v}
+		}
+	}
+	for k, v := range b {
+		if _, ok := a[k]; !ok {
+			changes[k] = auditChange{Before: v}
+		}
+	}
+	return changes, nil
+}
+
+// Запись изменения в журнал аудита. before — ресурс до изменения (nil при создании),
+// after — после (nil при удалении), projectID — проект ресурса (0 — вне проектов).
+// Изменение уже выполнено, поэтому ошибка записи не прерывает запрос, а попадает в лог.
+func recordAudit(c *gin.Context, action, resourceType string, resourceID, projectID uint, before, after interface{}) {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		log.Printf("[AUDIT ERROR] %s %s %d: %v", action, resourceType, resourceID, err)
+		return
+	}
+
+	event := AuditEvent{
+		CreatedAt:    time.Now().UTC(),
+		Actor:        c.GetString("username"),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Changes:      changes,
+		IP:           c.ClientIP(),
+		RequestID:    c.GetString("request_id"),
+	}
+	if actorID := c.GetUint("id"); actorID != 0 {
+		event.ActorID = &actorID
+	}
+	if projectID != 0 {
+		event.ProjectID = &projectID
+	}
+
+	if err := store.Audit.Create(c.Request.Context(), &event); err != nil {
+		log.Printf("[AUDIT ERROR] %s %s %d: %v", action, resourceType, resourceID, err)
+	}
+}
+
+// Фильтр журнала аудита из параметров запроса (actor, actor_id, action, from, to, page, page_size).
+// При некорректных параметрах отвечает 400 и возвращает false.
+func auditFilterFromQuery(c *gin.Context) (AuditFilter, bool) {
+	var filter AuditFilter
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page"})
+		return filter, false
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultAuditPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxAuditPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid page_size, allowed values are 1-%d", maxAuditPageSize)})
+		return filter, false
+	}
+	filter.Offset = (page - 1) * pageSize
+	filter.Limit = pageSize
+
+	if raw := c.Query("actor_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor_id"})
+			return filter, false
+		}
+		filter.ActorID = uint(id)
+	}
+	filter.Actor = strings.TrimSpace(c.Query("actor"))
+	filter.Action = strings.TrimSpace(c.Query("action"))
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + p.name + ", expected RFC 3339 time (2006-01-02T15:04:05Z)"})
+			return filter, false
+		}
+		*p.dst = t.UTC()
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be earlier than to"})
+		return filter, false
+	}
+	return filter, true
+}
+
+// Ответ со страницей журнала аудита
+func respondAuditEvents(c *gin.Context, filter AuditFilter) {
+	events, total, err := store.Audit.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Events": events, "Total": total, "Page": filter.Offset/filter.Limit + 1, "PageSize": filter.Limit})
+}
+
+// @Summary Журнал аудита
+// @Description Возвращает изменения во всём сервисе (новые первыми): кто, когда и что изменил, с изменёнными полями до и после, IP и идентификатором запроса. Только для администраторов.
+// @Tags Администрирование
+// @Produce json
+// @Param actor query string false "Имя пользователя, выполнившего действие"
+// @Param actor_id query int false "ID пользователя, выполнившего действие"
+// @Param action query string false "Действие (task.update) или все действия с ресурсом (task.)"
+// @Param project_id query int false "ID проекта"
+// @Param from query string false "Начало периода, RFC 3339 (включительно)"
+// @Param to query string false "Конец периода, RFC 3339 (не включительно)"
+// @Param page query int false "Номер страницы (с 1)"
+// @Param page_size query int false "Размер страницы (по умолчанию 50, не больше 200)"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "События и общее количество"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/audit [get]
+func listAuditEvents(c *gin.Context) {
+	filter, ok := auditFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	if raw := c.Query("project_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+		filter.ProjectID = uint(id)
+	}
+
+	respondAuditEvents(c, filter)
+}
+
+// @Summary Журнал аудита проекта
+// @Description Возвращает изменения проекта, его задач, участников и файлов (новые первыми). Доступно мейнтейнерам и владельцам проекта.
+// @Tags Проекты
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param actor query string false "Имя пользователя, выполнившего действие"
+// @Param actor_id query int false "ID пользователя, выполнившего действие"
+// @Param action query string false "Действие (task.update) или все действия с ресурсом (task.)"
+// @Param from query string false "Начало периода, RFC 3339 (включительно)"
+// @Param to query string false "Конец периода, RFC 3339 (не включительно)"
+// @Param page query int false "Номер страницы (с 1)"
+// @Param page_size query int false "Размер страницы (по умолчанию 50, не больше 200)"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "События и общее количество"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/audit [get]
+func getProjectAudit(c *gin.Context) {
+	filter, ok := auditFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	// Проект :id (найден в permissionMiddleware)
+	filter.ProjectID = c.MustGet("project").(*Project).ID
+
+	respondAuditEvents(c, filter)
+}
@@ -0,0 +1,99 @@
+package GoAPIManager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// События журнала аудита с действием action
+func auditEvents(t *testing.T, action string) []AuditEvent {
+	t.Helper()
+	var events []AuditEvent
+	if err := db.Where("action = ?", action).Order("id").Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestAuditPersonalTokens(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	raw, id := s.personalToken(alice, ScopeTasksRead)
+	s.expect(http.StatusOK, http.MethodDelete, fmt.Sprintf("/user/tokens/%d", id), alice, nil)
+
+	for _, action := range []string{"personal_token.create", "personal_token.revoke"} {
+		events := auditEvents(t, action)
+		if len(events) != 1 || events[0].ResourceID != id || events[0].Actor != "alice" {
+			t.Fatalf("%s events = %+v", action, events)
+		}
+		changes := jsonString(t, events[0].Changes)
+		if !strings.Contains(changes, `"ci"`) || !strings.Contains(changes, ScopeTasksRead) {
+			t.Errorf("%s changes = %s, want name and scopes", action, changes)
+		}
+		if strings.Contains(changes, raw[:personalTokenPrefixLen]) || strings.Contains(changes, hashToken(raw)) {
+			t.Errorf("%s changes expose the token: %s", action, changes)
+		}
+	}
+}
+
+func TestAuditEmailChange(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	update := gin.H{"email": "alice@example.com", "password": testPassword}
+
+	s.expect(http.StatusOK, http.MethodPut, "/user/email", alice, update)
+	// Повторное письмо на тот же адрес не попадает в журнал
+	s.expect(http.StatusOK, http.MethodPut, "/user/email", alice, update)
+
+	events := auditEvents(t, "user.update_email")
+	if len(events) != 1 {
+		t.Fatalf("user.update_email events = %+v, want 1", events)
+	}
+	if changes := jsonString(t, events[0].Changes); !strings.Contains(changes, "alice@example.com") {
+		t.Errorf("changes = %s, want the new email", changes)
+	}
+}
+
+// Отказ в изменении (последний администратор) не попадает в журнал, успешное изменение — с прежним и новым значением
+func TestAuditAdminRoleChange(t *testing.T) {
+	s := newTestServer(t)
+	root := s.admin("root")
+	s.user("bobby")
+	user := func(name string) string {
+		u, err := store.Users.GetByUsername(context.Background(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fmt.Sprintf("/admin/users/%d/role", u.ID)
+	}
+
+	s.expect(http.StatusConflict, http.MethodPut, user("root"), root, gin.H{"role": RoleUser})
+	if events := auditEvents(t, "user.role"); len(events) != 0 {
+		t.Fatalf("refused demotion audited: %+v", events)
+	}
+
+	s.expect(http.StatusOK, http.MethodPut, user("bobby"), root, gin.H{"role": RoleAdmin})
+	events := auditEvents(t, "user.role")
+	if len(events) != 1 || events[0].Actor != "root" || events[0].ProjectID != nil {
+		t.Fatalf("user.role events = %+v", events)
+	}
+	changes := jsonString(t, events[0].Changes)
+	if !strings.Contains(changes, RoleUser) || !strings.Contains(changes, RoleAdmin) || strings.Contains(changes, "password") {
+		t.Errorf("changes = %s, want the old and new role only", changes)
+	}
+}
+
+func jsonString(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,111 @@
+package GoAPIManager
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Run разбирает командную строку вида: [команда ...] [флаги]
+//
+//	(без команды) | serve  запуск сервера
+//	migrate up|down|status|force  управление схемой базы данных
+//	keys init|generate|list|retire  управление ключами подписи JWT
+//	create-admin USERNAME  создание первого администратора
+//	config print           вывод итоговой конфигурации (секреты скрыты)
+func Run(args []string) {
+	// Команда — ведущие аргументы без "-", всё остальное — флаги конфигурации
+	var command []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = append(command, args[0])
+		args = args[1:]
+	}
+
+	name := "server"
+	if len(command) > 0 {
+		name = "server " + strings.Join(command, " ")
+	}
+
+	config, err := LoadConfig(name, args)
+	if errors.Is(err, flag.ErrHelp) {
+		usage()
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка конфигурации:", err)
+		os.Exit(2)
+	}
+	cfg = config
+
+	if len(command) == 0 {
+		command = []string{"serve"}
+	}
+
+	switch command[0] {
+	case "serve":
+		mustValidateConfig()
+		Controller()
+	case "migrate":
+		mustValidateConfig()
+		Migrate(command[1:])
+	case "keys":
+		mustValidateConfig()
+		Keys(command[1:])
+	case "create-admin":
+		mustValidateConfig()
+		CreateAdmin(command[1:])
+	case "config":
+		if len(command) != 2 || command[1] != "print" {
+			usage()
+			os.Exit(2)
+		}
+		if err := printConfig(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, "Ошибка:", err)
+			os.Exit(1)
+		}
+		// Показываем проблемы конфигурации, но только после самой конфигурации
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintln(os.Stderr, "Конфигурация некорректна:\n"+err.Error())
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// Остановка с ошибкой, если конфигурация некорректна
+func mustValidateConfig() {
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "Конфигурация некорректна:\n"+err.Error())
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Использование: server [команда] [флаги]
+
+Команды:
+  serve                  запустить сервер (по умолчанию)
+  migrate up [N]         применить ожидающие миграции
+  migrate down [N]       откатить миграции
+  migrate status         показать состояние миграций
+  migrate force VERSION  выставить версию схемы вручную
+  keys init              создать ключ подписи JWT, если ключей ещё нет
+  keys generate          создать новый активный ключ (ротация)
+  keys list              показать ключи подписи
+  keys retire KID        вывести старый ключ из обращения
+  create-admin USERNAME  создать администратора или назначить им существующего
+                         пользователя (пароль в ADMIN_PASSWORD или вводится в консоли)
+  config print           показать итоговую конфигурацию (секреты скрыты)
+
+Источники конфигурации (по возрастанию приоритета): значения по умолчанию,
+YAML-файл (-config или CONFIG_FILE), переменные окружения, флаги.
+
+Флаги:`)
+	fs := newConfigFlagSet("server", defaultConfig(), map[string]string{})
+	fs.SetOutput(os.Stderr)
+	fs.PrintDefaults()
+}
@@ -0,0 +1,373 @@
+package GoAPIManager
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Максимальная длина комментария в символах
+const maxCommentLength = 10000
+
+// Размер страницы упоминаний
+const (
+	defaultMentionsPageSize = 20
+	maxMentionsPageSize     = 100
+)
+
+// Комментарий к задаче. Текст в Markdown хранится как есть, его отображает клиент.
+type Comment struct {
+	ID     uint `gorm:"primaryKey" json:"id"`
+	TaskID uint `gorm:"not null" json:"task_id"`
+	// Автор (null — пользователь удалён)
+	AuthorID  *uint      `json:"author_id"`
+	Body      string     `gorm:"not null" json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at"`
+	// Удалённый комментарий остаётся в базе, но не показывается
+	DeletedAt *time.Time `json:"-"`
+}
+
+func (Comment) TableName() string {
+	return "task_comments"
+}
+
+// Упоминание участника проекта в комментарии
+type CommentMention struct {
+	ID        uint `gorm:"primaryKey"`
+	CommentID uint `gorm:"not null"`
+	UserID    uint `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// Комментарий в ответах API: с именем автора и упомянутыми пользователями
+type commentView struct {
+	ID        uint       `json:"id"`
+	TaskID    uint       `json:"task_id"`
+	AuthorID  *uint      `json:"author_id"`
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at"`
+	Mentions  []string   `json:"mentions" gorm:"-"`
+}
+
+// Упоминание текущего пользователя в ответе /user/mentions
+type mentionView struct {
+	ID        uint       `json:"id"`
+	CommentID uint       `json:"comment_id"`
+	ProjectID uint       `json:"project_id"`
+	TaskID    uint       `json:"task_id"`
+	TaskTitle string     `json:"task_title"`
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at"`
+}
+
+// Тело запроса на создание или изменение комментария
+type commentRequest struct {
+	Body string `json:"body"`
+}
+
+// Упоминание @username (имя пользователя — как при регистрации)
+var mentionPattern = regexp.MustCompile(`@([a-zA-Z0-9]{3,20})`)
+
+// Может ли символ быть частью слова вокруг упоминания (например, в email user@example.com)
+func isMentionWordChar(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '.' || b == '_' || b == '-'
+}
+
+// Имена пользователей, упомянутых в тексте (в нижнем регистре, без повторов)
+func parseMentions(body string) []string {
+	var names []string
+	for _, m := range mentionPattern.FindAllStringSubmatchIndex(body, -1) {
+		start, end := m[0], m[1]
+		if start > 0 && isMentionWordChar(body[start-1]) {
+			continue
+		}
+		// Имя длиннее допустимого или продолжается после упоминания
+		if end < len(body) && isMentionWordChar(body[end]) && body[end] != '.' {
+			continue
+		}
+		name := strings.ToLower(body[m[2]:m[3]])
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Упомянутые в тексте участники проекта (ID и имена). Упоминания пользователей не из
+// проекта и самого автора не учитываются.
+func resolveMentions(ctx context.Context, projectID, authorID uint, body string) ([]uint, []string, error) {
+	names := parseMentions(body)
+	if len(names) == 0 {
+		return nil, []string{}, nil
+	}
+
+	members, err := store.Projects.ListMembers(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]projectMemberView, len(members))
+	for _, m := range members {
+		byName[strings.ToLower(m.Username)] = m
+	}
+
+	ids := []uint{}
+	usernames := []string{}
+	for _, name := range names {
+		member, ok := byName[name]
+		if !ok || member.UserID == authorID {
+			continue
+		}
+		ids = append(ids, member.UserID)
+		usernames = append(usernames, member.Username)
+	}
+	return ids, usernames, nil
+}
+
+// Проверка текста комментария; при ошибке отвечает 400 и возвращает false
+func bindCommentBody(c *gin.Context) (string, bool) {
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return "", false
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment body is required"})
+		return "", false
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment body cannot exceed 10000 characters"})
+		return "", false
+	}
+	return body, true
+}
+
+// Комментарий из пути, принадлежащий задаче из пути (найдены в permissionMiddleware).
+// Если комментарий относится к другой задаче проекта, отвечает 404 и возвращает nil.
+func pathComment(c *gin.Context) (*Task, *Comment) {
+	task := c.MustGet("task").(*Task)
+	comment := c.MustGet("comment").(*Comment)
+	if comment.TaskID != task.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return nil, nil
+	}
+	return task, comment
+}
+
+// Комментарий в ответе API
+func newCommentView(comment *Comment, author string, mentions []string) commentView {
+	return commentView{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		AuthorID:  comment.AuthorID,
+		Author:    author,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  comment.EditedAt,
+		Mentions:  mentions,
+	}
+}
+
+// @Summary Комментарии к задаче
+// @Description Возвращает комментарии к задаче (старые первыми) с автором и упомянутыми пользователями. Удалённые комментарии не возвращаются.
+// @Tags Комментарии
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Комментарии"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/comments [get]
+func getTaskComments(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	comments, err := store.Comments.ListForTask(c.Request.Context(), task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"TaskID": task.ID, "Comments": comments})
+}
+
+// @Summary Добавление комментария
+// @Description Добавляет комментарий к задаче. Текст в Markdown, до 10000 символов. Упомянутые через @username участники проекта увидят комментарий в /user/mentions.
+// @Tags Комментарии
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body commentRequest true "Текст комментария"
+// @Success 201 {object} map[string]interface{} "Комментарий добавлен"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/comments [post]
+func createTaskComment(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	body, ok := bindCommentBody(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	mentionIDs, mentions, err := resolveMentions(ctx, task.ProjectID, c.GetUint("id"), body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Комментарий сохраняется вместе с упоминаниями и событием в ленте проекта
+	comment := Comment{TaskID: task.ID, AuthorID: requestActor(c), Body: body, CreatedAt: time.Now()}
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Comments.Create(ctx, &comment, mentionIDs); err != nil {
+			return err
+		}
+		return recordActivity(ctx, tx, c, task.ProjectID, task.ID, ActivityCommented,
+			gin.H{"title": task.Title, "comment_id": comment.ID})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add comment: " + err.Error()})
+		return
+	}
+	recordAudit(c, "comment.create", "comment", comment.ID, task.ProjectID, nil, &comment)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Комментарий успешно добавлен", "Comment": newCommentView(&comment, c.GetString("username"), mentions)})
+}
+
+// @Summary Изменение комментария
+// @Description Изменяет текст комментария. Изменить можно только свой комментарий; время правки сохраняется в edited_at, упоминания пересчитываются.
+// @Tags Комментарии
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param comment_id path int true "ID комментария"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body commentRequest true "Новый текст комментария"
+// @Success 200 {object} map[string]interface{} "Комментарий изменён"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Чужой комментарий"
+// @Failure 404 {object} map[string]string "Комментарий не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/comments/{comment_id} [put]
+func updateTaskComment(c *gin.Context) {
+	task, comment := pathComment(c)
+	if comment == nil {
+		return
+	}
+	if comment.AuthorID == nil || *comment.AuthorID != c.GetUint("id") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own comments"})
+		return
+	}
+
+	body, ok := bindCommentBody(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	mentionIDs, mentions, err := resolveMentions(ctx, task.ProjectID, c.GetUint("id"), body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	before := *comment
+	now := time.Now()
+	comment.Body = body
+	comment.EditedAt = &now
+	if err := store.Comments.Update(ctx, comment, mentionIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment: " + err.Error()})
+		return
+	}
+	recordAudit(c, "comment.update", "comment", comment.ID, task.ProjectID, &before, comment)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Комментарий успешно изменён", "Comment": newCommentView(comment, c.GetString("username"), mentions)})
+}
+
+// @Summary Удаление комментария
+// @Description Удаляет комментарий (он перестаёт показываться, но остаётся в журнале аудита). Удалить можно свой комментарий; мейнтейнеры и владельцы проекта могут удалять любые.
+// @Tags Комментарии
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param comment_id path int true "ID комментария"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Комментарий удалён"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Комментарий не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/comments/{comment_id} [delete]
+func deleteTaskComment(c *gin.Context) {
+	task, comment := pathComment(c)
+	if comment == nil {
+		return
+	}
+	own := comment.AuthorID != nil && *comment.AuthorID == c.GetUint("id")
+	if !own && !can(c, PermCommentModerate) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot delete this comment"})
+		return
+	}
+
+	if err := store.Comments.Delete(c.Request.Context(), comment.ID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment: " + err.Error()})
+		return
+	}
+	recordAudit(c, "comment.delete", "comment", comment.ID, task.ProjectID, comment, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Комментарий успешно удалён"})
+}
+
+// @Summary Упоминания текущего пользователя
+// @Description Возвращает комментарии, в которых упомянут текущий пользователь (новые первыми), в проектах, где он состоит. Следующая страница запрашивается с cursor из NextCursor предыдущего ответа; пустой NextCursor — упоминаний больше нет.
+// @Tags Комментарии
+// @Produce json
+// @Param cursor query string false "Курсор следующей страницы (NextCursor)"
+// @Param limit query int false "Размер страницы (по умолчанию 20, не больше 100)"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Упоминания и курсор следующей страницы"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/mentions [get]
+func getUserMentions(c *gin.Context) {
+	before, limit, ok := cursorPage(c, defaultMentionsPageSize, maxMentionsPageSize)
+	if !ok {
+		return
+	}
+
+	mentions, err := store.Comments.ListMentions(c.Request.Context(), c.GetUint("id"), MentionFilter{Before: before, Limit: limit + 1})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	nextCursor := ""
+	if len(mentions) > limit {
+		mentions = mentions[:limit]
+		nextCursor = formatCursor(mentions[limit-1].ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Mentions": mentions, "NextCursor": nextCursor})
+}
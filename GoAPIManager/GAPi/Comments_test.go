@@ -0,0 +1,89 @@
+package GoAPIManager
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseMentions(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		want []string
+	}{
+		{"hi @alice", []string{"alice"}},
+		{"@alice, @bobby and @carol.", []string{"alice", "bobby", "carol"}},
+		{"(@bobby) @bobby @BOBBY", []string{"bobby"}},
+		{"@Alice then @alice", []string{"alice"}},
+		{"write to bob@example.com", nil},
+		{"x.@alice", nil},
+		{"@ab is too short", nil},
+		{"@abcdefghijklmnopqrstu is too long", nil},
+		{"@alice_x @alice-x", nil},
+		{"no mentions @ all", nil},
+		{"", nil},
+	} {
+		if got := parseMentions(tc.body); !slices.Equal(got, tc.want) {
+			t.Errorf("parseMentions(%q) = %v, want %v", tc.body, got, tc.want)
+		}
+	}
+}
+
+// Упоминания текущего пользователя (комментарии новыми первыми)
+func (s *testServer) mentions(token, query string) ([]interface{}, string) {
+	s.t.Helper()
+	out := s.expect(http.StatusOK, http.MethodGet, "/user/mentions"+query, token, nil)
+	list, _ := out["Mentions"].([]interface{})
+	cursor, _ := out["NextCursor"].(string)
+	return list, cursor
+}
+
+func TestCommentMentions(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	bob := s.user("bobby")
+	carol := s.user("carol")
+	projectID := s.project(alice, "Alpha")
+	s.addMember(alice, projectID, "bobby", ProjectRoleMember)
+	comments := fmt.Sprintf("/projects/%d/tasks/%d/comments", projectID, s.task(alice, projectID, "First"))
+
+	// Неизвестные пользователи, не участники проекта, сам автор и повторы не учитываются
+	out := s.expect(http.StatusCreated, http.MethodPost, comments, alice, gin.H{"body": "@bobby @Bobby, @carol @ghost @alice look"})
+	if mentions := field(t, out, "Comment", "mentions"); !jsonEqual(t, mentions, []string{"bobby"}) {
+		t.Errorf("mentions = %v, want [bobby]", mentions)
+	}
+	if list, _ := s.mentions(bob, ""); len(list) != 1 {
+		t.Fatalf("bobby mentions = %v, want 1", list)
+	}
+	if list, _ := s.mentions(carol, ""); len(list) != 0 {
+		t.Errorf("carol (not a member) mentions = %v", list)
+	}
+	if list, _ := s.mentions(alice, ""); len(list) != 0 {
+		t.Errorf("author mentions = %v", list)
+	}
+
+	// После правки текста упоминания пересчитываются
+	commentID := uint(field(t, out, "Comment", "id").(float64))
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("%s/%d", comments, commentID), alice, gin.H{"body": "never mind"})
+	if list, _ := s.mentions(bob, ""); len(list) != 0 {
+		t.Errorf("bobby mentions after edit = %v", list)
+	}
+
+	for i := range 3 {
+		s.expect(http.StatusCreated, http.MethodPost, comments, alice, gin.H{"body": fmt.Sprintf("@bobby ping %d", i)})
+	}
+	first, cursor := s.mentions(bob, "?limit=2")
+	if len(first) != 2 || cursor == "" {
+		t.Fatalf("first page: %d mentions, cursor %q", len(first), cursor)
+	}
+	rest, cursor := s.mentions(bob, "?limit=2&cursor="+cursor)
+	if len(rest) != 1 || cursor != "" {
+		t.Fatalf("second page: %d mentions, cursor %q", len(rest), cursor)
+	}
+	if body := rest[0].(map[string]interface{})["body"]; body != "@bobby ping 0" {
+		t.Errorf("oldest mention = %v", body)
+	}
+}
@@ -0,0 +1,475 @@
+package GoAPIManager
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Конфигурация сервиса. Значения собираются по слоям (каждый следующий перекрывает предыдущий):
+// значения по умолчанию → YAML-файл → переменные окружения → флаги командной строки.
+//
+// Теги полей: yaml — ключ в файле, env — переменная окружения, flag — имя флага,
+// usage — описание флага, secret:"true" — значение скрывается в `config print`.
+type Config struct {
+	Server        ServerConfig       `yaml:"server"`
+	Database      DatabaseConfig     `yaml:"database"`
+	Auth          AuthConfig         `yaml:"auth"`
+	Storage       StorageConfig      `yaml:"storage"`
+	Mail          MailConfig         `yaml:"mail"`
+	OIDC          OIDCConfig         `yaml:"oidc"`
+	Scheduler     SchedulerConfig    `yaml:"scheduler"`
+	Notifications NotificationConfig `yaml:"notifications"`
+}
+
+type ServerConfig struct {
+	Addr         string `yaml:"addr" env:"SERVER_ADDR" flag:"addr" usage:"адрес HTTP-сервера"`
+	RateLimit    int    `yaml:"rate_limit" env:"RATE_LIMIT" flag:"rate-limit" usage:"лимит запросов в минуту с одного IP"`
+	MaxBodySize  int64  `yaml:"max_body_size" env:"MAX_BODY_SIZE" flag:"max-body-size" usage:"максимальный размер тела запроса (байт), кроме загрузки файлов"`
+	LogFile      string `yaml:"log_file" env:"LOG_FILE" flag:"log-file" usage:"файл журнала запросов"`
+	GinLogFile   string `yaml:"gin_log_file" env:"GIN_LOG_FILE" flag:"gin-log-file" usage:"файл журнала Gin"`
+	AuditLogFile string `yaml:"audit_log_file" env:"AUDIT_LOG_FILE" flag:"audit-log-file" usage:"файл журнала аудита событий безопасности"`
+}
+
+type DatabaseConfig struct {
+	Driver   string `yaml:"driver" env:"DB_DRIVER" flag:"db-driver" usage:"драйвер базы данных (postgres, sqlite)"`
+	Path     string `yaml:"path" env:"DB_PATH" flag:"db-path" usage:"файл базы данных SQLite"`
+	Host     string `yaml:"host" env:"DB_HOST" flag:"db-host" usage:"хост PostgreSQL"`
+	Port     int    `yaml:"port" env:"DB_PORT" flag:"db-port" usage:"порт PostgreSQL"`
+	User     string `yaml:"user" env:"DB_USER" flag:"db-user" usage:"пользователь PostgreSQL"`
+	Password string `yaml:"password" env:"DB_PASSWORD" flag:"db-password" usage:"пароль PostgreSQL" secret:"true"`
+	Name     string `yaml:"name" env:"DB_NAME" flag:"db-name" usage:"имя базы данных"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE" flag:"db-sslmode" usage:"режим SSL (disable, require, verify-full)"`
+}
+
+type AuthConfig struct {
+	KeysDir              string        `yaml:"keys_dir" env:"JWT_KEYS_DIR" flag:"jwt-keys-dir" usage:"каталог ключей подписи JWT"`
+	SigningAlg           string        `yaml:"signing_alg" env:"JWT_SIGNING_ALG" flag:"jwt-signing-alg" usage:"алгоритм новых ключей подписи (EdDSA, RS256)"`
+	AccessTokenTTL       time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL" flag:"access-token-ttl" usage:"срок жизни access-токена"`
+	RefreshTokenTTL      time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL" flag:"refresh-token-ttl" usage:"срок жизни refresh-токена"`
+	PasswordResetTTL     time.Duration `yaml:"password_reset_ttl" env:"PASSWORD_RESET_TTL" flag:"password-reset-ttl" usage:"срок действия ссылки для сброса пароля"`
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl" env:"EMAIL_VERIFICATION_TTL" flag:"email-verification-ttl" usage:"срок действия ссылки для подтверждения email"`
+	LockoutThreshold     int           `yaml:"lockout_threshold" env:"LOCKOUT_THRESHOLD" flag:"lockout-threshold" usage:"неудачных входов по имени пользователя до блокировки"`
+	IPLockoutThreshold   int           `yaml:"ip_lockout_threshold" env:"IP_LOCKOUT_THRESHOLD" flag:"ip-lockout-threshold" usage:"неудачных входов с одного IP до блокировки"`
+	LockoutWindow        time.Duration `yaml:"lockout_window" env:"LOCKOUT_WINDOW" flag:"lockout-window" usage:"через сколько после последней неудачной попытки счётчик обнуляется"`
+	LockoutDuration      time.Duration `yaml:"lockout_duration" env:"LOCKOUT_DURATION" flag:"lockout-duration" usage:"первая блокировка, каждая следующая неудачная попытка удваивает её"`
+	LockoutMaxDuration   time.Duration `yaml:"lockout_max_duration" env:"LOCKOUT_MAX_DURATION" flag:"lockout-max-duration" usage:"максимальная длительность блокировки"`
+}
+
+type MailConfig struct {
+	Driver       string `yaml:"driver" env:"MAIL_DRIVER" flag:"mail-driver" usage:"способ отправки писем (smtp, file, log)"`
+	From         string `yaml:"from" env:"MAIL_FROM" flag:"mail-from" usage:"адрес отправителя писем"`
+	AppURL       string `yaml:"app_url" env:"APP_URL" flag:"app-url" usage:"адрес приложения для ссылок в письмах"`
+	SMTPHost     string `yaml:"smtp_host" env:"SMTP_HOST" flag:"smtp-host" usage:"хост SMTP-сервера"`
+	SMTPPort     int    `yaml:"smtp_port" env:"SMTP_PORT" flag:"smtp-port" usage:"порт SMTP-сервера"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME" flag:"smtp-username" usage:"пользователь SMTP (пусто — без аутентификации)"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD" flag:"smtp-password" usage:"пароль SMTP" secret:"true"`
+	FilePath     string `yaml:"file_path" env:"MAIL_FILE" flag:"mail-file" usage:"файл, в который записываются письма (драйвер file)"`
+}
+
+// Вход через внешнего провайдера (SSO) по OpenID Connect
+type OIDCConfig struct {
+	Issuer        string   `yaml:"issuer" env:"OIDC_ISSUER" flag:"oidc-issuer" usage:"адрес провайдера OpenID Connect (пусто — вход через SSO выключен)"`
+	ClientID      string   `yaml:"client_id" env:"OIDC_CLIENT_ID" flag:"oidc-client-id" usage:"идентификатор клиента у провайдера"`
+	ClientSecret  string   `yaml:"client_secret" env:"OIDC_CLIENT_SECRET" flag:"oidc-client-secret" usage:"секрет клиента (пусто — публичный клиент, только PKCE)" secret:"true"`
+	RedirectURL   string   `yaml:"redirect_url" env:"OIDC_REDIRECT_URL" flag:"oidc-redirect-url" usage:"адрес /auth/oidc/callback этого сервиса, зарегистрированный у провайдера"`
+	Scopes        []string `yaml:"scopes" env:"OIDC_SCOPES" flag:"oidc-scopes" usage:"запрашиваемые scope через запятую"`
+	GroupsClaim   string   `yaml:"groups_claim" env:"OIDC_GROUPS_CLAIM" flag:"oidc-groups-claim" usage:"claim ID-токена со списком групп пользователя"`
+	AdminGroups   []string `yaml:"admin_groups" env:"OIDC_ADMIN_GROUPS" flag:"oidc-admin-groups" usage:"группы, участники которых получают роль Admin (пусто — роль не синхронизируется)"`
+	AllowedGroups []string `yaml:"allowed_groups" env:"OIDC_ALLOWED_GROUPS" flag:"oidc-allowed-groups" usage:"войти могут только участники этих групп (пусто — все)"`
+	AutoProvision bool     `yaml:"auto_provision" env:"OIDC_AUTO_PROVISION" flag:"oidc-auto-provision" usage:"создавать пользователя при первом входе через SSO"`
+	LinkByEmail   bool     `yaml:"link_by_email" env:"OIDC_LINK_BY_EMAIL" flag:"oidc-link-by-email" usage:"привязывать вход к пользователю с тем же email, если адрес подтверждён провайдером и в сервисе"`
+}
+
+// Фоновые задачи сервера
+type SchedulerConfig struct {
+	Interval time.Duration `yaml:"interval" env:"SCHEDULER_INTERVAL" flag:"scheduler-interval" usage:"как часто выполняются фоновые задачи (повторяющиеся задачи, напоминания)"`
+}
+
+// Напоминания о дедлайнах и доставка уведомлений
+type NotificationConfig struct {
+	Reminders           []time.Duration `yaml:"reminders" env:"REMINDERS" flag:"reminders" usage:"за сколько до дедлайна напоминать исполнителям, через запятую (пусто — без напоминаний)"`
+	OverdueInterval     time.Duration   `yaml:"overdue_interval" env:"OVERDUE_INTERVAL" flag:"overdue-interval" usage:"как часто напоминать о просроченной задаче (0 — не напоминать)"`
+	WebhookTimeout      time.Duration   `yaml:"webhook_timeout" env:"WEBHOOK_TIMEOUT" flag:"webhook-timeout" usage:"время ожидания ответа на вебхук уведомлений"`
+	WebhookAllowedHosts []string        `yaml:"webhook_allowed_hosts" env:"WEBHOOK_ALLOWED_HOSTS" flag:"webhook-allowed-hosts" usage:"хосты, на которые можно отправлять вебхуки, через запятую (пусто — любые публичные)"`
+}
+
+type StorageConfig struct {
+	UploadDir     string `yaml:"upload_dir" env:"UPLOAD_DIR" flag:"upload-dir" usage:"каталог для загруженных файлов"`
+	MaxUploadSize int64  `yaml:"max_upload_size" env:"MAX_UPLOAD_SIZE" flag:"max-upload-size" usage:"максимальный размер загружаемого файла (байт)"`
+}
+
+// Текущая конфигурация сервиса
+var cfg = defaultConfig()
+
+// Значения по умолчанию
+func defaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Addr:         ":8080",
+			RateLimit:    100,
+			MaxBodySize:  1 << 20, // 1 MB
+			LogFile:      "server.log",
+			GinLogFile:   "serverGIN.log",
+			AuditLogFile: "audit.log",
+		},
+		Database: DatabaseConfig{
+			Driver:  "postgres",
+			Path:    "gapim.db",
+			Host:    "localhost",
+			Port:    5432,
+			User:    "postgres",
+			Name:    "gapim",
+			SSLMode: "disable",
+		},
+		Auth: AuthConfig{
+			KeysDir:              "keys/",
+			SigningAlg:           "EdDSA",
+			AccessTokenTTL:       12 * time.Hour,
+			RefreshTokenTTL:      7 * 24 * time.Hour,
+			PasswordResetTTL:     time.Hour,
+			EmailVerificationTTL: 48 * time.Hour,
+			LockoutThreshold:     5,
+			IPLockoutThreshold:   20,
+			LockoutWindow:        15 * time.Minute,
+			LockoutDuration:      time.Minute,
+			LockoutMaxDuration:   time.Hour,
+		},
+		Storage: StorageConfig{
+			UploadDir:     "uploads/",
+			MaxUploadSize: 100 << 20, // 100 MB
+		},
+		Mail: MailConfig{
+			Driver:   "log",
+			From:     "noreply@localhost",
+			AppURL:   "http://localhost:8080",
+			SMTPPort: 587,
+			FilePath: "mail.log",
+		},
+		OIDC: OIDCConfig{
+			RedirectURL:   "http://localhost:8080/auth/oidc/callback",
+			Scopes:        []string{"openid", "profile", "email"},
+			GroupsClaim:   "groups",
+			AutoProvision: true,
+		},
+		Scheduler: SchedulerConfig{
+			Interval: time.Minute,
+		},
+		Notifications: NotificationConfig{
+			Reminders:       []time.Duration{24 * time.Hour, time.Hour},
+			OverdueInterval: 24 * time.Hour,
+			WebhookTimeout:  10 * time.Second,
+		},
+	}
+}
+
+// Строка подключения к PostgreSQL
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+// Строка подключения к SQLite: внешние ключи включены, при блокировке ждём до 5 секунд
+func (d DatabaseConfig) SQLiteDSN() string {
+	return d.Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+// Проверка итоговой конфигурации
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr is required"))
+	}
+	if c.Server.RateLimit <= 0 {
+		errs = append(errs, errors.New("server.rate_limit must be positive"))
+	}
+	if c.Server.MaxBodySize <= 0 {
+		errs = append(errs, errors.New("server.max_body_size must be positive"))
+	}
+	switch c.Database.Driver {
+	case "postgres":
+		if c.Database.Host == "" || c.Database.User == "" || c.Database.Name == "" {
+			errs = append(errs, errors.New("database.host, database.user and database.name are required"))
+		}
+		if c.Database.Port <= 0 || c.Database.Port > 65535 {
+			errs = append(errs, errors.New("database.port must be between 1 and 65535"))
+		}
+	case "sqlite":
+		if c.Database.Path == "" {
+			errs = append(errs, errors.New("database.path is required for sqlite"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("database.driver must be postgres or sqlite, got %q", c.Database.Driver))
+	}
+	if c.Auth.KeysDir == "" {
+		errs = append(errs, errors.New("auth.keys_dir is required"))
+	}
+	if !slices.Contains(signingAlgorithms, c.Auth.SigningAlg) {
+		errs = append(errs, fmt.Errorf("auth.signing_alg must be one of %v, got %q", signingAlgorithms, c.Auth.SigningAlg))
+	}
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+		errs = append(errs, errors.New("auth.access_token_ttl and auth.refresh_token_ttl must be positive"))
+	}
+	if c.Auth.PasswordResetTTL <= 0 || c.Auth.EmailVerificationTTL <= 0 {
+		errs = append(errs, errors.New("auth.password_reset_ttl and auth.email_verification_ttl must be positive"))
+	}
+	if c.Auth.LockoutThreshold <= 0 || c.Auth.IPLockoutThreshold <= 0 {
+		errs = append(errs, errors.New("auth.lockout_threshold and auth.ip_lockout_threshold must be positive"))
+	}
+	if c.Auth.LockoutWindow <= 0 || c.Auth.LockoutDuration <= 0 || c.Auth.LockoutMaxDuration < c.Auth.LockoutDuration {
+		errs = append(errs, errors.New("auth.lockout_window and auth.lockout_duration must be positive, auth.lockout_max_duration must not be less than auth.lockout_duration"))
+	}
+	switch c.Mail.Driver {
+	case "smtp":
+		if c.Mail.SMTPHost == "" {
+			errs = append(errs, errors.New("mail.smtp_host is required for smtp"))
+		}
+		if c.Mail.SMTPPort <= 0 || c.Mail.SMTPPort > 65535 {
+			errs = append(errs, errors.New("mail.smtp_port must be between 1 and 65535"))
+		}
+	case "file":
+		if c.Mail.FilePath == "" {
+			errs = append(errs, errors.New("mail.file_path is required for file"))
+		}
+	case "log":
+	default:
+		errs = append(errs, fmt.Errorf("mail.driver must be smtp, file or log, got %q", c.Mail.Driver))
+	}
+	if c.Mail.From == "" {
+		errs = append(errs, errors.New("mail.from is required"))
+	}
+	if c.OIDC.Issuer != "" {
+		if c.OIDC.ClientID == "" || c.OIDC.RedirectURL == "" {
+			errs = append(errs, errors.New("oidc.client_id and oidc.redirect_url are required when oidc.issuer is set"))
+		}
+		if !slices.Contains(c.OIDC.Scopes, "openid") {
+			errs = append(errs, errors.New("oidc.scopes must include openid"))
+		}
+		if c.OIDC.GroupsClaim == "" && (len(c.OIDC.AdminGroups) > 0 || len(c.OIDC.AllowedGroups) > 0) {
+			errs = append(errs, errors.New("oidc.groups_claim is required when oidc.admin_groups or oidc.allowed_groups is set"))
+		}
+	}
+	if c.Storage.UploadDir == "" {
+		errs = append(errs, errors.New("storage.upload_dir is required"))
+	}
+	if c.Storage.MaxUploadSize <= 0 {
+		errs = append(errs, errors.New("storage.max_upload_size must be positive"))
+	}
+	if c.Scheduler.Interval <= 0 {
+		errs = append(errs, errors.New("scheduler.interval must be positive"))
+	}
+	for _, d := range c.Notifications.Reminders {
+		if d <= 0 {
+			errs = append(errs, errors.New("notifications.reminders must be positive"))
+			break
+		}
+	}
+	if c.Notifications.OverdueInterval < 0 {
+		errs = append(errs, errors.New("notifications.overdue_interval must not be negative"))
+	}
+	if c.Notifications.WebhookTimeout <= 0 {
+		errs = append(errs, errors.New("notifications.webhook_timeout must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// Поле конфигурации вместе с его тегами
+type configField struct {
+	Key    string // путь в YAML, например server.addr
+	Env    string
+	Flag   string
+	Usage  string
+	Secret bool
+	Value  reflect.Value
+}
+
+// Обход всех полей конфигурации (вложенные структуры разворачиваются)
+func configFields(c *Config) []configField {
+	var fields []configField
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			key := sf.Tag.Get("yaml")
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			if sf.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), key)
+				continue
+			}
+			fields = append(fields, configField{
+				Key:    key,
+				Env:    sf.Tag.Get("env"),
+				Flag:   sf.Tag.Get("flag"),
+				Usage:  sf.Tag.Get("usage"),
+				Secret: sf.Tag.Get("secret") == "true",
+				Value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(c).Elem(), "")
+	return fields
+}
+
+// Установка значения поля из строки (переменная окружения или флаг)
+func setConfigValue(v reflect.Value, raw string) error {
+	switch v.Interface().(type) {
+	case string:
+		v.SetString(raw)
+	case int, int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case []string:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case []time.Duration:
+		var items []time.Duration
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			d, err := time.ParseDuration(item)
+			if err != nil {
+				return err
+			}
+			items = append(items, d)
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
+
+// Набор флагов командной строки. Значения флагов запоминаются и применяются последним слоем.
+func newConfigFlagSet(name string, c *Config, setFlags map[string]string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.String("config", "", "путь к YAML-файлу конфигурации (или CONFIG_FILE)")
+	fs.String("env-file", "", "файл с переменными окружения в формате .env (или ENV_FILE)")
+	for _, f := range configFields(c) {
+		if f.Flag == "" {
+			continue
+		}
+		name := f.Flag
+		usage := fmt.Sprintf("%s (%s, env %s)", f.Usage, f.Key, f.Env)
+		fs.Func(name, usage, func(raw string) error {
+			setFlags[name] = raw
+			return nil
+		})
+	}
+	return fs
+}
+
+// Загрузка конфигурации из всех источников
+func LoadConfig(name string, args []string) (*Config, error) {
+	c := defaultConfig()
+	setFlags := make(map[string]string)
+	fs := newConfigFlagSet(name, c, setFlags)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	// Файл .env только дополняет окружение, уже заданные переменные не перезаписываются
+	envFile := fs.Lookup("env-file").Value.String()
+	if envFile == "" {
+		envFile = os.Getenv("ENV_FILE")
+	}
+	if envFile != "" {
+		if err := godotenv.Load(envFile); err != nil {
+			return nil, fmt.Errorf("load env file %s: %w", envFile, err)
+		}
+	}
+
+	// YAML-файл
+	configFile := fs.Lookup("config").Value.String()
+	if configFile == "" {
+		configFile = os.Getenv("CONFIG_FILE")
+	}
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", configFile, err)
+		}
+	}
+
+	// Переменные окружения, затем флаги
+	for _, f := range configFields(c) {
+		if raw, ok := os.LookupEnv(f.Env); ok && f.Env != "" {
+			if err := setConfigValue(f.Value, raw); err != nil {
+				return nil, fmt.Errorf("env %s: %w", f.Env, err)
+			}
+		}
+	}
+	for _, f := range configFields(c) {
+		if raw, ok := setFlags[f.Flag]; ok {
+			if err := setConfigValue(f.Value, raw); err != nil {
+				return nil, fmt.Errorf("flag -%s: %w", f.Flag, err)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// Копия конфигурации со скрытыми секретами
+func (c *Config) Redacted() *Config {
+	copied := *c
+	for _, f := range configFields(&copied) {
+		if f.Secret && !f.Value.IsZero() {
+			f.Value.SetString("******")
+		}
+	}
+	return &copied
+}
+
+// Вывод итоговой конфигурации в формате YAML
+func printConfig(c *Config) error {
+	out, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
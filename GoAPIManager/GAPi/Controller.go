@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/time/rate"
 
 	_ "GoAPIManager/docs"
@@ -30,8 +29,9 @@ func getLimiter(ip string) *rate.Limiter {
 
 	// Если для IP еще нет лимитера, создаем новый
 	if _, exists := rateLimiters[ip]; !exists {
-		// 100 запросов в минуту
-		limiter := rate.NewLimiter(rate.Every(60*time.Second), 100)
+		// server.rate_limit запросов в минуту
+		perMinute := cfg.Server.RateLimit
+		limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
 		rateLimiters[ip] = limiter
 		// Удаляем лимитер через 10 минут (чтобы не хранить в памяти вечно)
 		go func() {
@@ -58,6 +58,52 @@ func rateLimitMiddleware(c *gin.Context) {
 	c.Next()
 }
 
+// Ограничение размера тела запроса. Для загрузки файлов действует отдельный лимит storage.max_upload_size.
+func bodyLimitMiddleware(c *gin.Context) {
+	limit := cfg.Server.MaxBodySize
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		// Запас на служебные части multipart-запроса
+		limit = cfg.Storage.MaxUploadSize + 1<<20
+	}
+	if c.Request.ContentLength > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+		c.Abort()
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	c.Next()
+}
+
+// Идентификатор запроса: берётся из заголовка X-Request-ID клиента или прокси (если он
+// корректный) либо создаётся заново. Возвращается в ответе и попадает в логи и журнал аудита.
+func requestIDMiddleware(c *gin.Context) {
+	id := c.GetHeader("X-Request-ID")
+	if !validRequestID(id) {
+		var err error
+		if id, err = newTokenID(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate request ID"})
+			c.Abort()
+			return
+		}
+	}
+	c.Set("request_id", id)
+	c.Header("X-Request-ID", id)
+	c.Next()
+}
+
+// Идентификатор запроса от клиента: до 64 символов из латинских букв, цифр, '-', '_' и '.'
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
 // Логирование запросов
 func requestLoggerMiddleware(c *gin.Context) {
 	start := time.Now()
@@ -84,19 +130,18 @@ func logRequest(c *gin.Context, statusCode int, message string) {
 	// Получаем токен (если есть)
 	tokenString := c.GetHeader("Authorization")
 
-	var username string
+	// Пользователь, определённый authMiddleware (по JWT или персональному токену)
+	username := c.GetString("username")
 
 	// Если токен есть, ищем пользователя
-	if tokenString != "" {
+	if username == "" && tokenString != "" {
 		const prefix = "Bearer "
 		if len(tokenString) > len(prefix) && strings.HasPrefix(tokenString, prefix) {
 			tokenString = tokenString[len(prefix):]
 		}
 
 		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return jwtKey, nil
-		})
+		token, err := parseToken(tokenString, claims)
 
 		if err == nil && token.Valid {
 			username = claims.Username
@@ -105,47 +150,113 @@ func logRequest(c *gin.Context, statusCode int, message string) {
 
 	// Формируем строку лога
 
+	requestID := c.GetString("request_id")
 	if username != "" {
-		log.Printf("[REQUEST] %s | %s | %s | %d | %s", username, c.Request.Method, c.Request.URL.Path, statusCode, message)
+		log.Printf("[REQUEST] %s | %s | %s | %d | %s | %s", username, c.Request.Method, c.Request.URL.Path, statusCode, message, requestID)
 	} else {
-		log.Printf("[REQUEST] %s | %s | %s | %d | %s", clientIP, c.Request.Method, c.Request.URL.Path, statusCode, message)
+		log.Printf("[REQUEST] %s | %s | %s | %d | %s | %s", clientIP, c.Request.Method, c.Request.URL.Path, statusCode, message, requestID)
 	}
 }
 
 func Controller() {
 	initDB()
+	// Ключи подписи токенов
+	if err := LoadSigningKeys(cfg.Auth.KeysDir); err != nil {
+		log.Fatalf("Сервер не может быть запущен: %v", err)
+	}
+	// Отправка писем (сброс пароля, подтверждение email)
+	m, err := NewMailer(cfg.Mail)
+	if err != nil {
+		log.Fatalf("Сервер не может быть запущен: %v", err)
+	}
+	SetMailer(m)
 	// Открываем лог-файл (Мои логи)
-	logFile, err := os.OpenFile("server.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	logFile, err := os.OpenFile(cfg.Server.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		log.Fatalf("Ошибка открытия log-файла: %v", err)
 	}
 	defer logFile.Close()
 	log.SetOutput(logFile)
 	// Открываем или создаём файл логов (Дефолтные логи Gin)
-	logFile2, err := os.OpenFile("serverGIN.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	logFile2, err := os.OpenFile(cfg.Server.GinLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		log.Fatalf("Ошибка открытия log-файла: %v", err)
 	}
 
 	// Перенаправляем стандартный логгер Gin в файл и в консоль
 	gin.DefaultWriter = io.MultiWriter(logFile2, os.Stdout)
+	// Журнал аудита событий безопасности (блокировки входа и т.п.)
+	auditFile, err := os.OpenFile(cfg.Server.AuditLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatalf("Ошибка открытия log-файла: %v", err)
+	}
+	defer auditFile.Close()
+	auditLogger.SetOutput(auditFile)
+
+	// Фоновые задачи: создание следующих задач повторяющихся серий, напоминания о дедлайнах
+	startScheduler(ctx, cfg.Scheduler.Interval)
 
 	// Инициализация роутера
+	r := NewRouter(store)
+
+	log.Printf("Сервер работает на %s", cfg.Server.Addr)
+	// Запуск сервера с логированием ошибок
+	if err := r.Run(cfg.Server.Addr); err != nil {
+		log.Fatalf("Ошибка запуска сервера: %v", err)
+	}
+}
+
+// NewRouter собирает роутер со всеми маршрутами поверх хранилища s.
+// Сервер и интеграционные тесты (httptest) используют один и тот же набор маршрутов.
+func NewRouter(s *Store) *gin.Engine {
+	store = s
+
 	r := gin.Default()
 
 	// Применяем Rate Limit Middleware ко всем маршрутам
+	r.Use(requestIDMiddleware)
 	r.Use(requestLoggerMiddleware)
 	r.Use(rateLimitMiddleware)
-	// Настройка rate limit (например, 10 запросов в минуту на IP)
+	r.Use(bodyLimitMiddleware)
 
 	// Маршруты для аутентификации
 	r.POST("/register", registerUser)
 	r.POST("/login", loginUser)
-	r.POST("/refresh/:id", refreshToken)
+	r.POST("/login/2fa", loginTwoFactor)
+	r.POST("/auth/refresh", refreshTokens)
+	r.POST("/auth/change-password", changePassword)
+	r.POST("/password/forgot", forgotPassword)
+	r.POST("/password/reset", resetPassword)
+	r.POST("/email/verify", verifyEmail)
+	r.GET("/auth/oidc/login", oidcLogin)
+	r.GET("/auth/oidc/callback", oidcCallback)
+	r.GET("/.well-known/jwks.json", getJWKS)
 
-	// Маршруты для проектов
 	auth := r.Group("/")
-	auth.Use(authMiddleware)
+	auth.Use(authMiddleware, permissionMiddleware)
+
+	// Маршруты для сессий
+	auth.POST("/logout", logout)
+	auth.POST("/logout-all", logoutAll)
+	auth.GET("/user/sessions", getUserSessions)
+	auth.DELETE("/user/sessions/:id", deleteUserSession)
+
+	// Email пользователя
+	auth.PUT("/user/email", updateEmail)
+
+	// Маршруты для двухфакторной аутентификации
+	auth.GET("/user/2fa", getTwoFactorStatus)
+	auth.POST("/user/2fa/enroll", enrollTwoFactor)
+	auth.POST("/user/2fa/confirm", confirmTwoFactor)
+	auth.POST("/user/2fa/recovery-codes", regenerateRecoveryCodes)
+	auth.DELETE("/user/2fa", disableTwoFactor)
+
+	// Маршруты для персональных токенов доступа
+	auth.POST("/user/tokens", createPersonalToken)
+	auth.GET("/user/tokens", getPersonalTokens)
+	auth.DELETE("/user/tokens/:id", deletePersonalToken)
+
+	// Маршруты для проектов
 	auth.POST("/projects", createProject)
 	auth.GET("/user/projects", getUserProjects)
 	auth.GET("/projects/:id", getProject)
@@ -154,21 +265,98 @@ func Controller() {
 	auth.POST("/projects/:id/upload", uploadProjectFile)
 	auth.GET("/projects/:id/download", downloadProjectFile)
 
+	// Маршруты для процесса проекта (статусы и переходы задач)
+	auth.GET("/projects/:id/workflow", getWorkflow)
+	auth.PUT("/projects/:id/workflow", updateWorkflow)
+
+	// Маршруты для пользовательских полей задач проекта
+	auth.GET("/projects/:id/custom-fields", getCustomFields)
+	auth.POST("/projects/:id/custom-fields", createCustomField)
+	auth.PUT("/projects/:id/custom-fields/:field_id", updateCustomField)
+	auth.DELETE("/projects/:id/custom-fields/:field_id", deleteCustomField)
+
+	// Маршруты для участников проекта
+	auth.POST("/projects/:id/members", addProjectMember)
+	auth.GET("/projects/:id/members", getProjectMembers)
+	auth.PUT("/projects/:id/members/:user_id", updateProjectMember)
+	auth.DELETE("/projects/:id/members/:user_id", removeProjectMember)
+
+	// Лента событий и журнал аудита проекта
+	auth.GET("/projects/:id/activity", getProjectActivity)
+	auth.GET("/projects/:id/audit", getProjectAudit)
+
 	// Маршруты для задач
 	auth.POST("/projects/:id/tasks", createTask)
 	auth.GET("/projects/:id/tasks", getTasks)
 	auth.PUT("/projects/:id/tasks/:task_id", updateTask)
 	auth.DELETE("/projects/:id/tasks/:task_id", deleteTask)
+	auth.POST("/projects/:id/tasks/:task_id/assign", assignTask)
+	auth.GET("/projects/:id/tasks/:task_id/history", getTaskHistory)
+
+	// Маршруты для связей задач
+	auth.GET("/projects/:id/tasks/:task_id/links", getTaskLinks)
+	auth.POST("/projects/:id/tasks/:task_id/links", createTaskLink)
+	auth.DELETE("/projects/:id/tasks/:task_id/links/:link_id", deleteTaskLink)
+	auth.GET("/projects/:id/tasks/critical-path", getCriticalPath)
+
+	// Маршруты для учёта времени
+	auth.GET("/projects/:id/tasks/:task_id/worklogs", getTaskWorklogs)
+	auth.POST("/projects/:id/tasks/:task_id/worklogs", createWorklog)
+	auth.DELETE("/projects/:id/tasks/:task_id/worklogs/:worklog_id", deleteWorklog)
+	auth.POST("/projects/:id/tasks/:task_id/timer/start", startTimer)
+	auth.POST("/projects/:id/tasks/:task_id/timer/stop", stopTimer)
+	auth.GET("/user/timer", getUserTimer)
+	auth.GET("/projects/:id/timesheet", getProjectTimesheet)
+
+	// Маршруты для повторяющихся задач
+	auth.POST("/projects/:id/tasks/:task_id/recurrence", createTaskRecurrence)
+	auth.GET("/projects/:id/recurrences", getProjectRecurrences)
+	auth.GET("/projects/:id/recurrences/:recurrence_id", getRecurrence)
+	auth.PUT("/projects/:id/recurrences/:recurrence_id", updateRecurrence)
+	auth.DELETE("/projects/:id/recurrences/:recurrence_id", stopRecurrence)
+
+	// Маршруты для чек-листов задач
+	auth.GET("/projects/:id/tasks/:task_id/checklist", getTaskChecklist)
+	auth.POST("/projects/:id/tasks/:task_id/checklist", createChecklistItem)
+	auth.PUT("/projects/:id/tasks/:task_id/checklist/:item_id", updateChecklistItem)
+	auth.DELETE("/projects/:id/tasks/:task_id/checklist/:item_id", deleteChecklistItem)
+
+	// Маршруты для комментариев
+	auth.GET("/projects/:id/tasks/:task_id/comments", getTaskComments)
+	auth.POST("/projects/:id/tasks/:task_id/comments", createTaskComment)
+	auth.PUT("/projects/:id/tasks/:task_id/comments/:comment_id", updateTaskComment)
+	auth.DELETE("/projects/:id/tasks/:task_id/comments/:comment_id", deleteTaskComment)
+	auth.GET("/user/mentions", getUserMentions)
+	auth.GET("/user/tasks", getUserTasks)
+
+	// Маршруты для уведомлений
+	auth.GET("/user/notifications", getUserNotifications)
+	auth.PUT("/user/notifications/:id", updateNotification)
+	auth.POST("/user/notifications/read-all", readAllNotifications)
+	auth.GET("/user/notifications/settings", getNotificationSettings)
+	auth.PUT("/user/notifications/settings", updateNotificationSettings)
+
+	// Права пользователя (для интерфейса)
+	auth.GET("/user/permissions", getUserPermissions)
+
+	// Маршруты для администраторов
+	admin := auth.Group("/admin")
+	admin.Use(adminMiddleware)
+	admin.GET("/users", listUsers)
+	admin.GET("/users/:id", getUser)
+	admin.PUT("/users/:id/role", updateUserRole)
+	admin.POST("/users/:id/disable", disableUser)
+	admin.POST("/users/:id/enable", enableUser)
+	admin.POST("/users/:id/unlock", unlockUser)
+	admin.POST("/users/:id/reset-password", resetUserPassword)
+	admin.DELETE("/users/:id", deleteUser)
+	admin.DELETE("/users/:id/2fa", resetUserTwoFactor)
+	admin.GET("/settings", getSettings)
+	admin.PUT("/settings", updateSettings)
+	admin.GET("/audit", listAuditEvents)
 
 	// Эндпоинт для документации
 	r.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// Запуск сервера
-	r.Run(":8080")
-
-	log.Println("Сервер работает на порту 8080")
-	// Запуск сервера с логированием ошибок
-	if err := r.Run(":8080"); err != nil {
-		log.Fatalf("Ошибка запуска сервера: %v", err)
-	}
+	return r
 }
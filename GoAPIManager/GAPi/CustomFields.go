@@ -0,0 +1,597 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Типы пользовательских полей
+const (
+	FieldTypeText        = "text"
+	FieldTypeNumber      = "number"
+	FieldTypeDate        = "date" // YYYY-MM-DD
+	FieldTypeSelect      = "select"
+	FieldTypeMultiSelect = "multi_select"
+	FieldTypeUser        = "user" // ID участника проекта
+)
+
+var customFieldTypes = []string{FieldTypeText, FieldTypeNumber, FieldTypeDate, FieldTypeSelect, FieldTypeMultiSelect, FieldTypeUser}
+
+// Ограничения пользовательских полей
+const (
+	maxCustomFields       = 50
+	maxFieldOptions       = 50
+	maxFieldOptionLength  = 100
+	maxFieldTextLength    = 1000
+	customFieldQueryParam = "field."
+)
+
+// Имя поля — ключ в custom_fields задачи и в параметрах запроса: латинские буквы в нижнем
+// регистре, цифры и '_', начинается с буквы, до 50 символов
+var customFieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// Пользовательское поле задач проекта
+type CustomField struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ProjectID uint   `gorm:"not null" json:"project_id"`
+	Name      string `gorm:"not null" json:"name"`
+	Type      string `gorm:"not null" json:"type"`
+	// Варианты значений для select и multi_select
+	Options []string `gorm:"serializer:json;not null" json:"options"`
+	// Обязательное поле задаётся при создании задачи и не может быть очищено
+	Required  bool      `gorm:"not null" json:"required"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Значение поля задачи. У multi_select по строке на выбранный вариант.
+type TaskFieldValue struct {
+	ID          uint `gorm:"primaryKey"`
+	TaskID      uint `gorm:"not null"`
+	FieldID     uint `gorm:"not null"`
+	TextValue   *string
+	NumberValue *float64
+}
+
+// Значение поля задачи вместе с именем и типом поля
+type taskFieldValueView struct {
+	TaskID      uint
+	Name        string
+	Type        string
+	TextValue   *string
+	NumberValue *float64
+}
+
+// Тело запроса на создание или изменение поля (при изменении пустые поля не меняются, тип менять нельзя)
+type customFieldRequest struct {
+	Name     *string  `json:"name"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options"`
+	Required *bool    `json:"required"`
+}
+
+// Ошибка в значении пользовательского поля из запроса
+type customFieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *customFieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// Изменение пользовательских полей задачи: итоговые значения всех полей задачи
+// и строки, которые заменяют значения полей FieldIDs
+type customFieldChange struct {
+	Values   map[string]interface{}
+	FieldIDs []uint
+	Rows     []TaskFieldValue
+}
+
+// Хранится ли значение поля в number_value
+func (f *CustomField) numeric() bool {
+	return f.Type == FieldTypeNumber || f.Type == FieldTypeUser
+}
+
+// Проверка описания поля; варианты приводятся к виду без пробелов по краям
+func validateCustomField(field *CustomField) error {
+	if !customFieldNamePattern.MatchString(field.Name) {
+		return errors.New("invalid name: use 1-50 lowercase latin letters, digits or '_', starting with a letter")
+	}
+	if !slices.Contains(customFieldTypes, field.Type) {
+		return fmt.Errorf("invalid type, allowed values are: %s", strings.Join(customFieldTypes, ", "))
+	}
+
+	if field.Type != FieldTypeSelect && field.Type != FieldTypeMultiSelect {
+		if len(field.Options) > 0 {
+			return errors.New("options are allowed only for select and multi_select fields")
+		}
+		field.Options = []string{}
+		return nil
+	}
+	if len(field.Options) == 0 || len(field.Options) > maxFieldOptions {
+		return fmt.Errorf("select fields must have 1-%d options", maxFieldOptions)
+	}
+	options := make([]string, 0, len(field.Options))
+	for _, option := range field.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || utf8.RuneCountInString(option) > maxFieldOptionLength {
+			return fmt.Errorf("options must be 1-%d characters long", maxFieldOptionLength)
+		}
+		if slices.Contains(options, option) {
+			return fmt.Errorf("duplicate option %s", option)
+		}
+		options = append(options, option)
+	}
+	field.Options = options
+	return nil
+}
+
+// Значение поля из JSON: строки для сохранения (без TaskID) и значение для ответа API.
+// Пустые значения (пустая строка, пустой список) возвращаются как nil — поле очищается.
+func parseFieldValue(ctx context.Context, field *CustomField, raw interface{}) ([]TaskFieldValue, interface{}, error) {
+	invalid := func(reason string) error {
+		return &customFieldError{Field: field.Name, Reason: reason}
+	}
+	text := func(s string) []TaskFieldValue {
+		return []TaskFieldValue{{FieldID: field.ID, TextValue: &s}}
+	}
+
+	switch field.Type {
+	case FieldTypeText:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, nil, invalid("expected a string")
+		}
+		if s == "" {
+			return nil, nil, nil
+		}
+		if utf8.RuneCountInString(s) > maxFieldTextLength {
+			return nil, nil, invalid(fmt.Sprintf("must be at most %d characters", maxFieldTextLength))
+		}
+		return text(s), s, nil
+
+	case FieldTypeNumber:
+		n, ok := raw.(float64)
+		if !ok || math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, nil, invalid("expected a number")
+		}
+		return []TaskFieldValue{{FieldID: field.ID, NumberValue: &n}}, n, nil
+
+	case FieldTypeDate:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, nil, invalid("expected a date YYYY-MM-DD")
+		}
+		if s == "" {
+			return nil, nil, nil
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, nil, invalid("expected a date YYYY-MM-DD")
+		}
+		return text(s), s, nil
+
+	case FieldTypeSelect:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, nil, invalid("expected one of the options")
+		}
+		if s == "" {
+			return nil, nil, nil
+		}
+		if !slices.Contains(field.Options, s) {
+			return nil, nil, invalid("allowed values are: " + strings.Join(field.Options, ", "))
+		}
+		return text(s), s, nil
+
+	case FieldTypeMultiSelect:
+		list, ok := raw.([]interface{})
+		if !ok {
+			return nil, nil, invalid("expected a list of options")
+		}
+		var rows []TaskFieldValue
+		selected := []string{}
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok || !slices.Contains(field.Options, s) {
+				return nil, nil, invalid("allowed values are: " + strings.Join(field.Options, ", "))
+			}
+			if slices.Contains(selected, s) {
+				return nil, nil, invalid("duplicate option " + s)
+			}
+			selected = append(selected, s)
+			rows = append(rows, text(s)...)
+		}
+		if len(selected) == 0 {
+			return nil, nil, nil
+		}
+		return rows, selected, nil
+
+	case FieldTypeUser:
+		n, ok := raw.(float64)
+		if !ok || n <= 0 || n != math.Trunc(n) || n > math.MaxUint32 {
+			return nil, nil, invalid("expected a user ID")
+		}
+		userID := uint(n)
+		roles, err := store.Projects.MemberRoles(ctx, field.ProjectID, []uint{userID})
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := roles[userID]; !ok {
+			return nil, nil, invalid(fmt.Sprintf("user %d is not a member of this project", userID))
+		}
+		return []TaskFieldValue{{FieldID: field.ID, NumberValue: &n}}, userID, nil
+	}
+	return nil, nil, invalid("unsupported field type " + field.Type)
+}
+
+// Значения пользовательских полей задачи из запроса. patch — поля из запроса (null очищает
+// значение), current — значения задачи до изменения (nil при создании задачи: тогда
+// обязательные поля должны быть заданы).
+func resolveCustomFields(ctx context.Context, projectID uint, patch, current map[string]interface{}) (*customFieldChange, error) {
+	change := &customFieldChange{Values: make(map[string]interface{}, len(current))}
+	for name, value := range current {
+		change.Values[name] = value
+	}
+	if len(patch) == 0 && current != nil {
+		return change, nil
+	}
+
+	fields, err := store.CustomFields.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*CustomField, len(fields))
+	for i := range fields {
+		byName[fields[i].Name] = &fields[i]
+	}
+
+	for name, raw := range patch {
+		field := byName[name]
+		if field == nil {
+			return nil, &customFieldError{Field: name, Reason: "unknown field"}
+		}
+		change.FieldIDs = append(change.FieldIDs, field.ID)
+		delete(change.Values, name)
+		if raw == nil {
+			continue
+		}
+		rows, value, err := parseFieldValue(ctx, field, raw)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			change.Values[name] = value
+			change.Rows = append(change.Rows, rows...)
+		}
+	}
+	slices.Sort(change.FieldIDs)
+
+	for _, field := range fields {
+		if _, set := change.Values[field.Name]; set || !field.Required {
+			continue
+		}
+		if _, inPatch := patch[field.Name]; current == nil || inPatch {
+			return nil, &customFieldError{Field: field.Name, Reason: "field is required"}
+		}
+	}
+	return change, nil
+}
+
+// Ответ на ошибку проверки пользовательских полей
+func respondCustomFieldError(c *gin.Context, err error) {
+	var fieldErr *customFieldError
+	if errors.As(err, &fieldErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom_fields: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+}
+
+// Заполнение CustomFields у задач (задачи без значений получают пустую карту)
+func loadCustomFields(ctx context.Context, tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	rows, err := store.CustomFields.ListValues(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byTask := make(map[uint]map[string]interface{}, len(tasks))
+	for _, row := range rows {
+		values := byTask[row.TaskID]
+		if values == nil {
+			values = make(map[string]interface{})
+			byTask[row.TaskID] = values
+		}
+		switch {
+		case row.Type == FieldTypeMultiSelect && row.TextValue != nil:
+			selected, _ := values[row.Name].([]string)
+			values[row.Name] = append(selected, *row.TextValue)
+		case row.Type == FieldTypeUser && row.NumberValue != nil:
+			values[row.Name] = uint(*row.NumberValue)
+		case row.NumberValue != nil:
+			values[row.Name] = *row.NumberValue
+		case row.TextValue != nil:
+			values[row.Name] = *row.TextValue
+		}
+	}
+	for i := range tasks {
+		tasks[i].CustomFields = byTask[tasks[i].ID]
+		if tasks[i].CustomFields == nil {
+			tasks[i].CustomFields = map[string]interface{}{}
+		}
+	}
+	return nil
+}
+
+// Условия на пользовательские поля из параметров запроса списка задач: field.<имя>=<значение>
+// (для multi_select — задача содержит вариант), для number и date также field.<имя>.min
+// и field.<имя>.max (границы включительно)
+func customFieldFilters(query url.Values, fields []CustomField) ([]TaskFieldFilter, error) {
+	var filters []TaskFieldFilter
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, customFieldQueryParam)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		op := "="
+		if base, found := strings.CutSuffix(name, ".min"); found {
+			name, op = base, ">="
+		} else if base, found := strings.CutSuffix(name, ".max"); found {
+			name, op = base, "<="
+		}
+
+		i := slices.IndexFunc(fields, func(f CustomField) bool { return f.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown custom field %s", name)
+		}
+		field := &fields[i]
+		if op != "=" && field.Type != FieldTypeNumber && field.Type != FieldTypeDate {
+			return nil, fmt.Errorf("field %s supports only exact match", name)
+		}
+
+		filter := TaskFieldFilter{FieldID: field.ID, Op: op, Numeric: field.numeric()}
+		raw := values[0]
+		switch field.Type {
+		case FieldTypeNumber:
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+				return nil, fmt.Errorf("invalid value of field %s, expected a number", name)
+			}
+			filter.Value = n
+		case FieldTypeUser:
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of field %s, expected a user ID", name)
+			}
+			filter.Value = float64(id)
+		case FieldTypeDate:
+			if _, err := time.Parse("2006-01-02", raw); err != nil {
+				return nil, fmt.Errorf("invalid value of field %s, expected YYYY-MM-DD", name)
+			}
+			filter.Value = raw
+		default:
+			filter.Value = raw
+		}
+		filters = append(filters, filter)
+	}
+	slices.SortFunc(filters, func(a, b TaskFieldFilter) int { return int(a.FieldID) - int(b.FieldID) })
+	return filters, nil
+}
+
+// Сортировка списка задач из параметра sort: id, title, deadline или field.<имя>,
+// с '-' в начале — по убыванию
+func taskSortFromQuery(raw string, fields []CustomField) (TaskSort, error) {
+	sort := TaskSort{Column: "id"}
+	if raw == "" {
+		return sort, nil
+	}
+	name, desc := strings.CutPrefix(raw, "-")
+	sort.Desc = desc
+
+	if fieldName, ok := strings.CutPrefix(name, customFieldQueryParam); ok {
+		i := slices.IndexFunc(fields, func(f CustomField) bool { return f.Name == fieldName })
+		if i < 0 {
+			return sort, fmt.Errorf("unknown custom field %s", fieldName)
+		}
+		sort.FieldID = fields[i].ID
+		sort.Numeric = fields[i].numeric()
+		return sort, nil
+	}
+	if !slices.Contains([]string{"id", "title", "deadline"}, name) {
+		return sort, errors.New("invalid sort, allowed values are: id, title, deadline, field.<name> (with optional '-' for descending order)")
+	}
+	sort.Column = name
+	return sort, nil
+}
+
+// @Summary Пользовательские поля проекта
+// @Description Возвращает поля задач проекта. Значения полей задачи возвращаются в custom_fields задачи по имени поля.
+// @Tags Пользовательские поля
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Поля проекта"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/custom-fields [get]
+func getCustomFields(c *gin.Context) {
+	// Проект :id (найден в permissionMiddleware)
+	project := c.MustGet("project").(*Project)
+
+	fields, err := store.CustomFields.List(c.Request.Context(), project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"CustomFields": fields})
+}
+
+// @Summary Создание пользовательского поля
+// @Description Добавляет поле задач проекта (право project.update, не больше 50 полей). name — ключ в custom_fields задачи (латинские буквы в нижнем регистре, цифры и '_'); type — text (до 1000 символов), number, date (YYYY-MM-DD), select или multi_select (варианты в options) или user (ID участника проекта). required — значение обязательно при создании задачи и не может быть очищено.
+// @Tags Пользовательские поля
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body customFieldRequest true "Описание поля"
+// @Success 201 {object} map[string]interface{} "Поле создано"
+// @Failure 400 {object} map[string]string "Ошибка валидации данных"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 409 {object} map[string]string "Поле с таким именем уже есть"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/custom-fields [post]
+func createCustomField(c *gin.Context) {
+	// Проект :id (найден в permissionMiddleware)
+	project := c.MustGet("project").(*Project)
+
+	var req customFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	field := CustomField{ProjectID: project.ID, Type: req.Type, Options: req.Options, CreatedAt: time.Now()}
+	if req.Name != nil {
+		field.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if err := validateCustomField(&field); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	fields, err := store.CustomFields.List(ctx, project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if len(fields) >= maxCustomFields {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A project can have at most %d custom fields", maxCustomFields)})
+		return
+	}
+
+	if err := store.CustomFields.Create(ctx, &field); err != nil {
+		if errors.Is(err, ErrDuplicate) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Custom field with this name already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create custom field", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "custom_field.create", "custom_field", field.ID, project.ID, nil, &field)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Поле успешно создано", "CustomField": field})
+}
+
+// @Summary Изменение пользовательского поля
+// @Description Изменяет имя, варианты или обязательность поля (право project.update); тип поля не меняется. Значения вариантов, удалённых из options, удаляются у задач.
+// @Tags Пользовательские поля
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param field_id path int true "ID поля"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body customFieldRequest true "Изменяемые свойства поля"
+// @Success 200 {object} map[string]interface{} "Поле изменено"
+// @Failure 400 {object} map[string]string "Ошибка валидации данных"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Поле не найдено"
+// @Failure 409 {object} map[string]string "Поле с таким именем уже есть"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/custom-fields/{field_id} [put]
+func updateCustomField(c *gin.Context) {
+	// Поле проекта :id (найдено в permissionMiddleware)
+	found := c.MustGet("custom_field").(*CustomField)
+
+	var req customFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if req.Type != "" && req.Type != found.Type {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Custom field type cannot be changed"})
+		return
+	}
+
+	field := *found
+	if req.Name != nil {
+		field.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Options != nil {
+		field.Options = req.Options
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if err := validateCustomField(&field); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var removed []string
+	for _, option := range found.Options {
+		if !slices.Contains(field.Options, option) {
+			removed = append(removed, option)
+		}
+	}
+
+	if err := store.CustomFields.Update(c.Request.Context(), &field, removed); err != nil {
+		if errors.Is(err, ErrDuplicate) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Custom field with this name already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update custom field", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "custom_field.update", "custom_field", field.ID, field.ProjectID, found, &field)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Поле успешно изменено", "CustomField": field})
+}
+
+// @Summary Удаление пользовательского поля
+// @Description Удаляет поле проекта вместе с его значениями у всех задач (право project.update)
+// @Tags Пользовательские поля
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param field_id path int true "ID поля"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Поле удалено"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Поле не найдено"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/custom-fields/{field_id} [delete]
+func deleteCustomField(c *gin.Context) {
+	// Поле проекта :id (найдено в permissionMiddleware)
+	field := c.MustGet("custom_field").(*CustomField)
+
+	if err := store.CustomFields.Delete(c.Request.Context(), field.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete custom field", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "custom_field.delete", "custom_field", field.ID, field.ProjectID, field, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Поле успешно удалено"})
+}
@@ -0,0 +1,229 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Создание пользовательского поля проекта
+func (s *testServer) customField(token string, projectID uint, body gin.H) {
+	s.t.Helper()
+	s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/custom-fields", projectID), token, body)
+}
+
+// Названия задач проекта из списка с параметрами query (nil — задач не найдено)
+func (s *testServer) taskTitles(token string, projectID uint, query string) []string {
+	s.t.Helper()
+	path := fmt.Sprintf("/projects/%d/tasks?%s", projectID, query)
+	if w := s.request(http.MethodGet, path, token, nil); w.Code == http.StatusNotFound {
+		return nil
+	}
+	out := s.expect(http.StatusOK, http.MethodGet, path, token, nil)
+	var titles []string
+	for _, item := range out["Задачи"].([]interface{}) {
+		titles = append(titles, item.(map[string]interface{})["title"].(string))
+	}
+	return titles
+}
+
+func TestParseFieldValue(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	s.user("bobby")
+	s.user("carol")
+	projectID := s.project(alice, "Alpha")
+	s.addMember(alice, projectID, "bobby", ProjectRoleViewer)
+	bobID, carolID := userID(t, "bobby"), userID(t, "carol")
+
+	fields := map[string]*CustomField{}
+	for _, f := range []CustomField{
+		{Name: "note", Type: FieldTypeText},
+		{Name: "points", Type: FieldTypeNumber},
+		{Name: "due", Type: FieldTypeDate},
+		{Name: "severity", Type: FieldTypeSelect, Options: []string{"low", "high"}},
+		{Name: "tags", Type: FieldTypeMultiSelect, Options: []string{"ui", "api", "db"}},
+		{Name: "reviewer", Type: FieldTypeUser},
+	} {
+		f.ProjectID = projectID
+		fields[f.Type] = &f
+	}
+
+	for _, tc := range []struct {
+		fieldType string
+		raw       interface{}
+		want      interface{} // nil — значение очищается
+		invalid   bool
+	}{
+		{fieldType: FieldTypeText, raw: "draft", want: "draft"},
+		{fieldType: FieldTypeText, raw: "", want: nil},
+		{fieldType: FieldTypeText, raw: strings.Repeat("я", maxFieldTextLength), want: strings.Repeat("я", maxFieldTextLength)},
+		{fieldType: FieldTypeText, raw: strings.Repeat("я", maxFieldTextLength+1), invalid: true},
+		{fieldType: FieldTypeText, raw: 5.0, invalid: true},
+
+		{fieldType: FieldTypeNumber, raw: 3.5, want: 3.5},
+		{fieldType: FieldTypeNumber, raw: -2.0, want: -2.0},
+		{fieldType: FieldTypeNumber, raw: "3", invalid: true},
+		{fieldType: FieldTypeNumber, raw: math.Inf(1), invalid: true},
+
+		{fieldType: FieldTypeDate, raw: "2026-02-28", want: "2026-02-28"},
+		{fieldType: FieldTypeDate, raw: "", want: nil},
+		{fieldType: FieldTypeDate, raw: "2026-02-30", invalid: true},
+		{fieldType: FieldTypeDate, raw: "28.02.2026", invalid: true},
+		{fieldType: FieldTypeDate, raw: 20260228.0, invalid: true},
+
+		{fieldType: FieldTypeSelect, raw: "high", want: "high"},
+		{fieldType: FieldTypeSelect, raw: "", want: nil},
+		{fieldType: FieldTypeSelect, raw: "urgent", invalid: true},
+		{fieldType: FieldTypeSelect, raw: "High", invalid: true},
+		{fieldType: FieldTypeSelect, raw: []interface{}{"high"}, invalid: true},
+
+		{fieldType: FieldTypeMultiSelect, raw: []interface{}{"db", "ui"}, want: []string{"db", "ui"}},
+		{fieldType: FieldTypeMultiSelect, raw: []interface{}{}, want: nil},
+		{fieldType: FieldTypeMultiSelect, raw: []interface{}{"ui", "ui"}, invalid: true},
+		{fieldType: FieldTypeMultiSelect, raw: []interface{}{"ui", "mobile"}, invalid: true},
+		{fieldType: FieldTypeMultiSelect, raw: []interface{}{1.0}, invalid: true},
+		{fieldType: FieldTypeMultiSelect, raw: "ui", invalid: true},
+
+		{fieldType: FieldTypeUser, raw: float64(bobID), want: bobID},
+		{fieldType: FieldTypeUser, raw: float64(carolID), invalid: true}, // не участник проекта
+		{fieldType: FieldTypeUser, raw: 9999.0, invalid: true},
+		{fieldType: FieldTypeUser, raw: 0.0, invalid: true},
+		{fieldType: FieldTypeUser, raw: -1.0, invalid: true},
+		{fieldType: FieldTypeUser, raw: 1.5, invalid: true},
+		{fieldType: FieldTypeUser, raw: "2", invalid: true},
+	} {
+		field := fields[tc.fieldType]
+		rows, value, err := parseFieldValue(context.Background(), field, tc.raw)
+		if tc.invalid {
+			var fieldErr *customFieldError
+			if !errors.As(err, &fieldErr) || fieldErr.Field != field.Name {
+				t.Errorf("%s %v: err = %v, want a field error", tc.fieldType, tc.raw, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %v: %v", tc.fieldType, tc.raw, err)
+			continue
+		}
+		if !reflect.DeepEqual(value, tc.want) {
+			t.Errorf("%s %v: value = %#v, want %#v", tc.fieldType, tc.raw, value, tc.want)
+		}
+		if wantRows := 0; tc.want != nil {
+			wantRows = 1
+			if list, ok := tc.want.([]string); ok {
+				wantRows = len(list)
+			}
+			if len(rows) != wantRows {
+				t.Errorf("%s %v: %d rows, want %d", tc.fieldType, tc.raw, len(rows), wantRows)
+			}
+		} else if len(rows) != 0 {
+			t.Errorf("%s %v: %d rows for an empty value", tc.fieldType, tc.raw, len(rows))
+		}
+	}
+}
+
+func TestCustomFieldFilterAndSort(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	s.user("bobby")
+	projectID := s.project(alice, "Alpha")
+	s.addMember(alice, projectID, "bobby", ProjectRoleMember)
+	bobID := userID(t, "bobby")
+
+	s.customField(alice, projectID, gin.H{"name": "points", "type": FieldTypeNumber})
+	s.customField(alice, projectID, gin.H{"name": "due", "type": FieldTypeDate})
+	s.customField(alice, projectID, gin.H{"name": "tags", "type": FieldTypeMultiSelect, "options": []string{" ui ", "api", "db"}})
+	s.customField(alice, projectID, gin.H{"name": "owner", "type": FieldTypeUser})
+	s.expect(http.StatusConflict, http.MethodPost, fmt.Sprintf("/projects/%d/custom-fields", projectID), alice, gin.H{"name": "points", "type": FieldTypeText})
+	s.expect(http.StatusBadRequest, http.MethodPost, fmt.Sprintf("/projects/%d/custom-fields", projectID), alice, gin.H{"name": "Points", "type": FieldTypeText})
+
+	create := func(title string, fields gin.H) uint {
+		t.Helper()
+		out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), alice, gin.H{
+			"title": title, "priority": "Low", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339), "custom_fields": fields,
+		})
+		return uint(field(t, out, "Task", "ID").(float64))
+	}
+	a := create("A", gin.H{"points": 5, "due": "2026-03-10", "tags": []string{"ui", "api"}, "owner": bobID})
+	create("B", gin.H{"points": 2, "due": "2026-03-01", "tags": []string{"db"}})
+	create("C", gin.H{"tags": []string{"api", "db"}})
+	create("D", nil)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"field.points.min=3", []string{"A"}},
+		{"field.points.max=5", []string{"A", "B"}},
+		{"field.points=2", []string{"B"}},
+		{"field.due.min=2026-03-01&field.due.max=2026-03-05", []string{"B"}},
+		{"field.tags=api", []string{"A", "C"}},
+		{"field.tags=api&field.points.min=1", []string{"A"}},
+		{"field.owner=" + fmt.Sprint(bobID), []string{"A"}},
+		{"field.points.min=100", nil},
+
+		// Задачи без значения — в конце списка в обоих направлениях
+		{"sort=field.points", []string{"B", "A", "C", "D"}},
+		{"sort=-field.points", []string{"A", "B", "C", "D"}},
+		{"sort=field.due", []string{"B", "A", "C", "D"}},
+		// multi_select: по возрастанию — наименьший вариант, по убыванию — наибольший
+		{"sort=field.tags", []string{"A", "C", "B", "D"}},
+		{"sort=-field.tags", []string{"A", "B", "C", "D"}},
+		{"sort=-title", []string{"D", "C", "B", "A"}},
+		{"sort=-field.points&field.tags=db", []string{"B", "C"}},
+	} {
+		if got := s.taskTitles(alice, projectID, tc.query); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: tasks = %v, want %v", tc.query, got, tc.want)
+		}
+	}
+
+	for _, query := range []string{
+		"field.points=many",
+		"field.due.min=01.03.2026",
+		"field.owner=bobby",
+		"field.tags.min=api",
+		"field.unknown=1",
+		"sort=field.unknown",
+		"sort=priority",
+	} {
+		s.expect(http.StatusBadRequest, http.MethodGet, fmt.Sprintf("/projects/%d/tasks?%s", projectID, url.PathEscape(query)), alice, nil)
+	}
+
+	// Изменение меняет только переданные поля, null очищает значение
+	out := s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("/projects/%d/tasks/%d", projectID, a), alice, gin.H{
+		"title": "A", "priority": "Low", "status": "In_Line", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+		"custom_fields": gin.H{"points": nil, "tags": []string{"db"}},
+	})
+	values := field(t, out, "Task", "custom_fields").(map[string]interface{})
+	if _, ok := values["points"]; ok || values["due"] != "2026-03-10" || values["owner"] != float64(bobID) || fmt.Sprint(values["tags"]) != "[db]" {
+		t.Errorf("custom_fields after update = %v", values)
+	}
+	if got := s.taskTitles(alice, projectID, "sort=field.points"); !slices.Equal(got, []string{"B", "A", "C", "D"}) {
+		t.Errorf("sort after clearing: tasks = %v", got)
+	}
+
+	// Обязательное поле задаётся при создании и не очищается
+	s.customField(alice, projectID, gin.H{"name": "team", "type": FieldTypeText, "required": true})
+	s.expect(http.StatusBadRequest, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), alice, gin.H{
+		"title": "E", "priority": "Low", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+	})
+	e := create("E", gin.H{"team": "core"})
+	s.expect(http.StatusBadRequest, http.MethodPut, fmt.Sprintf("/projects/%d/tasks/%d", projectID, e), alice, gin.H{
+		"title": "E", "priority": "Low", "status": "In_Line", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+		"custom_fields": gin.H{"team": nil},
+	})
+	if got := s.taskTitles(alice, projectID, "field.team=core"); !slices.Equal(got, []string{"E"}) {
+		t.Errorf("field.team=core: tasks = %v", got)
+	}
+}
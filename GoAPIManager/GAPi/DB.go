@@ -3,40 +3,76 @@ package GoAPIManager
 import (
 	"fmt"
 	"log"
-	"os"
 
-	"github.com/joho/godotenv"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var db *gorm.DB
 
-func initDB() {
-	// Инициализация базы данных
-	env := "GAPi/DataBase.env"
-	errenv := godotenv.Load(env) //".env"
-	if errenv != nil {
-		log.Fatal("Ошибка загрузки .env файла")
+// Диалект подключённой базы данных (выбирает набор миграций и особенности SQL)
+var dbDialect sqlDialect = postgresDialect{}
+
+// Открытие базы данных, выбранной в database.driver
+func openDatabase(c DatabaseConfig) (*gorm.DB, sqlDialect, error) {
+	// TranslateError приводит ошибки драйверов к gorm.ErrDuplicatedKey и т.п.
+	gormConfig := &gorm.Config{TranslateError: true}
+
+	if c.Driver == "sqlite" {
+		conn, err := gorm.Open(sqlite.Open(c.SQLiteDSN()), gormConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		// SQLite допускает только одного писателя, запросы выполняются последовательно.
+		// Одно соединение также нужно, чтобы база ":memory:" была общей для всех запросов.
+		sqlDB, err := conn.DB()
+		if err != nil {
+			return nil, nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		return conn, sqliteDialect{}, nil
 	}
 
-	//Для Windows
-	connStr := fmt.Sprintf("user=%s password=%s dbname=%s sslmode=disable",
-		os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"))
+	conn, err := gorm.Open(postgres.Open(c.DSN()), gormConfig)
+	return conn, postgresDialect{}, err
+}
 
-	//Для докера
-	/*connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-	os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"))
-	*/
+// Подключение к базе данных и создание хранилища
+func connectDB() {
 	var err error
-	db, err = gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	db, dbDialect, err = openDatabase(cfg.Database)
 	if err != nil {
-		panic("Не удалось подключиться к базе данных")
+		log.Fatalf("Не удалось подключиться к базе данных: %v", err)
 	}
+	store = newGormStore(db, dbDialect)
 
 	fmt.Println("База данных успешно подключена!")
+}
 
-	// Автоматическая миграция
-	db.AutoMigrate(&User{}, &Project{}, &Task{})
-	fmt.Println("Миграция базы данных выполнена успешно!")
+// OpenStore подключается к базе данных из конфигурации c и применяет ожидающие миграции.
+// Нужен для интеграционных тестов и небольших установок на SQLite
+// (например, database.driver=sqlite, database.path=":memory:").
+func OpenStore(c *Config) (*Store, error) {
+	conn, dialect, err := openDatabase(c.Database)
+	if err != nil {
+		return nil, err
+	}
+	cfg, db, dbDialect = c, conn, dialect
+	if err := migrateUp(0); err != nil {
+		return nil, err
+	}
+	store = newGormStore(db, dbDialect)
+	return store, nil
+}
+
+func initDB() {
+	// Инициализация базы данных
+	connectDB()
+
+	// Схема меняется только командой `migrate`, сервер лишь проверяет её актуальность
+	if err := checkSchema(); err != nil {
+		log.Fatalf("Сервер не может быть запущен: %v", err)
+	}
+	fmt.Println("Схема базы данных актуальна!")
 }
@@ -0,0 +1,294 @@
+package GoAPIManager
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Одноразовый токен из письма (сброс пароля или подтверждение email).
+// Сам токен отправляется пользователю, в базе хранится только его SHA-256.
+type EmailToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	Purpose   string     `gorm:"not null"`
+	Email     string     `gorm:"not null"` // адрес, на который отправлено письмо
+	TokenHash string     `gorm:"not null;unique"`
+	CreatedAt time.Time  `gorm:"not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // время использования; токен действует только один раз
+}
+
+// Назначение токена из письма
+const (
+	emailTokenPasswordReset = "password_reset"
+	emailTokenVerification  = "email_verification"
+)
+
+// Тело запроса с токеном из письма
+type emailTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// Тело запроса на смену email
+type updateEmailRequest struct {
+	Email string `json:"email"`
+	// Текущий пароль: без него украденный access-токен позволил бы перехватить сброс пароля
+	Password string `json:"password"`
+}
+
+var (
+	errEmailTokenInvalid = errors.New("invalid email token")
+	errEmailTokenExpired = errors.New("email token expired")
+)
+
+// Приведение email к виду, в котором он хранится (без пробелов, в нижнем регистре)
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := validate.Var(email, "required,email,max=254"); err != nil {
+		return "", errors.New("Invalid email format")
+	}
+	return email, nil
+}
+
+// Выдача токена для письма на текущий email пользователя. Выданные раньше токены
+// с тем же назначением перестают действовать.
+func issueEmailToken(ctx context.Context, tokens EmailTokenRepository, user *User, purpose string) (string, error) {
+	if user.Email == nil {
+		return "", errors.New("user has no email")
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	ttl := cfg.Auth.EmailVerificationTTL
+	if purpose == emailTokenPasswordReset {
+		ttl = cfg.Auth.PasswordResetTTL
+	}
+
+	now := time.Now()
+	if err := tokens.Invalidate(ctx, user.ID, purpose, now); err != nil {
+		return "", err
+	}
+	err := tokens.Create(ctx, &EmailToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Email:     *user.Email,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Использование токена из письма: токен с другим назначением, истёкший или уже
+// использованный не принимается
+func consumeEmailToken(ctx context.Context, tokens EmailTokenRepository, raw, purpose string) (*EmailToken, error) {
+	if raw == "" {
+		return nil, errEmailTokenInvalid
+	}
+	token, err := tokens.GetByHash(ctx, hashToken(raw))
+	if errors.Is(err, ErrNotFound) {
+		return nil, errEmailTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token.Purpose != purpose || token.UsedAt != nil {
+		return nil, errEmailTokenInvalid
+	}
+
+	now := time.Now()
+	if !now.Before(token.ExpiresAt) {
+		return nil, errEmailTokenExpired
+	}
+	ok, err := tokens.MarkUsed(ctx, token.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errEmailTokenInvalid
+	}
+	return token, nil
+}
+
+// Ответ на ошибку использования токена из письма
+func respondEmailTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errEmailTokenInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or used token"})
+	case errors.Is(err, errEmailTokenExpired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token expired"})
+	case errors.Is(err, errAccountDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+	}
+}
+
+// Ссылка из письма: адрес приложения (mail.app_url), путь и токен
+func emailLink(path, token string) string {
+	return strings.TrimRight(cfg.Mail.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// Письмо со ссылкой для подтверждения email
+func emailVerificationMessage(user *User, token string) Message {
+	return Message{
+		To:      *user.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\n"+
+			"Чтобы подтвердить адрес, перейдите по ссылке:\n%s\n\n"+
+			"Или отправьте токен в POST /email/verify: %s\n\n"+
+			"Ссылка действует %s. Если вы не регистрировались, просто проигнорируйте это письмо.\n",
+			user.Username, emailLink("/verify-email", token), token, cfg.Auth.EmailVerificationTTL),
+	}
+}
+
+// Письмо со ссылкой для сброса пароля
+func passwordResetMessage(user *User, token string) Message {
+	return Message{
+		To:      *user.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\n"+
+			"Чтобы задать новый пароль, перейдите по ссылке:\n%s\n\n"+
+			"Или отправьте токен в POST /password/reset вместе с новым паролем: %s\n\n"+
+			"Ссылка действует %s и только один раз. Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.\n",
+			user.Username, emailLink("/reset-password", token), token, cfg.Auth.PasswordResetTTL),
+	}
+}
+
+// @Summary Подтверждение email
+// @Description Подтверждает email по токену из письма, отправленного при регистрации или смене адреса. Токен одноразовый.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body emailTokenRequest true "Токен из письма"
+// @Success 200 {object} map[string]string "Email подтверждён"
+// @Failure 400 {object} map[string]string "Токен недействителен, использован или истёк"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /email/verify [post]
+func verifyEmail(c *gin.Context) {
+	var req emailTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	err := store.Transaction(ctx, func(tx *Store) error {
+		token, err := consumeEmailToken(ctx, tx.EmailTokens, req.Token, emailTokenVerification)
+		if err != nil {
+			return err
+		}
+		user, err := tx.Users.GetByID(ctx, token.UserID)
+		if err != nil {
+			return err
+		}
+		// Адрес сменили после отправки письма — подтверждать нечего
+		if user.Email == nil || *user.Email != token.Email {
+			return errEmailTokenInvalid
+		}
+		if user.EmailVerifiedAt == nil {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+		}
+		return tx.Users.Update(ctx, user)
+	})
+	if err != nil {
+		respondEmailTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email успешно подтверждён"})
+}
+
+// @Summary Смена email
+// @Description Задаёт или меняет email текущего пользователя (нужен текущий пароль). Новый адрес считается неподтверждённым, на него отправляется письмо для подтверждения. Повторный запрос с тем же адресом отправляет письмо ещё раз.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Param request body updateEmailRequest true "Новый email и текущий пароль"
+// @Success 200 {object} map[string]interface{} "Email изменён, письмо отправлено"
+// @Failure 400 {object} map[string]string "Некорректный email или адрес уже подтверждён"
+// @Failure 401 {object} map[string]string "Неверный пароль"
+// @Failure 409 {object} map[string]string "Email уже используется"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /user/email [put]
+func updateEmail(c *gin.Context) {
+	var req updateEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := store.Users.GetByID(ctx, c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
+		return
+	}
+
+	before := *user
+	if user.Email != nil && *user.Email == email {
+		if user.EmailVerifiedAt != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email already verified"})
+			return
+		}
+	} else {
+		user.Email = &email
+		user.EmailVerifiedAt = nil
+	}
+
+	var token string
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Users.Update(ctx, user); err != nil {
+			return err
+		}
+		// Ссылки для сброса пароля, отправленные на прежний адрес, больше не действуют
+		if err := tx.EmailTokens.Invalidate(ctx, user.ID, emailTokenPasswordReset, time.Now()); err != nil {
+			return err
+		}
+		token, err = issueEmailToken(ctx, tx.EmailTokens, user, emailTokenVerification)
+		return err
+	})
+	if errors.Is(err, ErrDuplicate) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already in use"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Повторная отправка письма на тот же адрес ничего не меняет
+	if before.Email == nil || *before.Email != email {
+		recordAudit(c, "user.update_email", "user", user.ID, 0, &before, user)
+	}
+	sendMail(emailVerificationMessage(user, token))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Письмо для подтверждения отправлено на " + email, "User": user})
+}
@@ -0,0 +1,159 @@
+package GoAPIManager
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Почтовый ящик теста: письма, отправленные сервером, вместо отправки попадают в канал
+type testMailbox chan Message
+
+func (m testMailbox) Send(ctx context.Context, msg Message) error {
+	m <- msg
+	return nil
+}
+
+func (s *testServer) mailbox() testMailbox {
+	previous := mailer
+	s.t.Cleanup(func() { SetMailer(previous) })
+	box := make(testMailbox, 10)
+	SetMailer(box)
+	return box
+}
+
+// Письма отправляются в фоне, поэтому следующее письмо ожидается с таймаутом
+func (m testMailbox) next(t *testing.T, to string) Message {
+	t.Helper()
+	select {
+	case msg := <-m:
+		if msg.To != to {
+			t.Fatalf("mail sent to %s, want %s", msg.To, to)
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no mail sent to %s", to)
+		return Message{}
+	}
+}
+
+func (m testMailbox) empty(t *testing.T) {
+	t.Helper()
+	select {
+	case msg := <-m:
+		t.Fatalf("unexpected mail to %s: %s", msg.To, msg.Subject)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+var mailTokenRe = regexp.MustCompile(`: ([A-Za-z0-9_-]{43})\n`)
+
+// Токен из текста письма
+func mailToken(t *testing.T, msg Message) string {
+	t.Helper()
+	m := mailTokenRe.FindStringSubmatch(msg.Body)
+	if m == nil {
+		t.Fatalf("no token in mail %q", msg.Body)
+	}
+	return m[1]
+}
+
+func emailVerified(t *testing.T, username string) bool {
+	t.Helper()
+	user, err := store.Users.GetByUsername(context.Background(), username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.EmailVerifiedAt != nil
+}
+
+func TestEmailVerification(t *testing.T) {
+	s := newTestServer(t)
+	box := s.mailbox()
+	s.expect(http.StatusCreated, http.MethodPost, "/register", "", gin.H{"username": "alice", "password": testPassword, "email": " Alice@Example.com "})
+	token := mailToken(t, box.next(t, "alice@example.com"))
+	if emailVerified(t, "alice") {
+		t.Fatal("email verified before the link was used")
+	}
+
+	// Токен подтверждения не подходит для сброса пароля
+	s.expect(http.StatusBadRequest, http.MethodPost, "/password/reset", "", gin.H{"token": token, "new_password": "another1"})
+	s.expect(http.StatusOK, http.MethodPost, "/email/verify", "", gin.H{"token": token})
+	if !emailVerified(t, "alice") {
+		t.Fatal("email not verified")
+	}
+	s.expect(http.StatusBadRequest, http.MethodPost, "/email/verify", "", gin.H{"token": token})
+	s.expect(http.StatusBadRequest, http.MethodPost, "/email/verify", "", gin.H{"token": "not-a-token"})
+
+	// Новый адрес снова не подтверждён; письмо на старый адрес его не подтверждает
+	alice := accessToken(t, s.loginTokens("alice"))
+	s.expect(http.StatusUnauthorized, http.MethodPut, "/user/email", alice, gin.H{"email": "alice@corp.example.com", "password": "wrong-password"})
+	s.expect(http.StatusOK, http.MethodPut, "/user/email", alice, gin.H{"email": "alice@corp.example.com", "password": testPassword})
+	first := mailToken(t, box.next(t, "alice@corp.example.com"))
+	if emailVerified(t, "alice") {
+		t.Fatal("changed email still verified")
+	}
+	// Повторная отправка отменяет предыдущую ссылку
+	s.expect(http.StatusOK, http.MethodPut, "/user/email", alice, gin.H{"email": "alice@corp.example.com", "password": testPassword})
+	second := mailToken(t, box.next(t, "alice@corp.example.com"))
+	s.expect(http.StatusBadRequest, http.MethodPost, "/email/verify", "", gin.H{"token": first})
+	s.expect(http.StatusOK, http.MethodPost, "/email/verify", "", gin.H{"token": second})
+	if !emailVerified(t, "alice") {
+		t.Fatal("changed email not verified")
+	}
+}
+
+func TestPasswordResetTokenIsSingleUse(t *testing.T) {
+	s := newTestServer(t)
+	box := s.mailbox()
+	s.expect(http.StatusCreated, http.MethodPost, "/register", "", gin.H{"username": "alice", "password": testPassword, "email": "alice@example.com"})
+	box.next(t, "alice@example.com")
+	session := accessToken(t, s.loginTokens("alice"))
+
+	// Ответ для незарегистрированного адреса такой же, но письмо не отправляется
+	s.expect(http.StatusOK, http.MethodPost, "/password/forgot", "", gin.H{"email": "nobody@example.com"})
+	box.empty(t)
+
+	s.expect(http.StatusOK, http.MethodPost, "/password/forgot", "", gin.H{"email": "ALICE@example.com"})
+	first := mailToken(t, box.next(t, "alice@example.com"))
+	s.expect(http.StatusOK, http.MethodPost, "/password/forgot", "", gin.H{"email": "alice@example.com"})
+	token := mailToken(t, box.next(t, "alice@example.com"))
+
+	// Новое письмо отменяет предыдущую ссылку
+	s.expect(http.StatusBadRequest, http.MethodPost, "/password/reset", "", gin.H{"token": first, "new_password": "another1"})
+	s.expect(http.StatusOK, http.MethodPost, "/password/reset", "", gin.H{"token": token, "new_password": "another1"})
+	s.expect(http.StatusBadRequest, http.MethodPost, "/password/reset", "", gin.H{"token": token, "new_password": "another2"})
+
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/sessions", session, nil)
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/login", "", gin.H{"username": "alice", "password": testPassword})
+	s.expect(http.StatusOK, http.MethodPost, "/login", "", gin.H{"username": "alice", "password": "another1"})
+	// Письмо дошло, значит адрес подтверждён
+	if !emailVerified(t, "alice") {
+		t.Error("email not verified by the password reset")
+	}
+}
+
+func TestPasswordResetTokenExpires(t *testing.T) {
+	s := newTestServer(t)
+	box := s.mailbox()
+	s.expect(http.StatusCreated, http.MethodPost, "/register", "", gin.H{"username": "alice", "password": testPassword, "email": "alice@example.com"})
+	box.next(t, "alice@example.com")
+
+	s.expect(http.StatusOK, http.MethodPost, "/password/forgot", "", gin.H{"email": "alice@example.com"})
+	token := mailToken(t, box.next(t, "alice@example.com"))
+	err := db.Model(&EmailToken{}).Where("purpose = ?", emailTokenPasswordReset).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := s.expect(http.StatusBadRequest, http.MethodPost, "/password/reset", "", gin.H{"token": token, "new_password": "another1"})
+	if out["error"] != "Token expired" {
+		t.Errorf("error = %v, want Token expired", out["error"])
+	}
+	s.expect(http.StatusOK, http.MethodPost, "/login", "", gin.H{"username": "alice", "password": testPassword})
+}
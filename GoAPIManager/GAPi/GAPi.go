@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -17,26 +18,45 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 )
 
-const uploadDir = "uploads/"
-
 var ctx = context.Background()
 
 var validate = validator.New()
 
-var jwtKey = []byte("secret_key")
+// Глобальные роли пользователей
+const (
+	RoleUser  = "User"
+	RoleAdmin = "Admin"
+)
 
 // Models
 type User struct {
-	ID           uint   `gorm:"primaryKey"`
-	Username     string `gorm:"unique;not null"`
-	Password     string `gorm:"not null"`
-	Role         string `gorm:"not null" validate:"required,oneof=User Admin"`
-	RefreshToken string `gorm:"column:refreshtoken"`
-	//Добавить связь (Закомментировать после того как база данных создана, иначе будут при ответах вылазить ненужные строки)
-	Tasks []Task `gorm:"foreignKey:AssigneeID"`
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Username string `gorm:"unique;not null" json:"username"`
+	Password string `gorm:"not null" json:"-"`
+	Role     string `gorm:"not null" json:"role" validate:"required,oneof=User Admin"`
+	// Email хранится в нижнем регистре; по нему приходят письма для сброса пароля
+	Email           *string    `gorm:"unique" json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// Двухфакторная аутентификация: секрет TOTP (до подтверждения — ожидающий),
+	// время включения и последний использованный шаг
+	TOTPSecret    string     `gorm:"column:totp_secret;not null" json:"-"`
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at" json:"totp_enabled_at"`
+	TOTPLastStep  int64      `gorm:"column:totp_last_step;not null" json:"-"`
+	// Заблокированный пользователь не может войти, его сессии и токены не действуют
+	DisabledAt *time.Time `json:"disabled_at"`
+	// После сброса пароля администратором перед входом нужно задать новый пароль
+	MustChangePassword bool      `gorm:"not null" json:"must_change_password"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// Тело запроса на регистрацию. Роль не передаётся: новый пользователь всегда получает роль User.
+type registerRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Необязательный email: на него отправляется письмо для подтверждения адреса
+	Email string `json:"email"`
 }
 
 type Project struct {
@@ -45,9 +65,6 @@ type Project struct {
 	Description string
 	CreatedAt   time.Time
 	AssigneeID  uint `json:"assignee_id" gorm:"not null"`
-	//Добавить связи (Закомментировать после того как база данных создана, иначе будут при ответах вылазить ненужные строки)
-	Assignee User   `gorm:"foreignKey:AssigneeID"` // связь с User
-	Tasks    []Task `gorm:"foreignKey:ProjectID"`  // связь с задачами
 }
 
 type Task struct {
@@ -55,15 +72,51 @@ type Task struct {
 	ProjectID   uint      `gorm:"not null"`
 	Title       string    `gorm:"not null" json:"title" validate:"required,max=255"`
 	Description string    `json:"description" validate:"max=500"`
-	Status      string    `gorm:"not null" json:"status" validate:"required,oneof=In_Progress Done In_Line"`
+	Status      string    `gorm:"not null" json:"status"`
 	Priority    string    `gorm:"not null" json:"priority" validate:"required,oneof=High Medium Low"`
 	Deadline    time.Time `gorm:"not null" json:"deadline"`
 	AssigneeID  uint      `json:"assignee_id" gorm:"not null"`
-	//Добавить связи (Закомментировать после того как база данных создана, иначе будут при ответах вылазить ненужные строки)
-	Assignee User    `gorm:"foreignKey:AssigneeID;references:ID;constraint:OnDelete:SET NULL"` // связь с User
-	Project  Project `gorm:"foreignKey:ProjectID;references:ID;constraint:OnDelete:CASCADE"`   // связь с Project
+	// Все исполнители задачи (хранятся в task_assignees), основной исполнитель — AssigneeID
+	AssigneeIDs []uint `json:"assignee_ids" gorm:"-"`
+	// Родительская задача того же проекта (null — задача верхнего уровня)
+	ParentTaskID *uint `json:"parent_task_id"`
+	// Оценка трудоёмкости в минутах (null — не оценена)
+	EstimateMinutes *int `json:"estimate_minutes" validate:"omitempty,min=0,max=1000000"`
+	// Серия повторяющихся задач, к которой относится задача (null — задача не повторяется)
+	RecurrenceID *uint `json:"recurrence_id"`
+	// Значения пользовательских полей проекта по имени поля (хранятся в task_field_values)
+	CustomFields map[string]interface{} `json:"custom_fields" gorm:"-"`
+}
+
+// Копия задачи. Значения по указателям копируются: JSON записывает значение по указателю,
+// и без этого привязка запроса к копии изменила бы исходную задачу.
+func (t *Task) clone() Task {
+	task := *t
+	if t.ParentTaskID != nil {
+		parentTaskID := *t.ParentTaskID
+		task.ParentTaskID = &parentTaskID
+	}
+	if t.EstimateMinutes != nil {
+		estimate := *t.EstimateMinutes
+		task.EstimateMinutes = &estimate
+	}
+	if t.RecurrenceID != nil {
+		recurrenceID := *t.RecurrenceID
+		task.RecurrenceID = &recurrenceID
+	}
+	task.CustomFields = maps.Clone(t.CustomFields)
+	return task
 }
 
+// Тело запроса на вход
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Название устройства для списка сессий, по умолчанию — User-Agent
+	DeviceName string `json:"device_name"`
+}
+
+// Claims — содержимое JWT. В StandardClaims.Id (jti) хранится идентификатор сессии.
 type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
@@ -71,6 +124,13 @@ type Claims struct {
 	jwt.StandardClaims
 }
 
+var (
+	errTokenInvalid = errors.New("invalid token")
+	errTokenExpired = errors.New("token expired")
+	// Пользователь заблокирован администратором
+	errAccountDisabled = errors.New("account disabled")
+)
+
 func authMiddleware(c *gin.Context) {
 	tokenString := c.GetHeader("Authorization")
 	if tokenString == "" {
@@ -87,295 +147,297 @@ func authMiddleware(c *gin.Context) {
 
 	tokenString = strings.TrimPrefix(tokenString, prefix)
 
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
-
-	if err != nil || !token.Valid {
+	// Персональные токены (gapi_...) проверяются по базе, остальные — как JWT
+	var err error
+	if strings.HasPrefix(tokenString, personalTokenPrefix) {
+		err = authenticatePersonalToken(c, tokenString)
+	} else {
+		err = authenticateAccessToken(c, tokenString)
+	}
+	switch {
+	case errors.Is(err, errTokenInvalid):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+	case errors.Is(err, errTokenExpired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
+	case errors.Is(err, errSessionRevoked):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session revoked"})
+	case errors.Is(err, errAccountDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+	}
+	if err != nil {
 		c.Abort()
 		return
 	}
 
-	if claims.ExpiresAt < time.Now().Unix() {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
-		c.Abort()
+	// Персональный токен действует только в пределах своих областей
+	if !checkTokenScope(c) {
 		return
 	}
 
-	c.Set("id", claims.UserID)
-	c.Set("username", claims.Username)
-	c.Set("role", claims.Role)
-	// Проверка, имеет ли пользователь доступ к управлению проектом/задачами
-	// Карта маршрутов, где ключ — путь, а значение — список методов, требующих проверки
-	protectedRoutes := map[string]map[string]bool{
-		"/projects/:id": {
-			http.MethodGet:    true,
-			http.MethodPut:    true,
-			http.MethodDelete: true,
-		},
-		"/projects/:id/upload": {
-			http.MethodPost: true,
-		},
-		"/projects/:id/download": {
-			http.MethodGet: true,
-		},
-		"/projects/:id/tasks": {
-			http.MethodPost: true,
-			http.MethodGet:  true,
-		},
-		"/projects/:id/tasks/:task_id": {
-			http.MethodPut:    true,
-			http.MethodDelete: true,
-		},
+	// Обязательная 2FA: без неё доступна только её настройка
+	if !checkTwoFactorPolicy(c) {
+		return
 	}
-	// Проверяем, есть ли путь в списке защищенных
-	if methods, exists := protectedRoutes[c.FullPath()]; exists {
-		if methods[c.Request.Method] {
-			projectID, err := strconv.Atoi(c.Param("id"))
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
-				c.Abort()
-				return
-			}
-
-			var project Project
-			if err := db.First(&project, projectID).Error; err != nil {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
-				c.Abort()
-				return
-			}
-
-			usery := claims.UserID
-
-			if usery == 0 {
-				// Обработка ошибки, если пользователь не найден
-				fmt.Println("Ошибка:", usery)
-			}
-
-			userID := claims.UserID
-			role := claims.Role
-
-			if role != "admin" && userID != project.AssigneeID {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
-				c.Abort()
-				return
-			}
 
-		}
+	c.Next()
+}
+
+// Аутентификация по JWT, выданному при входе: токен действителен, только пока не завершена его сессия
+func authenticateAccessToken(c *gin.Context, tokenString string) error {
+	claims := &Claims{}
+	token, err := parseToken(tokenString, claims)
+	if err != nil || !token.Valid {
+		return errTokenInvalid
 	}
 
-	c.Next()
+	if claims.ExpiresAt < time.Now().Unix() {
+		return errTokenExpired
+	}
+
+	session, err := checkSession(c.Request.Context(), claims.Id, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	c.Set("id", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("role", claims.Role)
+	c.Set("session_id", session.ID)
+	return nil
 }
 
 // @Summary Регистрация пользователя
-// @Description Регистрирует нового пользователя в системе.
+// @Description Регистрирует нового пользователя в системе. Если указан email, на него отправляется письмо со ссылкой для подтверждения адреса (см. /email/verify).
 // @Tags Аутентификация
 // @Accept json
 // @Produce json
-// @Param input body User true "Данные для регистрации"
+// @Param input body registerRequest true "Имя пользователя, пароль и необязательный email"
 // @Success 201 {object} map[string]interface{} "Пользователь успешно зарегистрирован"
 // @Failure 400 {object} map[string]interface{} "Некорректные данные или пользователь уже существует"
 // @Failure 500 {object} map[string]interface{} "Внутренняя ошибка сервера"
 // @Router /register [post]
 func registerUser(c *gin.Context) {
-	var user User
-	if err := c.ShouldBindJSON(&user); err != nil {
+	var req registerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
 		return
 	}
 
-	if user.Username == "" || user.Password == "" {
+	if req.Username == "" || req.Password == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Username and password are required"})
 		return
 	}
 
-	// Проверка длины пароля
-	if len(user.Password) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Password must be at least 6 characters long"})
+	if err := validatePassword(req.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Валидация имени пользователя (пример: от 3 до 20 символов, только буквы и цифры)
-	if matched, _ := regexp.MatchString(`^[a-zA-Z0-9]{3,20}$`, user.Username); !matched {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid username format"})
+	if err := validateUsername(req.Username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	var email *string
+	if req.Email != "" {
+		normalized, err := normalizeEmail(req.Email)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		email = &normalized
+	}
+
 	// Хеширование пароля
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := hashPassword(req.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
 		return
 	}
 
 	// Проверка, существует ли пользователь
-	var existingUser User
-	if err := db.Where("username = ?", user.Username).First(&existingUser).Error; err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid credentials"})
+	if _, err := store.Users.GetByUsername(c.Request.Context(), req.Username); err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User already exists"})
+		return
+	} else if !errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid credentials"})
+		return
+	}
+	if email != nil {
+		if _, err := store.Users.GetByEmail(c.Request.Context(), *email); err == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email already in use"})
+			return
+		} else if !errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 			return
 		}
 	}
 
-	// Генерация refresh-токена
-	refreshToken, err := generateRefreshToken(user)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
+	// Сохранение пользователя в БД вместе с первой сессией, к которой привязан refresh-токен.
+	// Самостоятельная регистрация всегда создаёт обычного пользователя, администраторов
+	// назначает команда create-admin или другой администратор.
+	user := User{Username: req.Username, Password: hashedPassword, Role: RoleUser, Email: email}
+	var refreshToken, verificationToken string
+	err = store.Transaction(c.Request.Context(), func(tx *Store) error {
+		if err := tx.Users.Create(c.Request.Context(), &user); err != nil {
+			return err
+		}
+		session, err := createSession(c, tx.Sessions, &user, "")
+		if err != nil {
+			return err
+		}
+		// Генерация refresh-токена
+		refreshToken, err = issueRefreshToken(c.Request.Context(), tx.RefreshTokens, session)
+		if err != nil || email == nil {
+			return err
+		}
+		verificationToken, err = issueEmailToken(c.Request.Context(), tx.EmailTokens, &user, emailTokenVerification)
+		return err
+	})
+	if errors.Is(err, ErrDuplicate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User already exists"})
 		return
 	}
-
-	// Сохранение пользователя в БД
-	user.Password = string(hashedPassword)
-	user.RefreshToken = refreshToken
-
-	//Транзакцией
-	tx := db.Begin()
-	if err := tx.Create(&user).Error; err != nil {
-		tx.Rollback()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		return
 	}
-	tx.Commit()
+
+	if verificationToken != "" {
+		sendMail(emailVerificationMessage(&user, verificationToken))
+	}
+	recordAudit(c, "user.register", "user", user.ID, 0, nil, &user)
 
 	// Отправка ответа
 	c.JSON(http.StatusCreated, gin.H{"message": "Пользователь успешно зарегистрирован", "Ваш RefreshToken, сохраните его для того чтобы его можно было обменять на новый AccessToken": refreshToken})
 }
 
-// @Summary Обновление access и refresh токенов
-// @Description Проверяет refresh token, генерирует новый access и refresh токены и сохраняет новый refresh token в базе данных.
+// Функция для генерации access token сессии jti
+func generateAccessToken(user User, jti string) (string, error) {
+	claims := &Claims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			ExpiresAt: time.Now().Add(cfg.Auth.AccessTokenTTL).Unix(), // срок жизни accessToken из конфигурации
+		},
+	}
+
+	// Токен подписывается активным ключом из auth.keys_dir (см. Keys.go)
+	return signToken(claims)
+}
+
+// @Summary Аутентификация пользователя
+// @Description Аутентификация пользователя по имени и паролю и выдача access- и refresh-токена. Каждый вход создаёт отдельную сессию (см. /user/sessions). Если у пользователя включена 2FA, вместо токенов возвращается ChallengeToken, вход завершается через /login/2fa.
 // @Tags Аутентификация
 // @Accept json
 // @Produce json
-// @Param request body User true "Тело запроса с refresh токеном"
-// @Success 200 {object} map[string]string "Новые access и refresh токены"
+// @Param input body loginRequest true "Данные пользователя (имя, пароль и необязательное название устройства)"
+// @Success 200 {object} map[string]interface{} "Успешная аутентификация. Возвращает access- и refresh-токен или ChallengeToken для второго шага"
 // @Failure 400 {object} map[string]string "Некорректные входные данные"
-// @Failure 401 {object} map[string]string "Неверный refresh token"
-// @Failure 500 {object} map[string]string "Ошибка сервера"
-// @Router /refresh/:id [post]
-func refreshToken(c *gin.Context) {
-	// Проверка, есть ли такой refreshToken в базе данных
-	var user User
-	if err := c.ShouldBindJSON(&user); err != nil {
+// @Failure 401 {object} map[string]string "Неверное имя пользователя или пароль"
+// @Failure 403 {object} map[string]string "Учётная запись заблокирована или требуется смена пароля"
+// @Failure 429 {object} map[string]interface{} "Слишком много неудачных попыток, вход временно заблокирован (см. заголовок Retry-After)"
+// @Failure 500 {object} map[string]string "Ошибка при генерации access-токена"
+// @Router /login [post]
+func loginUser(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	if user.RefreshToken == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
+	// Перебор паролей: после нескольких неудачных попыток вход временно блокируется
+	if !checkLoginLockout(c, req.Username) {
 		return
 	}
 
-	if err := db.Where("refreshtoken = ?", user.RefreshToken).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+	user, err := store.Users.GetByUsername(c.Request.Context(), req.Username)
+	if err != nil {
+		respondLoginFailure(c, req.Username)
 		return
 	}
 
-	// Генерация нового accessToken для пользователя
-	newAccessToken, err := generateAccessToken(user)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate access token"})
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		respondLoginFailure(c, req.Username)
 		return
 	}
+	// С включённой 2FA верный пароль ещё не завершает вход: счётчик сбросит второй шаг,
+	// иначе новые /login позволяли бы бесконечно перебирать коды
+	if user.TOTPEnabledAt == nil {
+		if err := resetLoginFailures(c.Request.Context(), user.Username); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+	}
 
-	// Генерация нового refreshToken
-	newRefreshToken, err := generateRefreshToken(user)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate refresh token"})
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
 		return
 	}
 
-	// Обновление refresh токена в базе данных с транзакцией
-	tx := db.Begin()
-	user.RefreshToken = newRefreshToken
-	if err := tx.Save(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update refresh token"})
+	// Пароль сброшен администратором: сначала нужно задать новый (POST /auth/change-password)
+	if user.MustChangePassword {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Password change required"})
 		return
 	}
-	tx.Commit()
 
-	c.JSON(http.StatusOK, gin.H{"AccessToken": newAccessToken, "RefreshToken": newRefreshToken})
+	finishLogin(c, user, req.DeviceName)
 }
 
-// Генерация refresh token
-func generateRefreshToken(user User) (string, error) {
-	claims := &Claims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     user.Role,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24 * 7).Unix(), // refreshToken срок жизни 7 дней
-		},
+// Завершение входа после проверки первого фактора (пароль или вход через SSO)
+func finishLogin(c *gin.Context, user *User, deviceName string) {
+	// С включённой 2FA вход завершается кодом из приложения (POST /login/2fa)
+	if user.TOTPEnabledAt != nil {
+		challenge, err := createLoginChallenge(c.Request.Context(), user, deviceName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create login challenge"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Введите код из приложения-аутентификатора (POST /login/2fa)", "TwoFactorRequired": true, "ChallengeToken": challenge})
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	refreshToken, err := token.SignedString(jwtKey)
+	tokens, err := startSession(c, user, deviceName)
 	if err != nil {
-		return "", err
-	}
-
-	return refreshToken, nil
-}
-
-// Функция для генерации access token
-func generateAccessToken(user User) (string, error) {
-	claims := &Claims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     user.Role,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 12).Unix(), // accessToken срок жизни 12 часов
-		},
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session", "details": err.Error()})
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
+	// Пользователь, для которого 2FA обязательна, до её настройки может только подключить 2FA
+	required, err := twoFactorRequired(c.Request.Context(), user.Role)
 	if err != nil {
-		return "", err
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if required {
+		tokens["TwoFactorSetupRequired"] = true
 	}
 
-	return tokenString, nil
-
+	c.JSON(http.StatusOK, tokens)
 }
 
-// @Summary Аутентификация пользователя
-// @Description Аутентификация пользователя по имени и паролю и выдача access-токена
-// @Tags Аутентификация
-// @Accept json
-// @Produce json
-// @Param input body User true "Данные пользователя (имя и пароль)"
-// @Success 200 {object} map[string]string "Успешная аутентификация. Возвращает access-токен"
-// @Failure 400 {object} map[string]string "Некорректные входные данные"
-// @Failure 401 {object} map[string]string "Неверное имя пользователя или пароль"
-// @Failure 500 {object} map[string]string "Ошибка при генерации access-токена"
-// @Router /login [post]
-func loginUser(c *gin.Context) {
-	var req User
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
-		return
+// Новая сессия пользователя и ответ с её access- и refresh-токеном
+func startSession(c *gin.Context, user *User, deviceName string) (gin.H, error) {
+	session, err := createSession(c, store.Sessions, user, deviceName)
+	if err != nil {
+		return nil, err
 	}
 
-	var user User
-	if err := db.Where("username = ?", req.Username).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
-		return
+	accessToken, err := generateAccessToken(*user, session.JTI)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
-		return
-	}
-	accessToken, err := generateAccessToken(user)
+	refreshToken, err := issueRefreshToken(c.Request.Context(), store.RefreshTokens, session)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
-		return
+		return nil, err
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Вы успешно вошли", "AccessToken для всех последующих операций": accessToken})
-
+	return gin.H{"message": "Вы успешно вошли", "AccessToken для всех последующих операций": accessToken, "RefreshToken": refreshToken}, nil
 }
 
 // @Summary Создание проекта
@@ -388,41 +450,24 @@ func loginUser(c *gin.Context) {
 // @Success 201 {object} map[string]interface{} "Проект успешно создан"
 // @Failure 400 {object} map[string]string "Некорректный ввод данных"
 // @Failure 401 {object} map[string]string "Необходим авторизационный токен или неверный формат токена"
+// @Failure 403 {object} map[string]string "Нет права project.create"
 // @Failure 500 {object} map[string]string "Ошибка базы данных"
 // @Router /projects [post]
 func createProject(c *gin.Context) {
 	// Проверяем подключение к базе
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
 
-	// Получаем токен из заголовка
-	tokenString := c.GetHeader("Authorization")
-	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
-		return
-	}
-
-	// Убираем "Bearer " перед токеном
-	const prefix = "Bearer "
-	if len(tokenString) > len(prefix) && strings.HasPrefix(tokenString, prefix) {
-		tokenString = tokenString[len(prefix):]
-	}
-
-	// Разбираем JWT-токен
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
-
-	if err != nil || !token.Valid {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+	// Создавать проекты может роль сервиса с правом project.create
+	if !globalRoleCan(c.GetString("role"), PermProjectCreate) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied", "details": "requires " + PermProjectCreate})
 		return
 	}
 
-	// Получаем ID пользователя из токена
-	userID := claims.UserID
+	// ID пользователя из токена (проверен в authMiddleware)
+	userID := c.GetUint("id")
 
 	// Читаем JSON-запрос
 	var project Project
@@ -454,11 +499,12 @@ func createProject(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 4*time.Second)
 	defer cancel()
 
-	// Создаём проект в базе
-	if err := db.WithContext(ctx).Create(&project).Error; err != nil {
+	// Создаём проект в базе вместе с записью о владельце
+	if err := store.Projects.Create(ctx, &project, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
 		return
 	}
+	recordAudit(c, "project.create", "project", project.ID, project.ID, nil, &project)
 
 	// Возвращаем успешный ответ
 	c.JSON(http.StatusCreated, gin.H{"message": "Проект успешно создан", "Projects:": project})
@@ -478,7 +524,7 @@ func createProject(c *gin.Context) {
 // @Router /projects/{id} [get]
 func getProject(c *gin.Context) {
 	// Проверяем подключение к базе
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
@@ -495,11 +541,9 @@ func getProject(c *gin.Context) {
 	defer cancel()
 
 	// Ищем проект в базе данных
-	var project Project
-	err = db.WithContext(ctx).First(&project, projectID).Error
-
+	project, err := store.Projects.GetByID(ctx, uint(projectID))
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -512,7 +556,7 @@ func getProject(c *gin.Context) {
 }
 
 // @Summary Получение проектов пользователя
-// @Description Возвращает список проектов, созданных текущим пользователем
+// @Description Возвращает список проектов, в которых текущий пользователь является участником (с любой ролью)
 // @Tags Проекты
 // @Accept json
 // @Produce json
@@ -524,39 +568,13 @@ func getProject(c *gin.Context) {
 // @Router /user/projects [get]
 func getUserProjects(c *gin.Context) {
 	// Проверяем подключение к базе
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
 
-	// Получаем токен из заголовка Authorization
-	tokenString := c.GetHeader("Authorization")
-	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing token"})
-		return
-	}
-
-	// Убираем "Bearer " из строки токена
-	const prefix = "Bearer "
-	if len(tokenString) <= len(prefix) || !strings.HasPrefix(tokenString, prefix) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
-		return
-	}
-	tokenString = tokenString[len(prefix):]
-
-	// Разбираем токен и извлекаем данные
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
-
-	if err != nil || !token.Valid {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-		return
-	}
-
-	// Получаем UserID из токена
-	userID := claims.UserID
+	// Получаем UserID из токена (проверен в authMiddleware)
+	userID := c.GetUint("id")
 	if userID == 0 {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: missing user ID"})
 		return
@@ -566,9 +584,8 @@ func getUserProjects(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	// Ищем проекты, созданные пользователем
-	var projects []Project
-	err = db.WithContext(ctx).Where("assignee_id = ?", userID).Find(&projects).Error
+	// Ищем проекты, в которых пользователь состоит участником
+	projects, err := store.Projects.ListForMember(ctx, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
@@ -600,7 +617,7 @@ func getUserProjects(c *gin.Context) {
 // @Router /projects/{id} [put]
 func updateProject(c *gin.Context) {
 	// Проверяем подключение к базе
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
@@ -612,9 +629,12 @@ func updateProject(c *gin.Context) {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
 	// Ищем проект в базе
-	var project Project
-	if err := db.First(&project, id).Error; err != nil {
+	project, err := store.Projects.GetByID(ctx, uint(id))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
@@ -642,17 +662,18 @@ func updateProject(c *gin.Context) {
 		return
 	}
 
-	// Обновляем только переданные поля, ID оставляем старый
-	db.Model(&project).Updates(updatedData)
-
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	before := *project
+	// Меняются только название и описание: владельцы проекта задаются через участников,
+	// а assignee_id из запроса мог бы указать на любого пользователя
+	project.Name = updatedData.Name
+	project.Description = updatedData.Description
 
 	// Проверяем ошибки при обновлении
-	if err := db.WithContext(ctx).Save(&project).Error; err != nil {
+	if err := store.Projects.Update(ctx, project); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project: " + err.Error()})
 		return
 	}
+	recordAudit(c, "project.update", "project", project.ID, project.ID, &before, project)
 
 	// Отправляем успешный ответ
 	c.JSON(http.StatusOK, gin.H{"message": "Проект успешно обновлён", "Projects:": project})
@@ -665,7 +686,7 @@ func updateProject(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID проекта"
 // @Param Authorization header string true "Bearer токен"
-// @Param file formData file true "Файл для загрузки (до 100MB)"
+// @Param file formData file true "Файл для загрузки (лимит storage.max_upload_size, по умолчанию 100MB)"
 // @Success 200 {object} map[string]interface{} "Файл успешно загружен"
 // @Failure 400 {object} map[string]string "Некорректный запрос или ошибка загрузки файла"
 // @Failure 401 {object} map[string]string "Неавторизованный доступ"
@@ -686,21 +707,20 @@ func uploadProjectFile(c *gin.Context) {
 		return
 	}
 
-	// Валидация размера файла (например, ограничиваем до 10 MB)
-	const maxFileSize = 100 * 1024 * 1024 // 10 MB
-	if file.Size > maxFileSize {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File size exceeds the 100MB limit"})
+	// Валидация размера файла (лимит задаётся в конфигурации)
+	if file.Size > cfg.Storage.MaxUploadSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File size exceeds the %d bytes limit", cfg.Storage.MaxUploadSize)})
 		return
 	}
 
-	// Создаём папку uploads (если её нет)
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
+	// Создаём папку для загрузок (если её нет)
+	if err := os.MkdirAll(cfg.Storage.UploadDir, os.ModePerm); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
 		return
 	}
 
 	// Формируем путь к файлу
-	filePath := filepath.Join(uploadDir, fmt.Sprintf("project_%d_%s", projectID, file.Filename))
+	filePath := filepath.Join(cfg.Storage.UploadDir, fmt.Sprintf("project_%d_%s", projectID, file.Filename))
 
 	// Сохраняем файл
 	if err := c.SaveUploadedFile(file, filePath); err != nil {
@@ -712,10 +732,24 @@ func uploadProjectFile(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := db.WithContext(ctx).Model(&Project{}).Where("id = ?", projectID).Update("file_path", filePath).Error; err != nil {
+	previousPath, err := store.Projects.GetFilePath(ctx, uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Projects.SetFilePath(ctx, uint(projectID), filePath); err != nil {
+			return err
+		}
+		return recordActivity(ctx, tx, c, uint(projectID), 0, ActivityFileUploaded,
+			gin.H{"file_name": file.Filename, "size": file.Size})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update database with file path"})
 		return
 	}
+	recordAudit(c, "project.upload", "project", uint(projectID), uint(projectID),
+		gin.H{"file_path": previousPath}, gin.H{"file_path": filePath, "size": file.Size})
 	c.JSON(http.StatusOK, gin.H{"message": "Файл успешно загружен", "File_path": filePath})
 }
 
@@ -733,7 +767,7 @@ func uploadProjectFile(c *gin.Context) {
 // @Router /projects/{id}/download [get]
 func downloadProjectFile(c *gin.Context) {
 	// Проверяем подключение к базе
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
@@ -746,8 +780,7 @@ func downloadProjectFile(c *gin.Context) {
 	}
 
 	// Запрашиваем путь файла из БД
-	var filePath string
-	err = db.Raw("SELECT file_path FROM projects WHERE id = ?", projectID).Scan(&filePath).Error
+	filePath, err := store.Projects.GetFilePath(c.Request.Context(), uint(projectID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
 		return
@@ -782,7 +815,7 @@ func downloadProjectFile(c *gin.Context) {
 // @Router /projects/{id} [delete]
 func deleteProject(c *gin.Context) {
 	// Проверяем подключение к базе
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
@@ -794,10 +827,13 @@ func deleteProject(c *gin.Context) {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
 	// Ищем проект в базе
-	var project Project
-	if err := db.First(&project, projectID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	project, err := store.Projects.GetByID(ctx, uint(projectID))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
@@ -805,14 +841,12 @@ func deleteProject(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-	defer cancel()
-
 	// Удаляем проект
-	if err := db.WithContext(ctx).Delete(&project).Error; err != nil {
+	if err := store.Projects.Delete(ctx, project.ID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project: " + err.Error()})
 		return
 	}
+	recordAudit(c, "project.delete", "project", project.ID, project.ID, project, nil)
 
 	// Отправляем успешный ответ
 	c.JSON(http.StatusOK, gin.H{"message": "Проект успешно удалён"})
@@ -820,7 +854,7 @@ func deleteProject(c *gin.Context) {
 
 // Управление задачами
 // @Summary Создание задачи
-// @Description Создает новую задачу для проекта с привязкой к пользователю
+// @Description Создает новую задачу для проекта. Статус — один из статусов процесса проекта, по умолчанию первый статус категории todo. Исполнители задаются через assignee_id и/или assignee_ids (участники проекта), по умолчанию исполнитель — автор задачи. С parent_task_id задача создаётся как подзадача задачи того же проекта (вложенность не больше 5 уровней). custom_fields задаёт значения пользовательских полей проекта по имени поля; обязательные поля должны быть заданы.
 // @Tags Задачи
 // @Param id path int true "ID проекта"
 // @Param Authorization header string true "Bearer токен"
@@ -832,7 +866,7 @@ func deleteProject(c *gin.Context) {
 // @Router /projects/{id}/tasks [post]
 func createTask(c *gin.Context) {
 	var task Task
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
@@ -844,32 +878,8 @@ func createTask(c *gin.Context) {
 		return
 	}
 
-	// Получаем токен из заголовка
-	tokenString := c.GetHeader("Authorization")
-	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token required"})
-		return
-	}
-
-	// Убираем "Bearer " перед токеном
-	const prefix = "Bearer "
-	if len(tokenString) > len(prefix) && strings.HasPrefix(tokenString, prefix) {
-		tokenString = tokenString[len(prefix):]
-	}
-
-	// Разбираем JWT-токен
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
-
-	if err != nil || !token.Valid {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-		return
-	}
-
-	// Получаем ID пользователя из токена
-	userID := claims.UserID
+	// ID пользователя из токена (проверен в authMiddleware)
+	userID := c.GetUint("id")
 
 	// Привязываем JSON и проверяем обязательные поля
 	if err := c.ShouldBindJSON(&task); err != nil {
@@ -879,7 +889,15 @@ func createTask(c *gin.Context) {
 
 	// Устанавливаем ProjectID из параметра URL
 	task.ProjectID = uint(projectID)
-	task.AssigneeID = userID
+	// Задача попадает в серию только через настройку повторения
+	task.RecurrenceID = nil
+
+	// Исполнители из запроса, по умолчанию — автор задачи
+	assigneeIDs := collectAssigneeIDs(task.AssigneeID, task.AssigneeIDs)
+	if len(assigneeIDs) == 0 {
+		assigneeIDs = []uint{userID}
+	}
+	task.AssigneeID = assigneeIDs[0]
 
 	// Валидация данных
 	if err := validate.Struct(&task); err != nil {
@@ -890,24 +908,71 @@ func createTask(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 4*time.Second)
 	defer cancel()
 
-	// Сохраняем в базе
-	if err := db.WithContext(ctx).Create(&task).Error; err != nil {
+	// Статус должен быть в процессе проекта
+	workflow, err := store.Workflows.Get(ctx, task.ProjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if task.Status == "" {
+		task.Status = workflow.initialStatus()
+	}
+	if workflow.status(task.Status) == nil {
+		respondInvalidStatus(c, workflow)
+		return
+	}
+
+	// Исполнители должны быть участниками проекта
+	if err := validateAssignees(ctx, task.ProjectID, assigneeIDs); err != nil {
+		respondAssigneeError(c, err)
+		return
+	}
+
+	// Родительская задача должна быть в том же проекте
+	if err := validateParentTask(ctx, &task); err != nil {
+		respondParentError(c, err)
+		return
+	}
+
+	// Значения пользовательских полей проверяются по полям проекта
+	fields, err := resolveCustomFields(ctx, task.ProjectID, task.CustomFields, nil)
+	if err != nil {
+		respondCustomFieldError(c, err)
+		return
+	}
+	task.CustomFields = fields.Values
+
+	// Сохраняем в базе вместе с исполнителями, значениями полей и событием в ленте проекта
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Tasks.Create(ctx, &task, assigneeIDs); err != nil {
+			return err
+		}
+		if err := tx.CustomFields.SetValues(ctx, task.ID, fields.FieldIDs, fields.Rows); err != nil {
+			return err
+		}
+		return recordActivity(ctx, tx, c, task.ProjectID, task.ID, ActivityTaskCreated, gin.H{"title": task.Title})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task", "Err": err.Error()})
 		return
 	}
+	recordAudit(c, "task.create", "task", task.ID, task.ProjectID, nil, &task)
 
 	// Отправляем ответ с созданной задачей
 	c.JSON(http.StatusCreated, gin.H{"message": "Задача успешно создана", "Task": task})
 }
 
 // @Summary Получение задач проекта
-// @Description Получает список задач проекта с возможностью фильтрации по статусу, дедлайну и приоритету
+// @Description Получает список задач проекта с возможностью фильтрации по статусу (или категории статуса), дедлайну, приоритету и пользовательским полям: field.<имя>=<значение> (для multi_select — задача содержит вариант), для полей number и date также field.<имя>.min и field.<имя>.max. Задачи сортируются параметром sort, без значения поля — в конце. У каждой задачи возвращаются категория статуса (status_category) и выполнение (progress) по прямым подзадачам и чек-листу. С tree=true подзадачи вкладываются в родительские задачи (subtasks); задачи, чья родительская задача не попала под фильтр, возвращаются на верхнем уровне.
 // @Tags Задачи
 // @Param id path int true "ID проекта"
 // @Param Authorization header string true "Bearer токен"
-// @Param status query string false "Статус задачи (In_Progress, Done, In_Line)"
+// @Param status query string false "Статус задачи из процесса проекта (по умолчанию In_Progress, Done, In_Line)"
+// @Param category query string false "Категория статуса (todo, in_progress, done)"
 // @Param deadline query string false "Дедлайн задачи (формат: YYYY-MM-DD)"
 // @Param priority query string false "Приоритет задачи (High, Medium, Low)"
+// @Param tree query bool false "Вернуть задачи деревом"
+// @Param sort query string false "Порядок задач: id, title, deadline или field.<имя поля>, с '-' в начале — по убыванию (по умолчанию id)"
 // @Success 200 {object} map[string]interface{} "Список задач"
 // @Failure 400 {object} map[string]string "Ошибка валидации данных"
 // @Failure 404 {object} map[string]string "Задачи не найдены"
@@ -915,7 +980,7 @@ func createTask(c *gin.Context) {
 // @Router /projects/{id}/tasks [get]
 func getTasks(c *gin.Context) {
 	// Проверяем подключение к базе
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
@@ -929,12 +994,28 @@ func getTasks(c *gin.Context) {
 
 	// Получаем параметры фильтрации
 	status := c.Query("status")
+	category := c.Query("category")
 	deadline := c.Query("deadline")
 	priority := c.Query("priority")
 
-	// Валидация значений статуса и приоритета
-	if status != "" && !isValidStatus(status) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, allowed values are: In_Progress, Done, In_Line"})
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	// Статусы проверяются по процессу проекта
+	workflow, err := store.Workflows.Get(ctx, uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Валидация значений статуса, категории и приоритета
+	if status != "" && workflow.status(status) == nil {
+		respondInvalidStatus(c, workflow)
+		return
+	}
+
+	if category != "" && !slices.Contains(statusCategories, category) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category, allowed values are: " + strings.Join(statusCategories, ", ")})
 		return
 	}
 
@@ -943,38 +1024,48 @@ func getTasks(c *gin.Context) {
 		return
 	}
 
+	// Фильтр по статусу, категории, дедлайну и приоритету (пустые значения не учитываются)
+	filter := TaskFilter{Status: status, Category: category, Priority: priority}
+
 	// Валидация формата даты для deadline
 	if deadline != "" {
-		if _, err := time.Parse("2006-01-02", deadline); err != nil {
+		day, err := time.Parse("2006-01-02", deadline)
+		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deadline format, expected YYYY-MM-DD"})
 			return
 		}
+		filter.Deadline = day
 	}
 
-	// Инициализируем запрос
-	var tasks []Task
-	query := db.Where("project_id = ?", projectID)
-
-	// Применяем фильтрацию по статусу, если указано
-	if status != "" {
-		query = query.Where("status = ?", status)
+	// Условия и сортировка по пользовательским полям проекта
+	fields, err := store.CustomFields.List(ctx, uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
 	}
-
-	// Применяем фильтрацию по deadline, если указано
-	if deadline != "" {
-		query = query.Where("date_trunc('day', deadline) = ?", deadline)
+	filter.Fields, err = customFieldFilters(c.Request.URL.Query(), fields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-
-	// Применяем фильтрацию по приоритету, если указано
-	if priority != "" {
-		query = query.Where("priority = ?", priority)
+	filter.Sort, err = taskSortFromQuery(c.Query("sort"), fields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-	defer cancel()
+	tree := false
+	if raw := c.Query("tree"); raw != "" {
+		tree, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tree, expected true or false"})
+			return
+		}
+	}
 
 	// Выполняем запрос
-	if err := query.WithContext(ctx).Find(&tasks).Error; err != nil {
+	tasks, err := store.Tasks.List(ctx, uint(projectID), filter)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
 	}
@@ -985,47 +1076,77 @@ func getTasks(c *gin.Context) {
 		return
 	}
 
+	if err := store.Tasks.LoadAssignees(ctx, tasks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err := loadCustomFields(ctx, tasks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	views, err := newTaskViews(ctx, workflow, tasks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
 	// Возвращаем результат
-	c.JSON(http.StatusOK, gin.H{"Задачи": tasks})
+	if tree {
+		c.JSON(http.StatusOK, gin.H{"Задачи": buildTaskTree(views)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Задачи": views})
 }
 
 // @Summary Обновление задачи
-// @Description Обновляет задачу в проекте по ID, с проверкой обязательных полей и значений
+// @Description Обновляет задачу в проекте по ID, с проверкой обязательных полей и значений. parent_task_id переносит задачу в другую задачу проекта (null — на верхний уровень); задачу нельзя сделать подзадачей её собственных подзадач. Статус меняется только по переходу, разрешённому процессом проекта (409 со списком AllowedStatuses); если переход требует роль, участник с ролью ниже получает 403. Пока не завершены блокирующие задачи (blocked_by), задачу нельзя перевести в статус категории in_progress или done (409 со списком BlockedBy); мейнтейнеры и владельцы могут сделать это с force=true. Перевод текущей задачи серии повторяющихся задач в статус категории done повторяющихся задач сразу создаёт следующую задачу серии (NextTask в ответе); recurrence_id через тело запроса не меняется. custom_fields меняет только переданные поля, null очищает значение (обязательные поля очистить нельзя).
 // @Tags Задачи
 // @Param task_id path int true "ID задачи"
 // @Param Authorization header string true "Bearer токен"
 // @Param task body Task true "Обновленные данные задачи"
+// @Param force query bool false "Сменить статус, несмотря на незавершённые блокирующие задачи (право task.override_blockers)"
 // @Success 200 {object} map[string]interface{} "Информация об обновленной задаче"
 // @Failure 400 {object} map[string]string "Ошибка валидации данных"
 // @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 403 {object} map[string]string "Переход требует роль выше"
+// @Failure 409 {object} map[string]interface{} "Переход не разрешён или задачу блокируют незавершённые задачи"
 // @Failure 500 {object} map[string]string "Ошибка сервера"
 // @Router /projects/:id/tasks/:task_id [put]
 func updateTask(c *gin.Context) {
 	// Проверяем подключение к базе
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
 
-	// Получаем ID задачи из параметра "task_id"
-	taskID, err := strconv.Atoi(c.Param("task_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	// Задача проекта :id (найдена в permissionMiddleware)
+	found := c.MustGet("task").(*Task)
+	task := found.clone()
+
+	// Задача до изменения (с исполнителями) для истории и журнала аудита
+	before := []Task{*found}
+	if err := store.Tasks.LoadAssignees(ctx, before); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
 	}
-
-	// Ищем задачу в базе данных по taskID
-	var task Task
-	if err := db.First(&task, taskID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	if err := loadCustomFields(ctx, before); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
 	}
 
-	// Привязываем данные из JSON
+	// Привязываем данные из JSON (в CustomFields попадают только поля из запроса)
+	previousAssigneeID := task.AssigneeID
 	if err := c.ShouldBindJSON(&task); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	// Задачу нельзя перенести в другой проект или подменить её ID через тело запроса
+	task.ID = found.ID
+	task.ProjectID = found.ProjectID
+	task.RecurrenceID = found.RecurrenceID
 
 	// Валидация обязательных полей и значений
 	if task.Title == "" {
@@ -1033,31 +1154,104 @@ func updateTask(c *gin.Context) {
 		return
 	}
 
-	if !isValidStatus(task.Status) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, allowed values are: In_Progress, Done, In_Line"})
+	if !isValidPriority(task.Priority) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority, allowed values are: High, Medium, Low"})
 		return
 	}
 
-	if !isValidPriority(task.Priority) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority, allowed values are: High, Medium, Low"})
+	if err := validate.Var(task.EstimateMinutes, "omitempty,min=0,max=1000000"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate_minutes, allowed values are 0-1000000"})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
-	defer cancel()
+	// Исполнители меняются, только если переданы в запросе:
+	// assignee_ids задаёт полный список, одиночный assignee_id заменяет всех исполнителей
+	var assigneeIDs []uint
+	if task.AssigneeIDs != nil || task.AssigneeID != previousAssigneeID {
+		if task.AssigneeIDs != nil {
+			assigneeIDs = collectAssigneeIDs(0, task.AssigneeIDs)
+		} else {
+			assigneeIDs = collectAssigneeIDs(task.AssigneeID, nil)
+		}
+		if len(assigneeIDs) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "At least one assignee is required"})
+			return
+		}
+		if err := validateAssignees(ctx, task.ProjectID, assigneeIDs); err != nil {
+			respondAssigneeError(c, err)
+			return
+		}
+		task.AssigneeID = assigneeIDs[0]
+	}
+
+	// Статус меняется по переходам процесса проекта
+	workflow, err := store.Workflows.Get(ctx, task.ProjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if !checkStatusTransition(c, workflow, found.Status, task.Status) {
+		return
+	}
+
+	// Задачу нельзя начать или завершить, пока не завершены блокирующие её задачи
+	if task.Status != found.Status && workflow.category(task.Status) != StatusCategoryTodo {
+		if !checkTaskBlockers(c, ctx, &task) {
+			return
+		}
+	}
+
+	// Родительская задача проверяется, только если меняется
+	if !sameParentTask(found.ParentTaskID, task.ParentTaskID) {
+		if err := validateParentTask(ctx, &task); err != nil {
+			respondParentError(c, err)
+			return
+		}
+	}
+
+	// Если исполнители не меняются, в ответе и истории остаётся текущий список
+	if assigneeIDs == nil {
+		task.AssigneeIDs = before[0].AssigneeIDs
+	}
 
-	// Обновляем задачу в базе данных
-	if err := db.WithContext(ctx).Save(&task).Error; err != nil {
+	// Пользовательские поля меняются, только если переданы в запросе (null очищает значение)
+	fields, err := resolveCustomFields(ctx, task.ProjectID, task.CustomFields, before[0].CustomFields)
+	if err != nil {
+		respondCustomFieldError(c, err)
+		return
+	}
+	task.CustomFields = fields.Values
+
+	// Обновляем задачу в базе данных вместе со значениями полей и историей изменений
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Tasks.Update(ctx, &task, assigneeIDs); err != nil {
+			return err
+		}
+		if err := tx.CustomFields.SetValues(ctx, task.ID, fields.FieldIDs, fields.Rows); err != nil {
+			return err
+		}
+		return recordTaskChanges(ctx, tx, c, &before[0], &task)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task: " + err.Error()})
 		return
 	}
+	recordAudit(c, "task.update", "task", task.ID, task.ProjectID, &before[0], &task)
+
+	response := gin.H{"message": "Задача успешно обновлена", "Task": task}
+	// Завершение текущей задачи серии сразу создаёт следующую задачу серии
+	if task.RecurrenceID != nil && workflow.isDone(task.Status) && !workflow.isDone(found.Status) {
+		if next := completeRecurringTask(ctx, &task); next != nil {
+			response["NextTask"] = next
+		}
+	}
 
 	// Отправляем успешный ответ
-	c.JSON(http.StatusOK, gin.H{"message": "Задача успешно обновлена", "Task": task})
+	c.JSON(http.StatusOK, response)
 }
 
 // @Summary Удаление задачи
-// @Description Удаляет задачу из базы данных по ID
+// @Description Удаляет задачу из базы данных по ID вместе с её подзадачами и чек-листом
 // @Tags Задачи
 // @Param task_id path int true "ID задачи"
 // @Param Authorization header string true "Bearer токен"
@@ -1068,56 +1262,38 @@ func updateTask(c *gin.Context) {
 // @Router /tasks/{task_id} [delete]
 func deleteTask(c *gin.Context) {
 	// Проверяем подключение к базе данных
-	if db == nil {
+	if store == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection failed"})
 		return
 	}
 
-	// Получаем ID задачи и проверяем, является ли он числом
-	taskID, err := strconv.Atoi(c.Param("task_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID, must be a number"})
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+	before := []Task{*task}
+	if err := store.Tasks.LoadAssignees(ctx, before); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
 	}
-
-	// Проверяем, существует ли задача в базе данных
-	var task Task
-	if err := db.First(&task, taskID).Error; err != nil {
-		// Если задача не найдена, возвращаем ошибку
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
-		} else {
-			// Если произошла другая ошибка при поиске задачи
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
-		}
+	if err := loadCustomFields(ctx, before); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-	defer cancel()
-
 	// Удаляем задачу
-	if err := db.WithContext(ctx).Delete(&task).Error; err != nil {
+	if err := store.Tasks.Delete(ctx, task.ID); err != nil {
 		// Если возникла ошибка при удалении
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task: " + err.Error()})
 		return
 	}
+	recordAudit(c, "task.delete", "task", task.ID, task.ProjectID, &before[0], nil)
 
 	// Успешный ответ
 	c.JSON(http.StatusOK, gin.H{"message": "Задача успешно удалена"})
 }
 
-// Проверка допустимых значений для status
-func isValidStatus(status string) bool {
-	allowedStatuses := []string{"In_Progress", "Done", "In_Line"}
-	for _, s := range allowedStatuses {
-		if s == status {
-			return true
-		}
-	}
-	return false
-}
-
 // Проверка допустимых значений для priority
 func isValidPriority(priority string) bool {
 	allowedPriorities := []string{"High", "Medium", "Low"}
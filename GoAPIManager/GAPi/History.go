@@ -0,0 +1,292 @@
+package GoAPIManager
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Размер страницы ленты событий проекта
+const (
+	defaultActivityPageSize = 20
+	maxActivityPageSize     = 100
+)
+
+// Типы событий в ленте проекта
+const (
+	ActivityTaskCreated   = "task_created"
+	ActivityStatusChanged = "status_changed"
+	ActivityReassigned    = "reassigned"
+	ActivityCommented     = "commented"
+	ActivityFileUploaded  = "file_uploaded"
+)
+
+var activityTypes = []string{ActivityTaskCreated, ActivityStatusChanged, ActivityReassigned, ActivityCommented, ActivityFileUploaded}
+
+// Значение JSON, которое хранится в текстовой колонке
+type jsonValue json.RawMessage
+
+func (v jsonValue) Value() (driver.Value, error) {
+	if len(v) == 0 {
+		return "null", nil
+	}
+	return string(v), nil
+}
+
+func (v *jsonValue) Scan(value interface{}) error {
+	switch s := value.(type) {
+	case string:
+		*v = jsonValue(s)
+	case []byte:
+		*v = append(jsonValue(nil), s...)
+	default:
+		return fmt.Errorf("unsupported JSON value type %T", value)
+	}
+	return nil
+}
+
+func (v jsonValue) MarshalJSON() ([]byte, error) {
+	if len(v) == 0 {
+		return []byte("null"), nil
+	}
+	return v, nil
+}
+
+// Изменение одного поля задачи
+type TaskHistory struct {
+	ID       uint      `gorm:"primaryKey" json:"id"`
+	TaskID   uint      `gorm:"not null" json:"task_id"`
+	ActorID  *uint     `json:"actor_id"`
+	Actor    string    `gorm:"not null" json:"actor"`
+	Field    string    `gorm:"not null" json:"field"`
+	OldValue jsonValue `gorm:"not null;type:text" json:"old_value"`
+	NewValue jsonValue `gorm:"not null;type:text" json:"new_value"`
+	// Время изменения
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (TaskHistory) TableName() string {
+	return "task_history"
+}
+
+// Событие в ленте проекта. Details зависят от типа: название задачи, старое и новое значение,
+// ID комментария, имя файла.
+type Activity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProjectID uint      `gorm:"not null" json:"project_id"`
+	TaskID    *uint     `json:"task_id"`
+	ActorID   *uint     `json:"actor_id"`
+	Actor     string    `gorm:"not null" json:"actor"`
+	Type      string    `gorm:"not null" json:"type"`
+	Details   jsonValue `gorm:"not null;type:text" json:"details"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Activity) TableName() string {
+	return "project_activities"
+}
+
+// Поля задачи, которые не попадают в историю (не меняются после создания)
+var taskHistoryIgnored = []string{"ID", "ProjectID"}
+
+// Страница списка с курсором из параметров запроса cursor и limit. Курсор — ID последнего
+// элемента предыдущей страницы (0 — первая страница). При некорректных параметрах отвечает 400
+// и возвращает false.
+func cursorPage(c *gin.Context, defaultLimit, maxLimit int) (uint, int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit < 1 || limit > maxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid limit, allowed values are 1-%d", maxLimit)})
+		return 0, 0, false
+	}
+
+	var before uint64
+	if cursor := c.Query("cursor"); cursor != "" {
+		before, err = strconv.ParseUint(cursor, 10, 64)
+		if err != nil || before == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return 0, 0, false
+		}
+	}
+	return uint(before), limit, true
+}
+
+// Курсор следующей страницы после элемента с ID id
+func formatCursor(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// Пользователь, выполняющий запрос (nil — запрос без аутентификации)
+func requestActor(c *gin.Context) *uint {
+	if id := c.GetUint("id"); id != 0 {
+		return &id
+	}
+	return nil
+}
+
+// Запись события в ленту проекта (taskID 0 — событие не связано с задачей)
+func recordActivity(ctx context.Context, tx *Store, c *gin.Context, projectID, taskID uint, kind string, details gin.H) error {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	activity := Activity{
+		ProjectID: projectID,
+		ActorID:   requestActor(c),
+		Actor:     c.GetString("username"),
+		Type:      kind,
+		Details:   raw,
+	}
+	if taskID != 0 {
+		activity.TaskID = &taskID
+	}
+	return tx.Activity.Create(ctx, &activity)
+}
+
+// Запись изменений задачи: история по каждому изменённому полю и события в ленте проекта
+// при смене статуса или исполнителей. before и after должны содержать исполнителей
+// (AssigneeIDs).
+func recordTaskChanges(ctx context.Context, tx *Store, c *gin.Context, before, after *Task) error {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+	// Пользовательские поля попадают в историю по отдельности: custom_fields.<имя поля>
+	if change, ok := changes["custom_fields"]; ok {
+		delete(changes, "custom_fields")
+		fieldsBefore, _ := change.Before.(map[string]interface{})
+		fieldsAfter, _ := change.After.(map[string]interface{})
+		fieldChanges, err := auditDiff(fieldsBefore, fieldsAfter)
+		if err != nil {
+			return err
+		}
+		for name, fieldChange := range fieldChanges {
+			changes["custom_fields."+name] = fieldChange
+		}
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		if !slices.Contains(taskHistoryIgnored, field) {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	now := time.Now()
+	entries := make([]TaskHistory, 0, len(fields))
+	for _, field := range fields {
+		oldValue, err := json.Marshal(changes[field].Before)
+		if err != nil {
+			return err
+		}
+		newValue, err := json.Marshal(changes[field].After)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, TaskHistory{
+			TaskID:    after.ID,
+			ActorID:   requestActor(c),
+			Actor:     c.GetString("username"),
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			CreatedAt: now,
+		})
+	}
+	if err := tx.TaskHistory.Create(ctx, entries); err != nil {
+		return err
+	}
+
+	if change, ok := changes["status"]; ok {
+		if err := recordActivity(ctx, tx, c, after.ProjectID, after.ID, ActivityStatusChanged,
+			gin.H{"title": after.Title, "from": change.Before, "to": change.After}); err != nil {
+			return err
+		}
+	}
+	if change, ok := changes["assignee_ids"]; ok {
+		if err := recordActivity(ctx, tx, c, after.ProjectID, after.ID, ActivityReassigned,
+			gin.H{"title": after.Title, "from": change.Before, "to": change.After}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// @Summary История задачи
+// @Description Возвращает изменения задачи по полям (новые первыми): кто и когда изменил поле, старое и новое значение
+// @Tags Задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "История задачи"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/history [get]
+func getTaskHistory(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	history, err := store.TaskHistory.ListForTask(c.Request.Context(), task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"TaskID": task.ID, "History": history})
+}
+
+// @Summary Лента событий проекта
+// @Description Возвращает события проекта (новые первыми): создание задач, смену статуса, смену исполнителей, комментарии, загрузку файлов. Следующая страница запрашивается с cursor из NextCursor предыдущего ответа; пустой NextCursor — событий больше нет.
+// @Tags Проекты
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param type query string false "Тип события (task_created, status_changed, reassigned, commented, file_uploaded)"
+// @Param cursor query string false "Курсор следующей страницы (NextCursor)"
+// @Param limit query int false "Размер страницы (по умолчанию 20, не больше 100)"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "События и курсор следующей страницы"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/activity [get]
+func getProjectActivity(c *gin.Context) {
+	before, limit, ok := cursorPage(c, defaultActivityPageSize, maxActivityPageSize)
+	if !ok {
+		return
+	}
+
+	filter := ActivityFilter{Type: c.Query("type"), Before: before, Limit: limit + 1}
+	if filter.Type != "" && !slices.Contains(activityTypes, filter.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type, allowed values are: " + strings.Join(activityTypes, ", ")})
+		return
+	}
+
+	// Проект :id (найден в permissionMiddleware)
+	project := c.MustGet("project").(*Project)
+	activities, err := store.Activity.List(c.Request.Context(), project.ID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Лишнее событие показывает, что есть следующая страница
+	nextCursor := ""
+	if len(activities) > limit {
+		activities = activities[:limit]
+		nextCursor = formatCursor(activities[limit-1].ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Activity": activities, "NextCursor": nextCursor})
+}
@@ -0,0 +1,156 @@
+package GoAPIManager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// История задачи: поле -> записи изменений этого поля (новые первыми)
+func (s *testServer) taskHistory(token string, projectID, taskID uint) map[string][]map[string]interface{} {
+	s.t.Helper()
+	out := s.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/projects/%d/tasks/%d/history", projectID, taskID), token, nil)
+	entries, _ := out["History"].([]interface{})
+	byField := map[string][]map[string]interface{}{}
+	for _, e := range entries {
+		entry := e.(map[string]interface{})
+		field := entry["field"].(string)
+		byField[field] = append(byField[field], entry)
+	}
+	return byField
+}
+
+func jsonEqual(t *testing.T, got, want interface{}) bool {
+	t.Helper()
+	g, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(g) == string(w)
+}
+
+func TestTaskHistoryFieldDiffs(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	s.user("bobby")
+	projectID := s.project(alice, "Alpha")
+	s.addMember(alice, projectID, "bobby", ProjectRoleMember)
+
+	deadline := time.Now().AddDate(0, 0, 7).UTC().Truncate(time.Second).Format(time.RFC3339)
+	task := gin.H{"title": "First", "priority": "High", "status": "In_Line", "deadline": deadline}
+	out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), alice, task)
+	taskID := uint(field(t, out, "Task", "ID").(float64))
+	path := fmt.Sprintf("/projects/%d/tasks/%d", projectID, taskID)
+	if history := s.taskHistory(alice, projectID, taskID); len(history) != 0 {
+		t.Fatalf("history of a new task = %v", history)
+	}
+
+	// Запоминаются только изменённые поля, со старым и новым значением
+	task["title"], task["status"] = "First (edited)", "In_Progress"
+	s.expect(http.StatusOK, http.MethodPut, path, alice, task)
+	history := s.taskHistory(alice, projectID, taskID)
+	fields := make([]string, 0, len(history))
+	for f := range history {
+		fields = append(fields, f)
+	}
+	slices.Sort(fields)
+	if !slices.Equal(fields, []string{"status", "title"}) {
+		t.Fatalf("changed fields = %v, want [status title]", fields)
+	}
+	title := history["title"][0]
+	if title["old_value"] != "First" || title["new_value"] != "First (edited)" || title["actor"] != "alice" {
+		t.Errorf("title change = %v", title)
+	}
+	if status := history["status"][0]; status["old_value"] != "In_Line" || status["new_value"] != "In_Progress" {
+		t.Errorf("status change = %v", status)
+	}
+
+	// Запрос без изменений историю не пополняет
+	s.expect(http.StatusOK, http.MethodPut, path, alice, task)
+	if again := s.taskHistory(alice, projectID, taskID); len(again["title"]) != 1 || len(again["status"]) != 1 {
+		t.Errorf("no-op update recorded: %v", again)
+	}
+
+	// Смена исполнителей записывается списком ID
+	bob, err := store.Users.GetByUsername(context.Background(), "bobby")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.expect(http.StatusOK, http.MethodPost, path+"/assign", alice, gin.H{"assignee_ids": []uint{bob.ID}})
+	assignees := s.taskHistory(alice, projectID, taskID)["assignee_ids"]
+	if len(assignees) != 1 || !jsonEqual(t, assignees[0]["new_value"], []uint{bob.ID}) {
+		t.Errorf("assignee_ids change = %v, want new value [%d]", assignees, bob.ID)
+	}
+}
+
+func TestProjectActivityPagination(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	var taskIDs []uint
+	for i := range 5 {
+		taskIDs = append(taskIDs, s.task(alice, projectID, fmt.Sprintf("Task %d", i)))
+	}
+	deadline := time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339)
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("/projects/%d/tasks/%d", projectID, taskIDs[0]), alice,
+		gin.H{"title": "Task 0", "priority": "High", "status": "Done", "deadline": deadline})
+
+	activity := fmt.Sprintf("/projects/%d/activity", projectID)
+	var ids []float64
+	var types []string
+	cursor, pages := "", 0
+	for {
+		path := activity + "?limit=2"
+		if cursor != "" {
+			path += "&cursor=" + cursor
+		}
+		out := s.expect(http.StatusOK, http.MethodGet, path, alice, nil)
+		page, _ := out["Activity"].([]interface{})
+		if len(page) > 2 {
+			t.Fatalf("page of %d events with limit 2", len(page))
+		}
+		for _, e := range page {
+			event := e.(map[string]interface{})
+			ids = append(ids, event["id"].(float64))
+			types = append(types, event["type"].(string))
+		}
+		pages++
+		if cursor, _ = out["NextCursor"].(string); cursor == "" {
+			break
+		}
+		if pages > 10 {
+			t.Fatal("pagination does not end")
+		}
+	}
+
+	// Все события ровно один раз, новые первыми
+	if len(ids) != 6 || pages != 3 {
+		t.Fatalf("%d events on %d pages, want 6 on 3", len(ids), pages)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] >= ids[i-1] {
+			t.Errorf("events out of order: %v", ids)
+		}
+	}
+	if types[0] != ActivityStatusChanged || slices.Index(types[1:], ActivityStatusChanged) != -1 {
+		t.Errorf("event types = %v, want the status change first", types)
+	}
+
+	out := s.expect(http.StatusOK, http.MethodGet, activity+"?type="+ActivityStatusChanged, alice, nil)
+	if events, _ := out["Activity"].([]interface{}); len(events) != 1 || out["NextCursor"] != "" {
+		t.Errorf("status_changed events = %v", out)
+	}
+	for _, query := range []string{"?limit=0", "?limit=101", "?cursor=abc", "?cursor=0", "?type=unknown"} {
+		s.expect(http.StatusBadRequest, http.MethodGet, activity+query, alice, nil)
+	}
+}
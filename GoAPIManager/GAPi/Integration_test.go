@@ -0,0 +1,226 @@
+package GoAPIManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Пароль всех тестовых пользователей
+const testPassword = "secret1"
+
+// Тестовый сервер: полный роутер поверх SQLite в памяти с применёнными миграциями
+type testServer struct {
+	t      *testing.T
+	router *gin.Engine
+}
+
+// Новый сервер со свежей базой. Состояние пакета (cfg, store, ключи подписи) заменяется,
+// поэтому тесты с сервером не запускаются параллельно.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	c := defaultConfig()
+	c.Database.Driver = "sqlite"
+	c.Database.Path = ":memory:"
+	c.Auth.KeysDir = t.TempDir()
+	c.Server.RateLimit = 100000
+	if _, err := GenerateSigningKey(c.Auth.KeysDir, "EdDSA"); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadSigningKeys(c.Auth.KeysDir); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := OpenStore(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := db
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return &testServer{t: t, router: NewRouter(s)}
+}
+
+// Запрос к серверу; body кодируется в JSON, token передаётся как Bearer
+func (s *testServer) request(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// Запрос с проверкой кода ответа; возвращает разобранное тело ответа
+func (s *testServer) expect(status int, method, path, token string, body interface{}) map[string]interface{} {
+	s.t.Helper()
+	w := s.request(method, path, token, body)
+	if w.Code != status {
+		s.t.Fatalf("%s %s: status %d, want %d: %s", method, path, w.Code, status, w.Body.String())
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil && w.Body.Len() > 0 {
+		s.t.Fatalf("%s %s: invalid JSON: %s", method, path, w.Body.String())
+	}
+	return out
+}
+
+func (s *testServer) register(username string) {
+	s.t.Helper()
+	s.expect(http.StatusCreated, http.MethodPost, "/register", "", gin.H{"username": username, "password": testPassword, "role": "User"})
+}
+
+// Вход по паролю; возвращает тело ответа с токенами
+func (s *testServer) loginTokens(username string) map[string]interface{} {
+	s.t.Helper()
+	return s.expect(http.StatusOK, http.MethodPost, "/login", "", gin.H{"username": username, "password": testPassword})
+}
+
+// Регистрация и вход; возвращает access-токен
+func (s *testServer) user(username string) string {
+	s.t.Helper()
+	s.register(username)
+	return accessToken(s.t, s.loginTokens(username))
+}
+
+func accessToken(t *testing.T, tokens map[string]interface{}) string {
+	t.Helper()
+	token, _ := tokens["AccessToken для всех последующих операций"].(string)
+	if token == "" {
+		t.Fatalf("no access token in %v", tokens)
+	}
+	return token
+}
+
+// Создание проекта; возвращает его ID
+func (s *testServer) project(token, name string) uint {
+	s.t.Helper()
+	out := s.expect(http.StatusCreated, http.MethodPost, "/projects", token, gin.H{"name": name, "description": "test"})
+	return uint(field(s.t, out, "Projects:", "ID").(float64))
+}
+
+// Создание задачи с дедлайном через неделю; возвращает её ID
+func (s *testServer) task(token string, projectID uint, title string) uint {
+	s.t.Helper()
+	out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), token, gin.H{
+		"title": title, "priority": "High", "status": "In_Line", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+	})
+	return uint(field(s.t, out, "Task", "ID").(float64))
+}
+
+// Значение по пути ключей во вложенных объектах ответа
+func field(t *testing.T, out map[string]interface{}, keys ...string) interface{} {
+	t.Helper()
+	var v interface{} = out
+	for _, key := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			t.Fatalf("no %q in %v", key, out)
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	s := newTestServer(t)
+	s.register("alice")
+	s.expect(http.StatusBadRequest, http.MethodPost, "/register", "", gin.H{"username": "alice", "password": testPassword, "role": "User"})
+	s.expect(http.StatusBadRequest, http.MethodPost, "/register", "", gin.H{"username": "bob"})
+
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/login", "", gin.H{"username": "alice", "password": "wrong-password"})
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/login", "", gin.H{"username": "nobody", "password": testPassword})
+	token := accessToken(t, s.loginTokens("alice"))
+
+	s.expect(http.StatusNotFound, http.MethodGet, "/user/projects", token, nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/projects", "", nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/projects", "not-a-token", nil)
+}
+
+func TestProjectCRUD(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	bob := s.user("bobby")
+
+	id := s.project(alice, "Alpha")
+	path := fmt.Sprintf("/projects/%d", id)
+
+	out := s.expect(http.StatusOK, http.MethodGet, path, alice, nil)
+	if name := field(t, out, "Projects:", "Name"); name != "Alpha" {
+		t.Errorf("name = %v, want Alpha", name)
+	}
+	// Пользователь не из проекта не видит и не меняет его
+	s.expect(http.StatusForbidden, http.MethodGet, path, bob, nil)
+
+	out = s.expect(http.StatusOK, http.MethodPut, path, alice, gin.H{"name": "Beta", "description": "renamed"})
+	if name := field(t, out, "Projects:", "Name"); name != "Beta" {
+		t.Errorf("updated name = %v, want Beta", name)
+	}
+	out = s.expect(http.StatusOK, http.MethodGet, "/user/projects", alice, nil)
+	if projects, _ := out["Projects:"].([]interface{}); len(projects) != 1 {
+		t.Errorf("user projects = %v, want 1 project", out["Projects:"])
+	}
+
+	s.expect(http.StatusForbidden, http.MethodDelete, path, bob, nil)
+	s.expect(http.StatusOK, http.MethodDelete, path, alice, nil)
+	s.expect(http.StatusNotFound, http.MethodGet, path, alice, nil)
+}
+
+func TestTaskCRUD(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	tasks := fmt.Sprintf("/projects/%d/tasks", projectID)
+
+	s.expect(http.StatusBadRequest, http.MethodPost, tasks, alice, gin.H{"title": "No priority"})
+	first := s.task(alice, projectID, "First")
+	s.task(alice, projectID, "Second")
+
+	out := s.expect(http.StatusOK, http.MethodGet, tasks, alice, nil)
+	if list, _ := out["Задачи"].([]interface{}); len(list) != 2 {
+		t.Fatalf("tasks = %v, want 2", out["Задачи"])
+	}
+
+	path := fmt.Sprintf("%s/%d", tasks, first)
+	deadline := time.Now().AddDate(0, 0, 3).UTC().Format(time.RFC3339)
+	out = s.expect(http.StatusOK, http.MethodPut, path, alice, gin.H{
+		"title": "First (edited)", "priority": "Low", "status": "In_Progress", "deadline": deadline,
+	})
+	if status := field(t, out, "Task", "status"); status != "In_Progress" {
+		t.Errorf("status = %v, want In_Progress", status)
+	}
+
+	out = s.expect(http.StatusOK, http.MethodGet, tasks+"?status=In_Progress", alice, nil)
+	list, _ := out["Задачи"].([]interface{})
+	if len(list) != 1 || list[0].(map[string]interface{})["title"] != "First (edited)" {
+		t.Errorf("filtered tasks = %v, want the edited task", out["Задачи"])
+	}
+	s.expect(http.StatusBadRequest, http.MethodGet, tasks+"?priority=Urgent", alice, nil)
+
+	s.expect(http.StatusOK, http.MethodDelete, path, alice, nil)
+	s.expect(http.StatusNotFound, http.MethodPut, path, alice, gin.H{"title": "Gone", "priority": "Low"})
+	out = s.expect(http.StatusOK, http.MethodGet, tasks, alice, nil)
+	if list, _ := out["Задачи"].([]interface{}); len(list) != 1 {
+		t.Errorf("tasks after delete = %v, want 1", out["Задачи"])
+	}
+}
@@ -0,0 +1,423 @@
+package GoAPIManager
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Ключ подписи JWT. Каждый ключ хранится в каталоге auth.keys_dir в файле <kid>.pem
+// (закрытый ключ в формате PKCS#8). Идентификатор начинается с времени создания,
+// поэтому самый новый ключ — последний по алфавиту: им подписываются новые токены,
+// а остальные ключи каталога продолжают проверять ранее выданные токены.
+type signingKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// Набор загруженных ключей
+type keySet struct {
+	dir      string
+	keys     map[string]*signingKey
+	active   *signingKey
+	loadedAt time.Time
+}
+
+// Открытый ключ в формате JWK (RFC 7517, RFC 8037)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+const (
+	keyFileExt        = ".pem"
+	retiredKeysDir    = "retired"
+	rsaKeyBits        = 2048
+	keyReloadInterval = 30 * time.Second
+)
+
+// Алгоритмы, для которых можно создать ключ
+var signingAlgorithms = []string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}
+
+var errNoSigningKeys = errors.New("no signing keys")
+
+// Текущий набор ключей (заменяется целиком при перечитывании каталога)
+var signingKeys atomic.Pointer[keySet]
+
+var keyReloadMu sync.Mutex
+
+// Метод подписи для закрытого ключа
+func signingMethodFor(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < rsaKeyBits {
+			return nil, fmt.Errorf("RSA key is too short (%d bits)", k.N.BitLen())
+		}
+		return jwt.SigningMethodRS256, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// Чтение ключа из PEM-файла
+func readSigningKey(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s: expected PKCS#8 PEM block \"PRIVATE KEY\"", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported key type %T", path, parsed)
+	}
+	method, err := signingMethodFor(private)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &signingKey{
+		ID:      strings.TrimSuffix(filepath.Base(path), keyFileExt),
+		Method:  method,
+		Private: private,
+		Public:  private.Public(),
+	}, nil
+}
+
+// Чтение всех ключей каталога (подкаталоги, в том числе retired/, не читаются)
+func readKeyDir(dir string) (*keySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	ks := &keySet{dir: dir, keys: make(map[string]*signingKey), loadedAt: time.Now()}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != keyFileExt {
+			continue
+		}
+		key, err := readSigningKey(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[key.ID] = key
+		if ks.active == nil || key.ID > ks.active.ID {
+			ks.active = key
+		}
+	}
+	return ks, nil
+}
+
+// Ключи в порядке создания
+func (ks *keySet) sorted() []*signingKey {
+	keys := make([]*signingKey, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
+
+// LoadSigningKeys загружает ключи подписи JWT из каталога dir.
+// В каталоге должен быть хотя бы один ключ (см. команду `keys generate`).
+func LoadSigningKeys(dir string) error {
+	ks, err := readKeyDir(dir)
+	if err != nil {
+		return err
+	}
+	if ks.active == nil {
+		return fmt.Errorf("%w in %s (run `keys init`)", errNoSigningKeys, dir)
+	}
+	signingKeys.Store(ks)
+	return nil
+}
+
+// GenerateSigningKey создаёт в каталоге dir новый ключ для алгоритма alg (EdDSA или RS256)
+// и возвращает его идентификатор. Новый ключ становится активным после загрузки каталога.
+func GenerateSigningKey(dir, alg string) (string, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case jwt.SigningMethodEdDSA.Alg():
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case jwt.SigningMethodRS256.Alg():
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	default:
+		return "", fmt.Errorf("unsupported signing algorithm %q (expected one of %v)", alg, signingAlgorithms)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	kid := fmt.Sprintf("%s%09d-%s", now.Format("20060102T150405"), now.Nanosecond(), hex.EncodeToString(suffix))
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, kid+keyFileExt), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if err := pem.Encode(f, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		f.Close()
+		return "", err
+	}
+	return kid, f.Close()
+}
+
+// Подпись токена активным ключом, идентификатор ключа передаётся в заголовке kid
+func signToken(claims jwt.Claims) (string, error) {
+	ks := signingKeys.Load()
+	if ks == nil || ks.active == nil {
+		return "", errNoSigningKeys
+	}
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.ID
+	return token.SignedString(ks.active.Private)
+}
+
+// Разбор и проверка подписи токена
+func parseToken(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, verificationKey)
+}
+
+// Ключ для проверки подписи: ищется по kid, алгоритм токена должен совпадать с типом ключа.
+// Без этой проверки токен с alg=HS256, подписанный открытым ключом, прошёл бы проверку.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid header")
+	}
+	key := findVerificationKey(kid)
+	if key == nil {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+	return key.Public, nil
+}
+
+// Поиск ключа по kid. Неизвестный ключ мог появиться после ротации на другом экземпляре
+// сервера с общим каталогом ключей, поэтому каталог перечитывается, но не чаще keyReloadInterval.
+func findVerificationKey(kid string) *signingKey {
+	ks := signingKeys.Load()
+	if ks == nil {
+		return nil
+	}
+	if key, ok := ks.keys[kid]; ok {
+		return key
+	}
+	if time.Since(ks.loadedAt) < keyReloadInterval {
+		return nil
+	}
+
+	keyReloadMu.Lock()
+	defer keyReloadMu.Unlock()
+	if current := signingKeys.Load(); current != ks {
+		// Каталог уже перечитан параллельным запросом
+		return current.keys[kid]
+	}
+
+	next, err := readKeyDir(ks.dir)
+	if err != nil || next.active == nil {
+		log.Printf("[KEYS] could not reload signing keys from %s: %v", ks.dir, err)
+		retry := *ks
+		retry.loadedAt = time.Now()
+		signingKeys.Store(&retry)
+		return nil
+	}
+	signingKeys.Store(next)
+	if next.active != ks.active {
+		log.Printf("[KEYS] signing keys reloaded, active key %s", next.active.ID)
+	}
+	return next.keys[kid]
+}
+
+// Открытый ключ в формате JWK
+func (k *signingKey) jwk() jwk {
+	key := jwk{Kid: k.ID, Use: "sig", Alg: k.Method.Alg()}
+	switch pub := k.Public.(type) {
+	case ed25519.PublicKey:
+		key.Kty, key.Crv = "OKP", "Ed25519"
+		key.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	}
+	return key
+}
+
+// @Summary Открытые ключи подписи токенов (JWKS)
+// @Description Возвращает открытые ключи, которыми проверяются access-токены этого сервера (RFC 7517). Ключ выбирается по заголовку kid токена. Другие сервисы могут проверять токены без общего секрета.
+// @Tags Аутентификация
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Набор ключей в поле keys"
+// @Router /.well-known/jwks.json [get]
+func getJWKS(c *gin.Context) {
+	keys := []jwk{}
+	if ks := signingKeys.Load(); ks != nil {
+		for _, key := range ks.sorted() {
+			keys = append(keys, key.jwk())
+		}
+	}
+
+	// Клиенты могут кешировать ключи; новый ключ публикуется сразу после ротации
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+// Keys выполняет подкоманду `keys init|generate|list|retire`
+func Keys(args []string) {
+	if len(args) == 0 {
+		keysUsage()
+	}
+
+	dir := cfg.Auth.KeysDir
+	var err error
+	switch args[0] {
+	case "init":
+		err = keysInit(dir)
+	case "generate":
+		err = keysGenerate(dir)
+	case "list":
+		err = keysList(dir)
+	case "retire":
+		if len(args) != 2 {
+			keysUsage()
+		}
+		err = keysRetire(dir, args[1])
+	default:
+		keysUsage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
+	}
+}
+
+// Создание первого ключа, если каталог пуст
+func keysInit(dir string) error {
+	ks, err := readKeyDir(dir)
+	if err != nil {
+		return err
+	}
+	if ks.active != nil {
+		fmt.Printf("Ключи уже есть, активный ключ %s\n", ks.active.ID)
+		return nil
+	}
+	return keysGenerate(dir)
+}
+
+// Создание нового активного ключа (ротация)
+func keysGenerate(dir string) error {
+	kid, err := GenerateSigningKey(dir, cfg.Auth.SigningAlg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Создан ключ %s (%s), он станет активным после перезапуска сервера\n", kid, cfg.Auth.SigningAlg)
+	return nil
+}
+
+// Вывод ключей каталога
+func keysList(dir string) error {
+	ks, err := readKeyDir(dir)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KID\tALG\tSTATE")
+	for _, key := range ks.sorted() {
+		state := "verify"
+		if key == ks.active {
+			state = "active"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", key.ID, key.Method.Alg(), state)
+	}
+	return w.Flush()
+}
+
+// Вывод ключа из обращения: файл переносится в подкаталог retired/,
+// токены, подписанные этим ключом, перестают приниматься после перезапуска сервера
+func keysRetire(dir, kid string) error {
+	ks, err := readKeyDir(dir)
+	if err != nil {
+		return err
+	}
+	key, ok := ks.keys[kid]
+	if !ok {
+		return fmt.Errorf("key %q not found in %s", kid, dir)
+	}
+	if key == ks.active {
+		return fmt.Errorf("key %q is the active signing key, generate a new key first", kid)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, retiredKeysDir), 0o700); err != nil {
+		return err
+	}
+	name := kid + keyFileExt
+	if err := os.Rename(filepath.Join(dir, name), filepath.Join(dir, retiredKeysDir, name)); err != nil {
+		return err
+	}
+	fmt.Printf("Ключ %s выведен из обращения\n", kid)
+	return nil
+}
+
+func keysUsage() {
+	fmt.Fprintln(os.Stderr, `Использование: keys <команда>
+  init        создать ключ подписи, если в каталоге ещё нет ключей
+  generate    создать новый ключ; он становится активным, старые ключи продолжают проверять токены
+  list        показать ключи каталога
+  retire KID  вывести старый ключ из обращения (перенести в retired/)`)
+	os.Exit(2)
+}
@@ -0,0 +1,195 @@
+package GoAPIManager
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Каталог с новыми ключами для алгоритмов algs; набор ключей пакета восстанавливается после теста
+func testKeyDir(t *testing.T, algs ...string) string {
+	t.Helper()
+	previous := signingKeys.Load()
+	t.Cleanup(func() { signingKeys.Store(previous) })
+
+	dir := t.TempDir()
+	for _, alg := range algs {
+		if _, err := GenerateSigningKey(dir, alg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := LoadSigningKeys(dir); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func testClaims() *Claims {
+	return &Claims{UserID: 1, Username: "alice", StandardClaims: jwt.StandardClaims{
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}}
+}
+
+func TestSignAndParseToken(t *testing.T) {
+	for _, alg := range signingAlgorithms {
+		t.Run(alg, func(t *testing.T) {
+			testKeyDir(t, alg)
+			signed, err := signToken(testClaims())
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims := &Claims{}
+			token, err := parseToken(signed, claims)
+			if err != nil || !token.Valid {
+				t.Fatalf("parseToken: %v", err)
+			}
+			if token.Header["kid"] != signingKeys.Load().active.ID || token.Method.Alg() != alg {
+				t.Errorf("header = %v, want kid of the active key and alg %s", token.Header, alg)
+			}
+			if claims.Username != "alice" {
+				t.Errorf("username = %q", claims.Username)
+			}
+		})
+	}
+}
+
+func TestParseTokenRequiresKnownKid(t *testing.T) {
+	testKeyDir(t, "EdDSA")
+	active := signingKeys.Load().active
+
+	for name, kid := range map[string]interface{}{
+		"no kid":      nil,
+		"empty kid":   "",
+		"unknown kid": "20000101T000000000000000-deadbeef",
+		"kid number":  42,
+	} {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, testClaims())
+		if kid != nil {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(active.Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parseToken(signed, &Claims{}); err == nil {
+			t.Errorf("%s: token accepted", name)
+		}
+	}
+}
+
+func TestParseTokenRejectsAlgorithmMismatch(t *testing.T) {
+	testKeyDir(t, "EdDSA", "RS256")
+	var ed, rs *signingKey
+	for _, key := range signingKeys.Load().keys {
+		if key.Method == jwt.SigningMethodEdDSA {
+			ed = key
+		} else {
+			rs = key
+		}
+	}
+
+	// HS256 с открытым ключом в качестве секрета: классическая подмена алгоритма
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	hs.Header["kid"] = ed.ID
+	forged, err := hs.SignedString([]byte(ed.Public.(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseToken(forged, &Claims{}); err == nil {
+		t.Error("HS256 token signed with the public key accepted")
+	}
+
+	// Токен подписан RSA-ключом, но kid указывает на EdDSA-ключ
+	rsToken := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+	rsToken.Header["kid"] = ed.ID
+	signed, err := rsToken.SignedString(rs.Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseToken(signed, &Claims{}); err == nil || !strings.Contains(err.Error(), "unexpected signing method") {
+		t.Errorf("RS256 token with an EdDSA kid: err = %v", err)
+	}
+}
+
+func TestUnknownKidReloadsKeyDir(t *testing.T) {
+	dir := testKeyDir(t, "EdDSA")
+	old := signingKeys.Load().active
+
+	// Ключ создан другим экземпляром сервера после загрузки каталога
+	kid, err := GenerateSigningKey(dir, "EdDSA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := readKeyDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, testClaims())
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(next.keys[kid].Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Каталог только что прочитан: повторное чтение откладывается
+	if _, err := parseToken(signed, &Claims{}); err == nil {
+		t.Fatal("unknown kid accepted before the reload interval")
+	}
+
+	stale := *signingKeys.Load()
+	stale.loadedAt = time.Now().Add(-keyReloadInterval)
+	signingKeys.Store(&stale)
+	if _, err := parseToken(signed, &Claims{}); err != nil {
+		t.Fatalf("after reload: %v", err)
+	}
+	ks := signingKeys.Load()
+	if ks.active.ID != kid || ks.keys[old.ID] == nil {
+		t.Errorf("after reload: active %s, want %s; old key kept = %v", ks.active.ID, kid, ks.keys[old.ID] != nil)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	s := newTestServer(t)
+	if _, err := GenerateSigningKey(cfg.Auth.KeysDir, "RS256"); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadSigningKeys(cfg.Auth.KeysDir); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := s.request(http.MethodGet, "/.well-known/jwks.json", "", nil)
+	if cc := rec.Header().Get("Cache-Control"); !strings.Contains(cc, "max-age") {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+	out := s.expect(http.StatusOK, http.MethodGet, "/.well-known/jwks.json", "", nil)
+	keys, _ := out["keys"].([]interface{})
+	sorted := signingKeys.Load().sorted()
+	if len(keys) != len(sorted) {
+		t.Fatalf("%d keys published, want %d", len(keys), len(sorted))
+	}
+	for i, key := range sorted {
+		got := keys[i].(map[string]interface{})
+		if got["kid"] != key.ID || got["alg"] != key.Method.Alg() || got["use"] != "sig" {
+			t.Errorf("key %d = %v, want kid %s alg %s", i, got, key.ID, key.Method.Alg())
+		}
+		if _, ok := got["d"]; ok {
+			t.Errorf("key %s: private part published", key.ID)
+		}
+		switch key.Method {
+		case jwt.SigningMethodEdDSA:
+			x, _ := base64.RawURLEncoding.DecodeString(got["x"].(string))
+			if got["kty"] != "OKP" || got["crv"] != "Ed25519" || !key.Public.(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+				t.Errorf("EdDSA key %v does not match the public key", got)
+			}
+		case jwt.SigningMethodRS256:
+			if got["kty"] != "RSA" || got["n"] == "" || got["e"] != "AQAB" {
+				t.Errorf("RSA key %v", got)
+			}
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Типы связей задач. blocked_by — обратная сторона blocks, duplicated_by — обратная
+// сторона duplicates: в базе связь хранится один раз, от блокирующей (дублирующей) задачи.
+const (
+	TaskLinkBlocks       = "blocks"
+	TaskLinkBlockedBy    = "blocked_by"
+	TaskLinkRelatesTo    = "relates_to"
+	TaskLinkDuplicates   = "duplicates"
+	TaskLinkDuplicatedBy = "duplicated_by"
+)
+
+// Типы, которые можно указать при создании связи
+var taskLinkTypes = []string{TaskLinkBlocks, TaskLinkBlockedBy, TaskLinkRelatesTo, TaskLinkDuplicates}
+
+// Связь между задачами одного проекта
+type TaskLink struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SourceTaskID uint      `gorm:"not null" json:"source_task_id"`
+	TargetTaskID uint      `gorm:"not null" json:"target_task_id"`
+	Type         string    `gorm:"not null" json:"type"`
+	CreatedBy    *uint     `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Связь в ответе API: тип со стороны задачи из запроса и связанная задача
+type taskLinkView struct {
+	ID        uint      `json:"id"`
+	Type      string    `json:"type"`
+	TaskID    uint      `json:"task_id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	Deadline  time.Time `json:"deadline"`
+	CreatedAt time.Time `json:"created_at"`
+	// Связь хранится от задачи из запроса (для обратных сторон blocks и duplicates — false)
+	Outgoing bool `json:"-" gorm:"-"`
+}
+
+// Тело запроса на создание связи
+type taskLinkRequest struct {
+	Type   string `json:"type"`
+	TaskID uint   `json:"task_id"`
+}
+
+// Задача на критическом пути проекта
+type criticalPathStep struct {
+	TaskID   uint      `json:"task_id"`
+	Title    string    `json:"title"`
+	Status   string    `json:"status"`
+	Deadline time.Time `json:"deadline"`
+	// Самый ранний срок завершения: дедлайн задачи, но не раньше завершения блокирующих её задач
+	EarliestFinish time.Time `json:"earliest_finish"`
+	// Блокирующие задачи не успевают к дедлайну задачи
+	Late bool `json:"late"`
+}
+
+// Тип связи со стороны задачи из запроса
+func (v *taskLinkView) relativeType() string {
+	if v.Outgoing {
+		return v.Type
+	}
+	switch v.Type {
+	case TaskLinkBlocks:
+		return TaskLinkBlockedBy
+	case TaskLinkDuplicates:
+		return TaskLinkDuplicatedBy
+	}
+	return v.Type
+}
+
+// Цикл, который появится после добавления связи source -> target типа linkType:
+// задачи от target до source по существующим связям этого типа (nil — цикла нет)
+func findLinkCycle(ctx context.Context, tx *Store, projectID, source, target uint, linkType string) ([]uint, error) {
+	links, err := tx.TaskLinks.ListForProject(ctx, projectID, linkType)
+	if err != nil {
+		return nil, err
+	}
+	next := make(map[uint][]uint)
+	for _, l := range links {
+		next[l.SourceTaskID] = append(next[l.SourceTaskID], l.TargetTaskID)
+	}
+
+	// Поиск в ширину от target; prev восстанавливает путь от source обратно до target
+	prev := make(map[uint]uint)
+	seen := map[uint]bool{target: true}
+	queue := []uint{target}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == source {
+			path := []uint{id}
+			for id != target {
+				id = prev[id]
+				path = append(path, id)
+			}
+			slices.Reverse(path)
+			return path, nil
+		}
+		for _, n := range next[id] {
+			if !seen[n] {
+				seen[n] = true
+				prev[n] = id
+				queue = append(queue, n)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Проверка блокирующих задач перед переводом задачи в статус категории in_progress или done.
+// Пока блокирующие задачи не завершены, смена статуса запрещена; участник с правом
+// task.override_blockers может выполнить её с ?force=true. При отказе отвечает и возвращает false.
+func checkTaskBlockers(c *gin.Context, ctx context.Context, task *Task) bool {
+	force := false
+	if raw := c.Query("force"); raw != "" {
+		var err error
+		force, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid force, expected true or false"})
+			return false
+		}
+	}
+
+	blockers, err := store.TaskLinks.OpenBlockers(ctx, task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return false
+	}
+	if len(blockers) == 0 {
+		return true
+	}
+
+	if force {
+		if !can(c, PermTaskOverrideBlockers) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied", "details": "requires " + PermTaskOverrideBlockers})
+			return false
+		}
+		return true
+	}
+
+	ids := make([]uint, len(blockers))
+	for i, b := range blockers {
+		ids[i] = b.ID
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": "Task is blocked by open tasks", "BlockedBy": ids})
+	return false
+}
+
+// Критический путь по открытым задачам (не в статусах категории done процесса workflow)
+// и связям blocks: цепочка блокирующих задач, которая определяет самый поздний срок завершения.
+// Задача не может завершиться раньше блокирующих её задач, поэтому её срок — max(дедлайн,
+// сроки блокирующих задач).
+func criticalPath(tasks []Task, links []TaskLink, workflow *Workflow) []criticalPathStep {
+	open := make(map[uint]*Task)
+	for i := range tasks {
+		if !workflow.isDone(tasks[i].Status) {
+			open[tasks[i].ID] = &tasks[i]
+		}
+	}
+	blockers := make(map[uint][]uint)
+	pending := make(map[uint]int)
+	dependents := make(map[uint][]uint)
+	for _, l := range links {
+		if open[l.SourceTaskID] == nil || open[l.TargetTaskID] == nil {
+			continue
+		}
+		blockers[l.TargetTaskID] = append(blockers[l.TargetTaskID], l.SourceTaskID)
+		dependents[l.SourceTaskID] = append(dependents[l.SourceTaskID], l.TargetTaskID)
+		pending[l.TargetTaskID]++
+	}
+
+	// Топологический порядок: задача обрабатывается после всех блокирующих её задач
+	var queue []uint
+	for i := range tasks {
+		if id := tasks[i].ID; open[id] != nil && pending[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+	finish := make(map[uint]time.Time)
+	prev := make(map[uint]uint)
+	length := make(map[uint]int)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		finish[id] = open[id].Deadline
+		length[id] = 1
+		for _, b := range blockers[id] {
+			if p, ok := prev[id]; !ok || finish[b].After(finish[p]) || finish[b].Equal(finish[p]) && length[b] > length[p] {
+				prev[id] = b
+			}
+		}
+		if p, ok := prev[id]; ok {
+			length[id] = length[p] + 1
+			if finish[p].After(finish[id]) {
+				finish[id] = finish[p]
+			}
+		}
+
+		for _, d := range dependents[id] {
+			pending[d]--
+			if pending[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	// Конец пути — задача с самым поздним сроком, при равенстве — с самой длинной цепочкой
+	var last uint
+	found := false
+	for i := range tasks {
+		id := tasks[i].ID
+		if _, ok := finish[id]; !ok {
+			continue
+		}
+		if !found || finish[id].After(finish[last]) || finish[id].Equal(finish[last]) && length[id] > length[last] {
+			last, found = id, true
+		}
+	}
+
+	path := []criticalPathStep{}
+	for id, ok := last, found; ok; id, ok = prev[id] {
+		t := open[id]
+		path = append(path, criticalPathStep{
+			TaskID:         t.ID,
+			Title:          t.Title,
+			Status:         t.Status,
+			Deadline:       t.Deadline,
+			EarliestFinish: finish[id],
+			Late:           finish[id].After(t.Deadline),
+		})
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// @Summary Связи задачи
+// @Description Возвращает связи задачи с другими задачами проекта: blocks, blocked_by, relates_to, duplicates, duplicated_by (тип указан со стороны этой задачи)
+// @Tags Задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Связи задачи"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/links [get]
+func getTaskLinks(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	links, err := store.TaskLinks.ListForTask(c.Request.Context(), task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	for i := range links {
+		links[i].Type = links[i].relativeType()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"TaskID": task.ID, "Links": links})
+}
+
+// @Summary Добавление связи задачи
+// @Description Связывает задачу с другой задачей проекта. blocks — задача блокирует task_id, blocked_by — task_id блокирует задачу (её нельзя перевести в статус категории in_progress или done, пока task_id не завершена), relates_to — задачи связаны, duplicates — задача дублирует task_id. Связь, которая замыкает цепочку blocks или duplicates в цикл, отклоняется.
+// @Tags Задачи
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body taskLinkRequest true "Тип связи и связанная задача"
+// @Success 201 {object} map[string]interface{} "Связь добавлена"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 409 {object} map[string]interface{} "Связь уже есть или образует цикл"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/links [post]
+func createTaskLink(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	var req taskLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if !slices.Contains(taskLinkTypes, req.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type, allowed values are: " + strings.Join(taskLinkTypes, ", ")})
+		return
+	}
+	if req.TaskID == task.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A task cannot be linked to itself"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	other, err := store.Tasks.GetInProject(ctx, task.ProjectID, req.TaskID)
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Linked task not found in this project"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Связь хранится от блокирующей (дублирующей) задачи, relates_to — от задачи с меньшим ID
+	link := TaskLink{SourceTaskID: task.ID, TargetTaskID: other.ID, Type: req.Type, CreatedBy: requestActor(c), CreatedAt: time.Now()}
+	switch req.Type {
+	case TaskLinkBlockedBy:
+		link.SourceTaskID, link.TargetTaskID, link.Type = other.ID, task.ID, TaskLinkBlocks
+	case TaskLinkRelatesTo:
+		link.SourceTaskID, link.TargetTaskID = min(task.ID, other.ID), max(task.ID, other.ID)
+	}
+
+	// Проверка цикла и добавление связи в одной транзакции: параллельные запросы
+	// не должны по отдельности пройти проверку и вместе замкнуть цикл
+	var cycle []uint
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if link.Type != TaskLinkRelatesTo {
+			if err := tx.Projects.LockForUpdate(ctx, task.ProjectID); err != nil {
+				return err
+			}
+			var err error
+			if cycle, err = findLinkCycle(ctx, tx, task.ProjectID, link.SourceTaskID, link.TargetTaskID, link.Type); err != nil || cycle != nil {
+				return err
+			}
+		}
+		return tx.TaskLinks.Create(ctx, &link)
+	})
+	if cycle != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Link would create a " + link.Type + " cycle", "Cycle": cycle})
+		return
+	}
+	if errors.Is(err, ErrDuplicate) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Tasks are already linked with this type"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create link: " + err.Error()})
+		return
+	}
+	recordAudit(c, "task_link.create", "task_link", link.ID, task.ProjectID, nil, &link)
+
+	view := taskLinkView{
+		ID:        link.ID,
+		Type:      link.Type,
+		TaskID:    other.ID,
+		Title:     other.Title,
+		Status:    other.Status,
+		Deadline:  other.Deadline,
+		CreatedAt: link.CreatedAt,
+		Outgoing:  link.SourceTaskID == task.ID,
+	}
+	view.Type = view.relativeType()
+	c.JSON(http.StatusCreated, gin.H{"message": "Связь успешно добавлена", "Link": view})
+}
+
+// @Summary Удаление связи задачи
+// @Description Удаляет связь задачи с другой задачей (с любой стороны связи)
+// @Tags Задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param link_id path int true "ID связи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Связь удалена"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Связь не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/links/{link_id} [delete]
+func deleteTaskLink(c *gin.Context) {
+	// Задача и связь проекта :id (найдены в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+	link := c.MustGet("task_link").(*TaskLink)
+	if link.SourceTaskID != task.ID && link.TargetTaskID != task.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+
+	if err := store.TaskLinks.Delete(c.Request.Context(), link.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete link: " + err.Error()})
+		return
+	}
+	recordAudit(c, "task_link.delete", "task_link", link.ID, task.ProjectID, link, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Связь успешно удалена"})
+}
+
+// @Summary Критический путь проекта
+// @Description Возвращает цепочку незавершённых задач, связанных через blocks, которая определяет самый поздний срок завершения проекта. Задача не может завершиться раньше блокирующих её задач, поэтому earliest_finish — её дедлайн или срок блокирующих задач, если он позже (тогда late = true). Finish — срок завершения последней задачи пути (null, если открытых задач нет).
+// @Tags Задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Критический путь и срок завершения"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/critical-path [get]
+func getCriticalPath(c *gin.Context) {
+	// Проект :id (найден в permissionMiddleware)
+	project := c.MustGet("project").(*Project)
+
+	ctx := c.Request.Context()
+	tasks, err := store.Tasks.List(ctx, project.ID, TaskFilter{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	links, err := store.TaskLinks.ListForProject(ctx, project.ID, TaskLinkBlocks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	workflow, err := store.Workflows.Get(ctx, project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	path := criticalPath(tasks, links, workflow)
+	var finish *time.Time
+	if len(path) > 0 {
+		finish = &path[len(path)-1].EarliestFinish
+	}
+
+	c.JSON(http.StatusOK, gin.H{"CriticalPath": path, "Finish": finish})
+}
@@ -0,0 +1,202 @@
+package GoAPIManager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Хранилище связей для проверки поиска цикла без базы
+type fakeTaskLinks struct {
+	TaskLinkRepository
+	links []TaskLink
+}
+
+func (f fakeTaskLinks) ListForProject(ctx context.Context, projectID uint, linkType string) ([]TaskLink, error) {
+	return f.links, nil
+}
+
+// Добавление связи задачи; возвращает ответ с ожидаемым статусом
+func (s *testServer) link(status int, token string, projectID, taskID uint, linkType string, other uint) map[string]interface{} {
+	s.t.Helper()
+	return s.expect(status, http.MethodPost, fmt.Sprintf("/projects/%d/tasks/%d/links", projectID, taskID), token, gin.H{"type": linkType, "task_id": other})
+}
+
+// ID задач из ответа в порядке следования
+func responseIDs(t *testing.T, v interface{}, key string) []uint {
+	t.Helper()
+	list, _ := v.([]interface{})
+	ids := []uint{}
+	for _, item := range list {
+		if key != "" {
+			item = item.(map[string]interface{})[key]
+		}
+		ids = append(ids, uint(item.(float64)))
+	}
+	return ids
+}
+
+func TestFindLinkCycle(t *testing.T) {
+	// ID 0 не должен обрывать восстановление пути
+	tx := &Store{TaskLinks: fakeTaskLinks{links: []TaskLink{
+		{SourceTaskID: 0, TargetTaskID: 1},
+		{SourceTaskID: 1, TargetTaskID: 2},
+		{SourceTaskID: 5, TargetTaskID: 6},
+	}}}
+	for name, tc := range map[string]struct {
+		source, target uint
+		want           []uint
+	}{
+		"direct":      {1, 0, []uint{0, 1}},
+		"indirect":    {2, 0, []uint{0, 1, 2}},
+		"from zero":   {0, 2, nil},
+		"other chain": {6, 1, nil},
+		"closes pair": {6, 5, []uint{5, 6}},
+	} {
+		cycle, err := findLinkCycle(context.Background(), tx, 1, tc.source, tc.target, TaskLinkBlocks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(cycle, tc.want) {
+			t.Errorf("%s: cycle = %v, want %v", name, cycle, tc.want)
+		}
+	}
+}
+
+func TestTaskLinkCycles(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	a := s.task(alice, projectID, "A")
+	b := s.task(alice, projectID, "B")
+	c := s.task(alice, projectID, "C")
+
+	s.link(http.StatusCreated, alice, projectID, a, TaskLinkBlocks, b)
+	s.link(http.StatusConflict, alice, projectID, a, TaskLinkBlocks, b)
+
+	// Прямой цикл: b blocks a при a blocks b
+	out := s.link(http.StatusConflict, alice, projectID, b, TaskLinkBlocks, a)
+	if got := responseIDs(t, out["Cycle"], ""); !slices.Equal(got, []uint{a, b}) {
+		t.Errorf("direct cycle = %v, want %v", got, []uint{a, b})
+	}
+
+	// Косвенный цикл через blocked_by: a blocked_by c при a -> b -> c
+	s.link(http.StatusCreated, alice, projectID, c, TaskLinkBlockedBy, b)
+	out = s.link(http.StatusConflict, alice, projectID, a, TaskLinkBlockedBy, c)
+	if got := responseIDs(t, out["Cycle"], ""); !slices.Equal(got, []uint{a, b, c}) {
+		t.Errorf("indirect cycle = %v, want %v", got, []uint{a, b, c})
+	}
+
+	// Циклы проверяются для каждого типа отдельно
+	s.link(http.StatusCreated, alice, projectID, c, TaskLinkDuplicates, a)
+
+	// relates_to не направлена: связь с другой стороны — та же связь
+	s.link(http.StatusCreated, alice, projectID, c, TaskLinkRelatesTo, a)
+	s.link(http.StatusConflict, alice, projectID, a, TaskLinkRelatesTo, c)
+}
+
+func TestBlockedStatusChange(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.user("alice")
+	member := s.user("bobby")
+	projectID := s.project(owner, "Alpha")
+	s.addMember(owner, projectID, "bobby", ProjectRoleMember)
+	blocker := s.task(owner, projectID, "Blocker")
+	blocked := s.task(owner, projectID, "Blocked")
+	s.link(http.StatusCreated, owner, projectID, blocker, TaskLinkBlocks, blocked)
+
+	path := fmt.Sprintf("/projects/%d/tasks/%d", projectID, blocked)
+	body := func(status string) gin.H {
+		return gin.H{"title": "Blocked", "priority": "High", "status": status, "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339)}
+	}
+
+	out := s.expect(http.StatusConflict, http.MethodPut, path, member, body("In_Progress"))
+	if got := responseIDs(t, out["BlockedBy"], ""); !slices.Equal(got, []uint{blocker}) {
+		t.Errorf("BlockedBy = %v, want %v", got, []uint{blocker})
+	}
+	s.expect(http.StatusConflict, http.MethodPut, path, owner, body("Done"))
+	s.expect(http.StatusBadRequest, http.MethodPut, path+"?force=maybe", owner, body("In_Progress"))
+
+	// Обойти блокировку может только участник с task.override_blockers
+	s.expect(http.StatusForbidden, http.MethodPut, path+"?force=true", member, body("In_Progress"))
+	s.expect(http.StatusOK, http.MethodPut, path+"?force=true", owner, body("In_Progress"))
+
+	// Прочие изменения заблокированной задачи не проверяются
+	s.expect(http.StatusOK, http.MethodPut, path, member, body("In_Progress"))
+
+	// После завершения блокирующей задачи статус меняется без force
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("/projects/%d/tasks/%d", projectID, blocker), owner, gin.H{
+		"title": "Blocker", "priority": "High", "status": "Done", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+	})
+	s.expect(http.StatusOK, http.MethodPut, path, member, body("Done"))
+}
+
+func TestCriticalPath(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	deadline := func(n int) time.Time { return day.AddDate(0, 0, n) }
+
+	// 0 -> 2, 1 -> 2, 2 -> 3; завершённая задача 4 блокирует 0 и не учитывается
+	tasks := []Task{
+		{ID: 0, Title: "A", Status: "In_Line", Deadline: deadline(1)},
+		{ID: 1, Title: "B", Status: "In_Line", Deadline: deadline(5)},
+		{ID: 2, Title: "C", Status: "In_Progress", Deadline: deadline(3)},
+		{ID: 3, Title: "D", Status: "In_Line", Deadline: deadline(4)},
+		{ID: 4, Title: "E", Status: "Done", Deadline: deadline(9)},
+	}
+	links := []TaskLink{
+		{SourceTaskID: 0, TargetTaskID: 2},
+		{SourceTaskID: 1, TargetTaskID: 2},
+		{SourceTaskID: 2, TargetTaskID: 3},
+		{SourceTaskID: 4, TargetTaskID: 0},
+	}
+
+	want := []criticalPathStep{
+		{TaskID: 1, Title: "B", Status: "In_Line", Deadline: deadline(5), EarliestFinish: deadline(5)},
+		{TaskID: 2, Title: "C", Status: "In_Progress", Deadline: deadline(3), EarliestFinish: deadline(5), Late: true},
+		{TaskID: 3, Title: "D", Status: "In_Line", Deadline: deadline(4), EarliestFinish: deadline(5), Late: true},
+	}
+	if got := criticalPath(tasks, links, defaultWorkflow(0)); !slices.Equal(got, want) {
+		t.Errorf("critical path = %+v, want %+v", got, want)
+	}
+
+	// Без связей путь — одна задача с самым поздним дедлайном, в том числе с ID 0
+	if got := criticalPath(tasks[:1], nil, defaultWorkflow(0)); len(got) != 1 || got[0].TaskID != 0 {
+		t.Errorf("single task path = %+v", got)
+	}
+	if got := criticalPath(nil, nil, defaultWorkflow(0)); len(got) != 0 {
+		t.Errorf("empty project path = %+v", got)
+	}
+}
+
+func TestCriticalPathEndpoint(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	path := fmt.Sprintf("/projects/%d/tasks/critical-path", projectID)
+
+	out := s.expect(http.StatusOK, http.MethodGet, path, alice, nil)
+	if len(responseIDs(t, out["CriticalPath"], "task_id")) != 0 || out["Finish"] != nil {
+		t.Errorf("empty project: %v", out)
+	}
+
+	// Дедлайны задач растут в порядке создания: несвязанная задача создаётся первой
+	s.task(alice, projectID, "Unlinked")
+	a := s.task(alice, projectID, "A")
+	b := s.task(alice, projectID, "B")
+	c := s.task(alice, projectID, "C")
+	s.link(http.StatusCreated, alice, projectID, a, TaskLinkBlocks, b)
+	s.link(http.StatusCreated, alice, projectID, c, TaskLinkBlockedBy, b)
+
+	out = s.expect(http.StatusOK, http.MethodGet, path, alice, nil)
+	if got := responseIDs(t, out["CriticalPath"], "task_id"); !slices.Equal(got, []uint{a, b, c}) {
+		t.Errorf("critical path = %v, want %v", got, []uint{a, b, c})
+	}
+	if out["Finish"] == nil {
+		t.Error("Finish is missing")
+	}
+}
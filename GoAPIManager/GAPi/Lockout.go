@@ -0,0 +1,161 @@
+package GoAPIManager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Счётчик неудачных попыток входа. Ключ — user:<имя в нижнем регистре> или ip:<адрес>.
+type LoginFailure struct {
+	Key          string     `gorm:"primaryKey" json:"-"`
+	Failures     int        `gorm:"not null" json:"failures"`
+	LastFailedAt time.Time  `gorm:"not null" json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+}
+
+// Заблокирован ли вход на момент now
+func (f *LoginFailure) locked(now time.Time) bool {
+	return f.LockedUntil != nil && now.Before(*f.LockedUntil)
+}
+
+func userLockoutKey(username string) string {
+	return "user:" + strings.ToLower(username)
+}
+
+func ipLockoutKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Журнал аудита событий безопасности: одно событие — одна строка JSON
+var auditLogger = log.New(os.Stderr, "", 0)
+
+// Запись события безопасности в журнал аудита
+func auditEvent(c *gin.Context, event string, fields gin.H) {
+	entry := gin.H{"time": time.Now().UTC().Format(time.RFC3339), "event": event, "ip": c.ClientIP()}
+	if actor := c.GetString("username"); actor != "" {
+		entry["actor"] = actor
+	}
+	if requestID := c.GetString("request_id"); requestID != "" {
+		entry["request_id"] = requestID
+	}
+	for k, v := range fields {
+		entry[k] = v
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[AUDIT ERROR] %s: %v", event, err)
+		return
+	}
+	auditLogger.Println(string(line))
+}
+
+// Длительность блокировки после failures неудачных попыток: первая блокировка наступает
+// на пороге threshold, каждая следующая неудачная попытка удваивает её (не больше максимума)
+func lockoutDuration(failures, threshold int) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+	d := cfg.Auth.LockoutDuration
+	for i := threshold; i < failures && d < cfg.Auth.LockoutMaxDuration; i++ {
+		d *= 2
+	}
+	return min(d, cfg.Auth.LockoutMaxDuration)
+}
+
+// Проверка блокировки входа для имени пользователя и IP. Если вход заблокирован,
+// отвечает 429 с заголовком Retry-After и возвращает false.
+func checkLoginLockout(c *gin.Context, username string) bool {
+	ctx := c.Request.Context()
+	now := time.Now()
+	var until time.Time
+	for _, key := range []string{userLockoutKey(username), ipLockoutKey(c.ClientIP())} {
+		failure, err := store.LoginFailures.Get(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return false
+		}
+		if failure.locked(now) && failure.LockedUntil.After(until) {
+			until = *failure.LockedUntil
+		}
+	}
+	if until.IsZero() {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(until.Sub(now).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed login attempts, try again later", "RetryAfter": retryAfter})
+	return false
+}
+
+// Учёт неудачной попытки входа по имени пользователя и по IP. При достижении порога
+// вход блокируется, событие записывается в журнал аудита.
+func recordLoginFailure(c *gin.Context, username string) error {
+	ctx := c.Request.Context()
+	now := time.Now()
+	keys := []struct {
+		key       string
+		threshold int
+	}{
+		{userLockoutKey(username), cfg.Auth.LockoutThreshold},
+		{ipLockoutKey(c.ClientIP()), cfg.Auth.IPLockoutThreshold},
+	}
+	for _, k := range keys {
+		failure, err := store.LoginFailures.RecordFailure(ctx, k.key, now, now.Add(-cfg.Auth.LockoutWindow))
+		if err != nil {
+			return err
+		}
+		duration := lockoutDuration(failure.Failures, k.threshold)
+		if duration == 0 {
+			continue
+		}
+		until := now.Add(duration)
+		if err := store.LoginFailures.Lock(ctx, k.key, until); err != nil {
+			return err
+		}
+		auditEvent(c, "login.lockout", gin.H{
+			"key":          k.key,
+			"username":     username,
+			"failures":     failure.Failures,
+			"locked_until": until.UTC().Format(time.RFC3339),
+		})
+	}
+	return nil
+}
+
+// Ответ на неудачную попытку входа: попытка учитывается, клиент получает 401
+func respondLoginFailure(c *gin.Context, username string) {
+	if err := recordLoginFailure(c, username); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+}
+
+// Успешный вход сбрасывает счётчик для имени пользователя. Счётчик IP не сбрасывается:
+// иначе вход в свою учётную запись позволял бы продолжать перебор чужих.
+func resetLoginFailures(ctx context.Context, username string) error {
+	return store.LoginFailures.Reset(ctx, userLockoutKey(username))
+}
+
+// Состояние блокировки входа пользователя (nil — неудачных попыток не было)
+func userLoginFailure(ctx context.Context, username string) (*LoginFailure, error) {
+	failure, err := store.LoginFailures.Get(ctx, userLockoutKey(username))
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return failure, err
+}
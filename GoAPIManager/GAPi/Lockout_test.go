@@ -0,0 +1,84 @@
+package GoAPIManager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoginLockout(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.admin("root")
+	s.register("alice")
+	s.register("bobby")
+	wrong := gin.H{"username": "alice", "password": "wrong"}
+	right := gin.H{"username": "alice", "password": testPassword}
+
+	for i := range cfg.Auth.LockoutThreshold {
+		// Имя пользователя учитывается без регистра
+		if i%2 == 1 {
+			wrong["username"] = "ALICE"
+		}
+		s.expect(http.StatusUnauthorized, http.MethodPost, "/login", "", wrong)
+	}
+
+	// Верный пароль во время блокировки тоже отклоняется
+	w := s.request(http.MethodPost, "/login", "", right)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("locked login: status %d, want %d: %s", w.Code, http.StatusTooManyRequests, w.Body.String())
+	}
+	if retry, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || retry <= 0 {
+		t.Errorf("Retry-After = %q", w.Header().Get("Retry-After"))
+	}
+	s.expect(http.StatusTooManyRequests, http.MethodPost, "/auth/change-password", "", gin.H{
+		"username": "alice", "password": testPassword, "new_password": "secret2",
+	})
+	// Блокировка касается только этого имени
+	s.loginTokens("bobby")
+
+	user, err := store.Users.GetByUsername(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.expect(http.StatusOK, http.MethodPost, fmt.Sprintf("/admin/users/%d/unlock", user.ID), admin, nil)
+	s.expect(http.StatusOK, http.MethodPost, "/login", "", right)
+}
+
+func TestLoginLockoutByIP(t *testing.T) {
+	s := newTestServer(t)
+	s.register("alice")
+	cfg.Auth.IPLockoutThreshold = 3
+
+	// Перебор разных имён с одного адреса блокирует адрес
+	for i := range cfg.Auth.IPLockoutThreshold {
+		s.expect(http.StatusUnauthorized, http.MethodPost, "/login", "", gin.H{"username": fmt.Sprintf("user%d", i), "password": "wrong"})
+	}
+	s.expect(http.StatusTooManyRequests, http.MethodPost, "/login", "", gin.H{"username": "alice", "password": testPassword})
+}
+
+func TestLockoutDuration(t *testing.T) {
+	newTestServer(t)
+	cfg.Auth.LockoutDuration = time.Minute
+	cfg.Auth.LockoutMaxDuration = 5 * time.Minute
+
+	for _, tc := range []struct {
+		failures int
+		want     time.Duration
+	}{
+		{4, 0},
+		{5, time.Minute},
+		{6, 2 * time.Minute},
+		{7, 4 * time.Minute},
+		{8, 5 * time.Minute},
+		{50, 5 * time.Minute},
+	} {
+		if got := lockoutDuration(tc.failures, 5); got != tc.want {
+			t.Errorf("lockoutDuration(%d, 5) = %v, want %v", tc.failures, got, tc.want)
+		}
+	}
+}
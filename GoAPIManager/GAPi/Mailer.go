@@ -0,0 +1,175 @@
+package GoAPIManager
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Письмо пользователю (только текст)
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма. Реализация выбирается настройкой mail.driver:
+// smtp — через SMTP-сервер, file — запись в файл, log — в журнал сервера (для разработки).
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Текущий способ отправки писем
+var mailer Mailer = &logMailer{}
+
+// SetMailer заменяет способ отправки писем (например, в интеграционных тестах)
+func SetMailer(m Mailer) {
+	mailer = m
+}
+
+// NewMailer создаёт Mailer по настройкам mail.*
+func NewMailer(c MailConfig) (Mailer, error) {
+	switch c.Driver {
+	case "smtp":
+		return &SMTPMailer{
+			Addr:     net.JoinHostPort(c.SMTPHost, strconv.Itoa(c.SMTPPort)),
+			Username: c.SMTPUsername,
+			Password: c.SMTPPassword,
+			From:     c.From,
+		}, nil
+	case "file":
+		return &FileMailer{Path: c.FilePath, From: c.From}, nil
+	case "log":
+		return &logMailer{}, nil
+	}
+	return nil, fmt.Errorf("unknown mail driver %q", c.Driver)
+}
+
+// Текст письма в формате RFC 5322
+func formatMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// Отправка через SMTP-сервер. Если сервер поддерживает STARTTLS, соединение шифруется;
+// аутентификация выполняется, только если задан Username.
+type SMTPMailer struct {
+	Addr     string // host:port
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.Addr)
+	if err != nil {
+		return err
+	}
+	// Отправка не должна зависать дольше, чем позволяет контекст
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	host, _, _ := net.SplitHostPort(m.Addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(formatMessage(m.From, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Запись писем в файл (для разработки): письма дописываются в конец файла
+type FileMailer struct {
+	Path string
+	From string
+
+	mu sync.Mutex
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(formatMessage(m.From, msg), "\r\n"...)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Вывод писем в журнал сервера (для разработки)
+type logMailer struct{}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("[MAIL] to %s | %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// Сколько ждать отправки одного письма
+const mailSendTimeout = 30 * time.Second
+
+// Отправка письма в фоне: ответ на запрос не ждёт SMTP-сервер, а время ответа
+// не выдаёт, существует ли адрес. Ошибки отправки пишутся в журнал.
+func sendMail(msg Message) {
+	m := mailer
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+		if err := m.Send(ctx, msg); err != nil {
+			log.Printf("[MAIL ERROR] to %s | %s: %v", msg.To, msg.Subject, err)
+		}
+	}()
+}
@@ -0,0 +1,167 @@
+package GoAPIManager
+
+import (
+	"bufio"
+	"context"
+	"mime"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Письмо, принятое тестовым SMTP-сервером
+type smtpDelivery struct {
+	From string
+	To   []string
+	Data string
+}
+
+// Минимальный SMTP-сервер в процессе теста: без STARTTLS и аутентификации, принимает
+// письма и передаёт их в канал. rejectRcpt — ответ 550 на RCPT TO для этих адресов.
+func smtpTestServer(t *testing.T, rejectRcpt ...string) (string, <-chan smtpDelivery) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	deliveries := make(chan smtpDelivery, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, rejectRcpt, deliveries)
+		}
+	}()
+	return ln.Addr().String(), deliveries
+}
+
+func serveSMTP(conn net.Conn, rejectRcpt []string, deliveries chan<- smtpDelivery) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP test")
+
+	var d smtpDelivery
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			tp.PrintfLine("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			d = smtpDelivery{From: strings.Trim(line[len("MAIL FROM:"):], "<> ")}
+			tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			rcpt := strings.Trim(line[len("RCPT TO:"):], "<> ")
+			rejected := false
+			for _, r := range rejectRcpt {
+				rejected = rejected || r == rcpt
+			}
+			if rejected {
+				tp.PrintfLine("550 No such user")
+				continue
+			}
+			d.To = append(d.To, rcpt)
+			tp.PrintfLine("250 OK")
+		case cmd == "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			d.Data = string(data)
+			deliveries <- d
+			tp.PrintfLine("250 OK")
+		case cmd == "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	addr, deliveries := smtpTestServer(t)
+	m := &SMTPMailer{Addr: addr, From: "noreply@example.com"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := m.Send(ctx, Message{To: "alice@example.com", Subject: "Сброс пароля", Body: "Строка 1\nСтрока 2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var d smtpDelivery
+	select {
+	case d = <-deliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message delivered")
+	}
+	if d.From != "noreply@example.com" || len(d.To) != 1 || d.To[0] != "alice@example.com" {
+		t.Errorf("envelope from %q to %v", d.From, d.To)
+	}
+
+	msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(d.Data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Get("From") != "noreply@example.com" || msg.Get("To") != "alice@example.com" {
+		t.Errorf("headers From %q To %q", msg.Get("From"), msg.Get("To"))
+	}
+	// Тема не в ASCII кодируется по RFC 2047
+	if subject, err := new(mime.WordDecoder).DecodeHeader(msg.Get("Subject")); err != nil || subject != "Сброс пароля" {
+		t.Errorf("subject %q (%v), raw %q", subject, err, msg.Get("Subject"))
+	}
+	if !strings.Contains(msg.Get("Content-Type"), "charset=utf-8") {
+		t.Errorf("Content-Type = %q", msg.Get("Content-Type"))
+	}
+	if _, body, _ := strings.Cut(d.Data, "\n\n"); strings.TrimSpace(body) != "Строка 1\nСтрока 2" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestSMTPMailerReportsRejectedRecipient(t *testing.T) {
+	addr, deliveries := smtpTestServer(t, "nobody@example.com")
+	m := &SMTPMailer{Addr: addr, From: "noreply@example.com"}
+
+	err := m.Send(context.Background(), Message{To: "nobody@example.com", Subject: "Test", Body: "x"})
+	if err == nil || !strings.Contains(err.Error(), "550") {
+		t.Errorf("err = %v, want 550 from the server", err)
+	}
+	select {
+	case d := <-deliveries:
+		t.Errorf("message delivered to %v", d.To)
+	default:
+	}
+}
+
+func TestNewMailer(t *testing.T) {
+	m, err := NewMailer(MailConfig{Driver: "smtp", SMTPHost: "mail.example.com", SMTPPort: 587, From: "noreply@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smtp, ok := m.(*SMTPMailer); !ok || smtp.Addr != "mail.example.com:587" || smtp.From != "noreply@example.com" {
+		t.Errorf("smtp driver: %#v", m)
+	}
+	if m, err := NewMailer(MailConfig{Driver: "file", FilePath: "mail.log"}); err != nil || m.(*FileMailer).Path != "mail.log" {
+		t.Errorf("file driver: %#v, %v", m, err)
+	}
+	if m, err := NewMailer(MailConfig{Driver: "log"}); err != nil {
+		t.Errorf("log driver: %v", err)
+	} else if _, ok := m.(*logMailer); !ok {
+		t.Errorf("log driver: %#v", m)
+	}
+	if _, err := NewMailer(MailConfig{Driver: "pigeon"}); err == nil {
+		t.Error("unknown driver accepted")
+	}
+}
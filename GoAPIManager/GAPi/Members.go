@@ -0,0 +1,343 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Роли участников проекта
+const (
+	ProjectRoleOwner      = "owner"
+	ProjectRoleMaintainer = "maintainer"
+	ProjectRoleMember     = "member"
+	ProjectRoleViewer     = "viewer"
+)
+
+// Старшинство ролей: чем больше число, тем больше прав
+var projectRoleRank = map[string]int{
+	ProjectRoleViewer:     1,
+	ProjectRoleMember:     2,
+	ProjectRoleMaintainer: 3,
+	ProjectRoleOwner:      4,
+}
+
+var errLastOwner = errors.New("project must have at least one owner")
+
+type ProjectMember struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProjectID uint      `gorm:"not null;uniqueIndex:idx_project_members_project_user" json:"project_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_project_members_project_user" json:"user_id"`
+	Role      string    `gorm:"not null" json:"role" validate:"required,oneof=owner maintainer member viewer"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Участник проекта в ответах API
+type projectMemberView struct {
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Тело запроса на добавление участника
+type addMemberRequest struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role" validate:"required,oneof=owner maintainer member viewer"`
+}
+
+// Тело запроса на изменение роли участника
+type updateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner maintainer member viewer"`
+}
+
+// Проверка, что роль не ниже требуемой
+func projectRoleAtLeast(role, required string) bool {
+	return projectRoleRank[role] >= projectRoleRank[required]
+}
+
+// Глобальный администратор сервиса
+func isAdmin(role string) bool {
+	return role == RoleAdmin
+}
+
+// Определение роли пользователя в проекте. Администратор сервиса получает права владельца.
+// Если пользователь не состоит в проекте, возвращается пустая строка.
+func resolveProjectRole(ctx context.Context, projectID, userID uint, globalRole string) (string, error) {
+	if isAdmin(globalRole) {
+		return ProjectRoleOwner, nil
+	}
+
+	member, err := store.Projects.GetMember(ctx, projectID, userID)
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// Может ли участник с ролью actorRole выдать или отозвать роль target.
+// Владелец управляет любыми ролями, остальные — только ролями ниже своей.
+func canManageRole(actorRole, target string) bool {
+	if actorRole == ProjectRoleOwner {
+		return true
+	}
+	return projectRoleRank[actorRole] > projectRoleRank[target]
+}
+
+// @Summary Добавление участника в проект
+// @Description Приглашает пользователя в проект с указанной ролью (owner, maintainer, member, viewer). Пользователь указывается через user_id или username.
+// @Tags Участники
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body addMemberRequest true "Пользователь и роль"
+// @Success 201 {object} map[string]interface{} "Участник добавлен"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 409 {object} map[string]string "Пользователь уже состоит в проекте"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/members [post]
+func addProjectMember(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req addMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role, allowed values are: owner, maintainer, member, viewer"})
+		return
+	}
+
+	if req.UserID == 0 && req.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id or username is required"})
+		return
+	}
+
+	actorRole := c.GetString("project_role")
+	if !canManageRole(actorRole, req.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot grant this role"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 4*time.Second)
+	defer cancel()
+
+	// Ищем приглашаемого пользователя
+	var user *User
+	if req.UserID != 0 {
+		user, err = store.Users.GetByID(ctx, req.UserID)
+	} else {
+		user, err = store.Users.GetByUsername(ctx, req.Username)
+	}
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	member := ProjectMember{ProjectID: uint(projectID), UserID: user.ID, Role: req.Role}
+	err = store.Projects.AddMember(ctx, &member)
+	if errors.Is(err, ErrDuplicate) {
+		c.JSON(http.StatusConflict, gin.H{"error": "User is already a member of this project"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add member: " + err.Error()})
+		return
+	}
+	recordAudit(c, "member.add", "member", member.UserID, member.ProjectID, nil, &member)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Участник успешно добавлен", "Member": projectMemberView{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	}})
+}
+
+// @Summary Список участников проекта
+// @Description Возвращает всех участников проекта и их роли
+// @Tags Участники
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Список участников"
+// @Failure 400 {object} map[string]string "Некорректный ID проекта"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/members [get]
+func getProjectMembers(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	members, err := store.Projects.ListMembers(ctx, uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Members": members})
+}
+
+// @Summary Изменение роли участника
+// @Description Меняет роль участника проекта. Последнего владельца понизить нельзя.
+// @Tags Участники
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param user_id path int true "ID пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body updateMemberRequest true "Новая роль"
+// @Success 200 {object} map[string]interface{} "Роль изменена"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Участник не найден"
+// @Failure 409 {object} map[string]string "Нельзя понизить последнего владельца"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/members/{user_id} [put]
+func updateProjectMember(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req updateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	if err := validate.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role, allowed values are: owner, maintainer, member, viewer"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	member, err := store.Projects.GetMember(ctx, uint(projectID), uint(userID))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	// Нужны права и на текущую роль участника, и на новую
+	actorRole := c.GetString("project_role")
+	if !canManageRole(actorRole, member.Role) || !canManageRole(actorRole, req.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot change this member's role"})
+		return
+	}
+
+	before := *member
+	err = store.Projects.UpdateMemberRole(ctx, member, req.Role)
+	if errors.Is(err, errLastOwner) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Project must have at least one owner"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update member: " + err.Error()})
+		return
+	}
+	recordAudit(c, "member.update", "member", member.UserID, member.ProjectID, &before, member)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Роль участника успешно изменена", "Member": member})
+}
+
+// @Summary Удаление участника из проекта
+// @Description Исключает пользователя из проекта. Участник может покинуть проект сам. Последнего владельца удалить нельзя.
+// @Tags Участники
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param user_id path int true "ID пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Участник удалён"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Участник не найден"
+// @Failure 409 {object} map[string]string "Нельзя удалить последнего владельца"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/members/{user_id} [delete]
+func removeProjectMember(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	member, err := store.Projects.GetMember(ctx, uint(projectID), uint(userID))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	// Покинуть проект может любой участник, исключить другого — только участник с правом
+	// member.remove и старший по роли
+	self := member.UserID == c.GetUint("id")
+	if !self && (!can(c, PermMemberRemove) || !canManageRole(c.GetString("project_role"), member.Role)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot remove this member"})
+		return
+	}
+
+	err = store.Projects.RemoveMember(ctx, member)
+	if errors.Is(err, errLastOwner) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Project must have at least one owner"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member: " + err.Error()})
+		return
+	}
+	recordAudit(c, "member.remove", "member", member.UserID, member.ProjectID, member, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Участник успешно удалён из проекта"})
+}
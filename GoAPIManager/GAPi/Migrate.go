@@ -0,0 +1,356 @@
+package GoAPIManager
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SQL-миграции вшиваются в бинарник: migrations/<диалект>/NNNN_name.up.sql и NNNN_name.down.sql.
+// Наборы для PostgreSQL и SQLite должны содержать одинаковые версии.
+//
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Запись о применённой миграции. Dirty означает, что миграция начала выполняться, но не завершилась.
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	Dirty     bool      `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+var errDirtySchema = errors.New("database schema is dirty")
+
+// Чтение и проверка списка миграций текущей базы данных
+func loadMigrations() ([]migration, error) {
+	dir := path.Join("migrations", dbDialect.Name())
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Создание таблицы учёта миграций
+func ensureMigrationsTable() error {
+	return db.Exec(dbDialect.MigrationsTableSQL()).Error
+}
+
+// Применённые миграции по версиям
+func appliedMigrations() (map[int64]SchemaMigration, error) {
+	if err := ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]SchemaMigration, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// Первая "грязная" миграция, если есть
+func findDirty(applied map[int64]SchemaMigration) *SchemaMigration {
+	for _, r := range applied {
+		if r.Dirty {
+			return &r
+		}
+	}
+	return nil
+}
+
+// Применение всех (или steps) ожидающих миграций
+func migrateUp(steps int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return err
+	}
+	if dirty := findDirty(applied); dirty != nil {
+		return fmt.Errorf("%w: migration %d_%s did not finish, fix it and run `migrate force`", errDirtySchema, dirty.Version, dirty.Name)
+	}
+
+	done := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if steps > 0 && done >= steps {
+			break
+		}
+
+		// Помечаем миграцию как незавершённую до выполнения
+		record := SchemaMigration{Version: m.Version, Name: m.Name, Dirty: true, AppliedAt: time.Now()}
+		if err := db.Create(&record).Error; err != nil {
+			return err
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.Up).Error; err != nil {
+				return err
+			}
+			return tx.Model(&SchemaMigration{}).Where("version = ?", m.Version).Update("dirty", false).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d_%s failed: %w", m.Version, m.Name, err)
+		}
+
+		fmt.Printf("Применена миграция %04d_%s\n", m.Version, m.Name)
+		done++
+	}
+
+	if done == 0 {
+		fmt.Println("Нет новых миграций")
+	}
+	return nil
+}
+
+// Откат последних steps (больше 0) миграций
+func migrateDown(steps int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return err
+	}
+	if dirty := findDirty(applied); dirty != nil {
+		return fmt.Errorf("%w: migration %d_%s did not finish, fix it and run `migrate force`", errDirtySchema, dirty.Version, dirty.Name)
+	}
+
+	done := 0
+	for i := len(migrations) - 1; i >= 0 && done < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		if err := db.Model(&SchemaMigration{}).Where("version = ?", m.Version).Update("dirty", true).Error; err != nil {
+			return err
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.Down).Error; err != nil {
+				return err
+			}
+			return tx.Where("version = ?", m.Version).Delete(&SchemaMigration{}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("rollback of migration %d_%s failed: %w", m.Version, m.Name, err)
+		}
+
+		fmt.Printf("Откачена миграция %04d_%s\n", m.Version, m.Name)
+		done++
+	}
+
+	if done == 0 {
+		fmt.Println("Нет применённых миграций")
+	}
+	return nil
+}
+
+// Ручное выставление версии схемы после исправления "грязной" миграции:
+// миграции до version считаются применёнными, более поздние — нет
+func migrateForce(version int64) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	if err := ensureMigrationsTable(); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("version > ?", version).Delete(&SchemaMigration{}).Error; err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if m.Version > version {
+				break
+			}
+			record := SchemaMigration{Version: m.Version, Name: m.Name, Dirty: false, AppliedAt: time.Now()}
+			if err := tx.Save(&record).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Вывод состояния миграций
+func migrationStatus() error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, m := range migrations {
+		state, appliedAt := "pending", ""
+		if r, ok := applied[m.Version]; ok {
+			state, appliedAt = "applied", r.AppliedAt.Format(time.RFC3339)
+			if r.Dirty {
+				state = "dirty"
+			}
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", m.Version, m.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
+
+// Проверка перед запуском сервера: схема должна быть актуальной и не "грязной"
+func checkSchema() error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return err
+	}
+	if dirty := findDirty(applied); dirty != nil {
+		return fmt.Errorf("%w: migration %d_%s did not finish", errDirtySchema, dirty.Version, dirty.Name)
+	}
+
+	var pending []string
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%04d_%s", m.Version, m.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database schema is out of date, pending migrations: %v (run `migrate up`)", pending)
+	}
+	return nil
+}
+
+// Migrate выполняет подкоманду `migrate up|down|status|force`
+func Migrate(args []string) {
+	if len(args) == 0 {
+		migrateUsage()
+	}
+
+	connectDB()
+
+	var err error
+	switch args[0] {
+	case "up":
+		err = migrateUp(parseSteps(args[1:], 0))
+	case "down":
+		steps := parseSteps(args[1:], 1)
+		if steps == 0 {
+			migrateUsage()
+		}
+		err = migrateDown(steps)
+	case "status":
+		err = migrationStatus()
+	case "force":
+		if len(args) < 2 {
+			migrateUsage()
+		}
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil {
+			migrateUsage()
+		}
+		err = migrateForce(version)
+	default:
+		migrateUsage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка миграции:", err)
+		os.Exit(1)
+	}
+}
+
+// Количество шагов для up/down (по умолчанию def; для up 0 — все)
+func parseSteps(args []string, def int) int {
+	if len(args) == 0 {
+		return def
+	}
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 0 {
+		migrateUsage()
+	}
+	return steps
+}
+
+func migrateUsage() {
+	fmt.Fprintln(os.Stderr, `Использование: migrate <команда>
+  up [N]         применить все (или N) ожидающие миграции
+  down [N]       откатить последнюю (или N последних, N > 0) миграцию
+  status         показать состояние миграций
+  force VERSION  считать схему применённой до версии VERSION (после ручного исправления)`)
+	os.Exit(2)
+}
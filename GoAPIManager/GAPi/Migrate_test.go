@@ -0,0 +1,127 @@
+package GoAPIManager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	defer func(d sqlDialect) { dbDialect = d }(dbDialect)
+
+	names := map[string][]string{}
+	for _, dialect := range []sqlDialect{postgresDialect{}, sqliteDialect{}} {
+		dbDialect = dialect
+		migrations, err := loadMigrations()
+		if err != nil {
+			t.Fatalf("%s: %v", dialect.Name(), err)
+		}
+		if len(migrations) == 0 {
+			t.Fatalf("%s: no migrations embedded", dialect.Name())
+		}
+		// Версии идут подряд без пропусков, у каждой есть непустые up и down
+		for i, m := range migrations {
+			if m.Version != int64(i+1) {
+				t.Errorf("%s: migration %d_%s: version %d, want %d", dialect.Name(), m.Version, m.Name, m.Version, i+1)
+			}
+			if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+				t.Errorf("%s: migration %d_%s has an empty up or down file", dialect.Name(), m.Version, m.Name)
+			}
+			names[dialect.Name()] = append(names[dialect.Name()], m.Name)
+		}
+	}
+	// Схема одна и та же для обеих баз
+	if pg, lite := strings.Join(names["postgres"], ","), strings.Join(names["sqlite"], ","); pg != lite {
+		t.Errorf("postgres migrations %s differ from sqlite %s", pg, lite)
+	}
+}
+
+func TestMigrationFileName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"0001_init.up.sql":            true,
+		"0012_task_links.down.sql":    true,
+		"0001_init.sql":               false,
+		"init.up.sql":                 false,
+		"0001_Init.up.sql":            false,
+		"0001_init.sideways.sql":      false,
+		"0001_init.up.sql.bak":        false,
+		"0001_project-members.up.sql": false,
+	} {
+		if got := migrationFileName.MatchString(name); got != want {
+			t.Errorf("%s: match = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// Все миграции откатываются и применяются заново, схема после этого снова актуальна
+func TestMigrateDownAndUp(t *testing.T) {
+	newTestServer(t)
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrateDown(len(migrations)); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("after down: %d migrations still applied", len(applied))
+	}
+	for _, table := range []string{"users", "projects", "tasks"} {
+		if db.Migrator().HasTable(table) {
+			t.Errorf("after down: table %s still exists", table)
+		}
+	}
+	if err := checkSchema(); err == nil {
+		t.Error("checkSchema accepted an empty database")
+	}
+
+	if err := migrateUp(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSchema(); err != nil {
+		t.Fatal(err)
+	}
+	// Сервер по-прежнему работает на заново созданной схеме
+	s := &testServer{t: t, router: NewRouter(store)}
+	s.project(s.user("alice"), "Alpha")
+}
+
+func TestCheckSchemaRefusesPendingAndDirty(t *testing.T) {
+	newTestServer(t)
+
+	if err := migrateDown(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSchema(); err == nil || !strings.Contains(err.Error(), "pending migrations") {
+		t.Errorf("pending migration: err = %v", err)
+	}
+	if err := migrateUp(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Миграция, прерванная на середине, блокирует запуск и новые миграции до migrate force
+	var last SchemaMigration
+	if err := db.Order("version DESC").First(&last).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&last).Update("dirty", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSchema(); !errors.Is(err, errDirtySchema) {
+		t.Errorf("dirty schema: checkSchema err = %v, want %v", err, errDirtySchema)
+	}
+	if err := migrateUp(0); !errors.Is(err, errDirtySchema) {
+		t.Errorf("dirty schema: migrateUp err = %v, want %v", err, errDirtySchema)
+	}
+	if err := migrateForce(last.Version); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSchema(); err != nil {
+		t.Errorf("after force: %v", err)
+	}
+}
@@ -0,0 +1,583 @@
+package GoAPIManager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Размер страницы уведомлений
+const (
+	defaultNotificationsPageSize = 20
+	maxNotificationsPageSize     = 100
+)
+
+// Ограничения настроек доставки
+const (
+	maxWebhookURLLength    = 2048
+	maxWebhookSecretLength = 255
+)
+
+// Типы уведомлений
+const (
+	NotificationDeadlineReminder = "deadline_reminder"
+	NotificationTaskOverdue      = "task_overdue"
+)
+
+// Темы писем с уведомлениями
+var notificationSubjects = map[string]string{
+	NotificationDeadlineReminder: "Напоминание о дедлайне",
+	NotificationTaskOverdue:      "Задача просрочена",
+}
+
+// Уведомление пользователя. Details зависят от типа: название задачи, дедлайн, за сколько минут
+// до дедлайна отправлено напоминание.
+type Notification struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null" json:"-"`
+	ProjectID *uint     `json:"project_id"`
+	TaskID    *uint     `json:"task_id"`
+	Type      string    `gorm:"not null" json:"type"`
+	Message   string    `gorm:"not null" json:"message"`
+	Details   jsonValue `gorm:"not null;type:text" json:"details"`
+	// Одно и то же напоминание (задача, дедлайн, срок напоминания) отправляется пользователю один раз
+	DedupKey  string     `gorm:"not null" json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// Настройки доставки уведомлений пользователя. Внутри сервиса уведомления доставляются всегда.
+type NotificationSettings struct {
+	UserID uint `gorm:"primaryKey" json:"-"`
+	// Отправлять уведомления на подтверждённый email
+	Email bool `gorm:"not null" json:"email"`
+	// Адрес, на который уведомления отправляются POST-запросом (пусто — не отправлять)
+	WebhookURL string `gorm:"not null" json:"webhook_url"`
+	// Ключ подписи тела запроса (HMAC-SHA256 в заголовке X-Signature-256), в ответах не возвращается
+	WebhookSecret string    `gorm:"not null" json:"-"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (NotificationSettings) TableName() string {
+	return "notification_settings"
+}
+
+// Настройки доставки в ответах API: вместо ключа подписи — задан ли он
+func (s *NotificationSettings) view() gin.H {
+	return gin.H{"email": s.Email, "webhook_url": s.WebhookURL, "webhook_secret_set": s.WebhookSecret != ""}
+}
+
+// Тело запроса на изменение настроек доставки (переданные поля заменяются)
+type notificationSettingsRequest struct {
+	Email         *bool   `json:"email"`
+	WebhookURL    *string `json:"webhook_url"`
+	WebhookSecret *string `json:"webhook_secret"`
+}
+
+// Тело запроса на изменение состояния уведомления
+type notificationReadRequest struct {
+	Read *bool `json:"read"`
+}
+
+// Канал доставки уведомлений. Каналы вызываются по порядку из notificationChannels;
+// канал, выключенный в настройках пользователя, ничего не делает.
+type NotificationChannel interface {
+	Name() string
+	Deliver(ctx context.Context, user *User, settings *NotificationSettings, notification *Notification) error
+}
+
+// Уведомление уже было доставлено пользователю при одном из прошлых запусков
+var errAlreadyNotified = errors.New("notification already delivered")
+
+// Каналы доставки. Внутренний канал идёт первым: его запись защищает от повторной отправки.
+var notificationChannels = []NotificationChannel{inAppChannel{}, emailChannel{}, webhookChannel{}}
+
+// Внутри сервиса: уведомление сохраняется и доступно в GET /user/notifications
+type inAppChannel struct{}
+
+func (inAppChannel) Name() string {
+	return "in_app"
+}
+
+func (inAppChannel) Deliver(ctx context.Context, user *User, settings *NotificationSettings, notification *Notification) error {
+	created, err := store.Notifications.Create(ctx, notification)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errAlreadyNotified
+	}
+	return nil
+}
+
+// Письмо на подтверждённый email
+type emailChannel struct{}
+
+func (emailChannel) Name() string {
+	return "email"
+}
+
+func (emailChannel) Deliver(ctx context.Context, user *User, settings *NotificationSettings, notification *Notification) error {
+	if !settings.Email || user.Email == nil || user.EmailVerifiedAt == nil {
+		return nil
+	}
+	return mailer.Send(ctx, Message{
+		To:      *user.Email,
+		Subject: notificationSubjects[notification.Type],
+		Body:    fmt.Sprintf("Здравствуйте, %s!\n\n%s\n", user.Username, notification.Message),
+	})
+}
+
+// POST-запрос с уведомлением в JSON на адрес пользователя
+type webhookChannel struct{}
+
+func (webhookChannel) Name() string {
+	return "webhook"
+}
+
+func (webhookChannel) Deliver(ctx context.Context, user *User, settings *NotificationSettings, notification *Notification) error {
+	if settings.WebhookURL == "" {
+		return nil
+	}
+	body, err := json.Marshal(gin.H{"event": notification.Type, "user": user.Username, "notification": notification})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Notifications.WebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, settings.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if settings.WebhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(settings.WebhookSecret))
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	if err := checkWebhookURL(settings.WebhookURL); err != nil {
+		return err
+	}
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Доставка уведомления пользователю по всем каналам. Ошибки внешних каналов пишутся в журнал,
+// повторно уведомление не отправляется.
+func notifyUser(ctx context.Context, user *User, notification Notification) error {
+	settings, err := store.Notifications.GetSettings(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	notification.UserID = user.ID
+	for _, channel := range notificationChannels {
+		err := channel.Deliver(ctx, user, settings, &notification)
+		if errors.Is(err, errAlreadyNotified) {
+			return nil
+		}
+		if err != nil {
+			log.Printf("[NOTIFY] %s | %s | %s: %v", user.Username, channel.Name(), notification.Type, err)
+		}
+	}
+	return nil
+}
+
+// Напоминание о задаче на момент now (nil — напоминать не нужно). До дедлайна отправляется
+// ближайшее из напоминаний reminders, в срок которого попадает дедлайн: задача, созданная за
+// 2 часа до дедлайна, получит напоминания за 24 часа и за 1 час, но не два сразу. После дедлайна
+// напоминание повторяется раз в overdue.
+func taskReminder(task *Task, now time.Time, reminders []time.Duration, overdue time.Duration) *Notification {
+	deadline := task.Deadline.UTC().Format("2006-01-02 15:04 MST")
+	notification := Notification{ProjectID: &task.ProjectID, TaskID: &task.ID}
+	details := gin.H{"title": task.Title, "deadline": task.Deadline}
+
+	left := task.Deadline.Sub(now)
+	if left > 0 {
+		i := slices.IndexFunc(reminders, func(d time.Duration) bool { return d >= left })
+		if i < 0 {
+			return nil
+		}
+		before := int64(reminders[i] / time.Minute)
+		notification.Type = NotificationDeadlineReminder
+		notification.Message = fmt.Sprintf("Дедлайн задачи «%s» — %s", task.Title, deadline)
+		notification.DedupKey = fmt.Sprintf("reminder:%d:%d:%d", task.ID, task.Deadline.Unix(), before)
+		details["remind_before_minutes"] = before
+	} else {
+		if overdue <= 0 {
+			return nil
+		}
+		n := int64(-left / overdue)
+		notification.Type = NotificationTaskOverdue
+		notification.Message = fmt.Sprintf("Задача «%s» просрочена: дедлайн был %s", task.Title, deadline)
+		notification.DedupKey = fmt.Sprintf("overdue:%d:%d:%d", task.ID, task.Deadline.Unix(), n)
+	}
+
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil
+	}
+	notification.Details = raw
+	return &notification
+}
+
+// Фоновая задача планировщика: напоминания исполнителям незавершённых задач о приближении
+// дедлайна и о просрочке (настройки notifications.*)
+func processReminders(ctx context.Context, now time.Time) {
+	reminders := slices.Sorted(slices.Values(cfg.Notifications.Reminders))
+	overdue := cfg.Notifications.OverdueInterval
+	if len(reminders) == 0 && overdue <= 0 {
+		return
+	}
+
+	// Задачи, о которых может понадобиться напомнить
+	var from time.Time
+	if overdue <= 0 {
+		from = now
+	}
+	to := now
+	if len(reminders) > 0 {
+		to = now.Add(reminders[len(reminders)-1])
+	}
+	tasks, err := store.Tasks.ListOpenDue(ctx, from, to)
+	if err == nil {
+		err = store.Tasks.LoadAssignees(ctx, tasks)
+	}
+	if err != nil {
+		log.Printf("[NOTIFY] Ошибка чтения задач для напоминаний: %v", err)
+		return
+	}
+
+	users := make(map[uint]*User)
+	for i := range tasks {
+		notification := taskReminder(&tasks[i], now, reminders, overdue)
+		if notification == nil {
+			continue
+		}
+		for _, userID := range tasks[i].AssigneeIDs {
+			user, ok := users[userID]
+			if !ok {
+				user, err = store.Users.GetByID(ctx, userID)
+				if err != nil && !errors.Is(err, ErrNotFound) {
+					log.Printf("[NOTIFY] Ошибка чтения пользователя %d: %v", userID, err)
+				}
+				users[userID] = user
+			}
+			// Заблокированным пользователям напоминания не отправляются
+			if user == nil || user.DisabledAt != nil {
+				continue
+			}
+			if err := notifyUser(ctx, user, *notification); err != nil {
+				log.Printf("[NOTIFY] %s | %s: %v", user.Username, notification.Type, err)
+			}
+		}
+	}
+}
+
+// @Summary Уведомления текущего пользователя
+// @Description Возвращает уведомления (новые первыми) и количество непрочитанных. С unread=true — только непрочитанные. Следующая страница запрашивается с cursor из NextCursor предыдущего ответа; пустой NextCursor — уведомлений больше нет.
+// @Tags Уведомления
+// @Produce json
+// @Param unread query bool false "Только непрочитанные"
+// @Param cursor query string false "Курсор следующей страницы (NextCursor)"
+// @Param limit query int false "Размер страницы (по умолчанию 20, не больше 100)"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Уведомления, количество непрочитанных и курсор следующей страницы"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/notifications [get]
+func getUserNotifications(c *gin.Context) {
+	before, limit, ok := cursorPage(c, defaultNotificationsPageSize, maxNotificationsPageSize)
+	if !ok {
+		return
+	}
+
+	filter := NotificationFilter{Before: before, Limit: limit + 1}
+	if raw := c.Query("unread"); raw != "" {
+		unread, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unread, expected true or false"})
+			return
+		}
+		filter.Unread = unread
+	}
+
+	ctx := c.Request.Context()
+	userID := c.GetUint("id")
+	notifications, err := store.Notifications.List(ctx, userID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	unread, err := store.Notifications.CountUnread(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	nextCursor := ""
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		nextCursor = formatCursor(notifications[limit-1].ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Notifications": notifications, "Unread": unread, "NextCursor": nextCursor})
+}
+
+// @Summary Отметка уведомления
+// @Description Отмечает уведомление текущего пользователя прочитанным (read=true) или непрочитанным (read=false)
+// @Tags Уведомления
+// @Accept json
+// @Produce json
+// @Param id path int true "ID уведомления"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body notificationReadRequest true "Состояние уведомления"
+// @Success 200 {object} map[string]interface{} "Уведомление отмечено"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 404 {object} map[string]string "Уведомление не найдено"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/notifications/{id} [put]
+func updateNotification(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	var req notificationReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if req.Read == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "read is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	notification, err := store.Notifications.GetForUser(ctx, c.GetUint("id"), uint(id))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Повторная отметка прочитанным сохраняет время первого прочтения
+	switch {
+	case *req.Read && notification.ReadAt == nil:
+		now := time.Now()
+		notification.ReadAt = &now
+	case !*req.Read:
+		notification.ReadAt = nil
+	}
+	if err := store.Notifications.SetRead(ctx, notification.ID, notification.ReadAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Уведомление обновлено", "Notification": notification})
+}
+
+// @Summary Отметка всех уведомлений прочитанными
+// @Description Отмечает прочитанными все уведомления текущего пользователя
+// @Tags Уведомления
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Количество отмеченных уведомлений"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/notifications/read-all [post]
+func readAllNotifications(c *gin.Context) {
+	count, err := store.Notifications.MarkAllRead(c.Request.Context(), c.GetUint("id"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Все уведомления прочитаны", "Updated": count})
+}
+
+// @Summary Настройки доставки уведомлений
+// @Description Возвращает, куда кроме сервиса доставляются уведомления текущего пользователя: на email и/или на вебхук
+// @Tags Уведомления
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Настройки доставки"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/notifications/settings [get]
+func getNotificationSettings(c *gin.Context) {
+	settings, err := store.Notifications.GetSettings(c.Request.Context(), c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Settings": settings.view()})
+}
+
+// @Summary Изменение настроек доставки уведомлений
+// @Description Изменяет переданные настройки доставки. email=true — письма на подтверждённый email. webhook_url — адрес http(s), на который уведомления отправляются POST-запросом в JSON (пустая строка — не отправлять); с webhook_secret тело запроса подписывается HMAC-SHA256 в заголовке X-Signature-256 (sha256=<hex>).
+// @Tags Уведомления
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Param input body notificationSettingsRequest true "Настройки доставки"
+// @Success 200 {object} map[string]interface{} "Настройки сохранены"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/notifications/settings [put]
+func updateNotificationSettings(c *gin.Context) {
+	var req notificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	settings, err := store.Notifications.GetSettings(ctx, c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	if req.Email != nil {
+		settings.Email = *req.Email
+	}
+	if req.WebhookURL != nil {
+		if *req.WebhookURL != "" {
+			if err := checkWebhookURL(*req.WebhookURL); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook_url", "details": err.Error()})
+				return
+			}
+		}
+		settings.WebhookURL = *req.WebhookURL
+	}
+	if req.WebhookSecret != nil {
+		if len(*req.WebhookSecret) > maxWebhookSecretLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("webhook_secret must be at most %d characters", maxWebhookSecretLength)})
+			return
+		}
+		settings.WebhookSecret = *req.WebhookSecret
+	}
+	settings.UpdatedAt = time.Now()
+
+	if err := store.Notifications.SaveSettings(ctx, settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save settings: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Настройки уведомлений сохранены", "Settings": settings.view()})
+}
+
+var (
+	errWebhookURLInvalid     = fmt.Errorf("expected an http(s) URL up to %d characters", maxWebhookURLLength)
+	errWebhookHostNotAllowed = errors.New("webhook host is not in notifications.webhook_allowed_hosts")
+	errWebhookAddressBlocked = errors.New("webhook address is private, loopback or link-local")
+)
+
+// Проверка адреса вебхука: абсолютный http(s) URL с хостом из notifications.webhook_allowed_hosts
+// (если список задан) и не IP-адрес внутренней сети. Имя хоста проверяется при подключении.
+func checkWebhookURL(raw string) error {
+	if len(raw) > maxWebhookURLLength {
+		return errWebhookURLInvalid
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errWebhookURLInvalid
+	}
+	host := u.Hostname()
+	if allowed := cfg.Notifications.WebhookAllowedHosts; len(allowed) > 0 &&
+		!slices.ContainsFunc(allowed, func(h string) bool { return strings.EqualFold(h, host) }) {
+		return errWebhookHostNotAllowed
+	}
+	if ip := net.ParseIP(host); ip != nil && !webhookIPAllowed(ip) {
+		return errWebhookAddressBlocked
+	}
+	return nil
+}
+
+// Диапазоны, которые не покрываются методами net.IP: «эта сеть», CGNAT (там же метаданные
+// некоторых облаков), служебные и зарезервированные адреса
+var webhookBlockedNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{"0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "240.0.0.0/4"} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// Можно ли отправлять вебхук на адрес: только публичные адреса, иначе любой пользователь
+// мог бы через планировщик обращаться к сервисам внутренней сети и к метаданным облака
+// (169.254.169.254 — link-local)
+var webhookIPAllowed = func(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	return !slices.ContainsFunc(webhookBlockedNets, func(n *net.IPNet) bool { return n.Contains(ip) })
+}
+
+// Проверка адреса при каждом подключении, уже после разрешения имени: так не помогают
+// ни DNS-имена, указывающие на внутренние адреса, ни подмена ответа DNS между проверками
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !webhookIPAllowed(ip) {
+		return fmt.Errorf("%w: %s", errWebhookAddressBlocked, host)
+	}
+	return nil
+}
+
+// Клиент для вебхуков: без прокси из окружения (иначе проверялся бы адрес прокси)
+// и без перенаправлений, которые могли бы увести запрос на внутренний адрес.
+// Время ожидания задаёт notifications.webhook_timeout через контекст запроса.
+var webhookClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: 10 * time.Second, Control: webhookDialControl}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
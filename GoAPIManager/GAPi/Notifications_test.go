@@ -0,0 +1,107 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWebhookIPAllowed(t *testing.T) {
+	for ip, want := range map[string]bool{
+		"8.8.8.8":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"::ffff:127.0.0.1": false,
+		"0.0.0.0":          false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"fd00:ec2::254":    false,
+		"100.100.100.200":  false,
+		"224.0.0.1":        false,
+	} {
+		if got := webhookIPAllowed(net.ParseIP(ip)); got != want {
+			t.Errorf("webhookIPAllowed(%s) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestWebhookSettingsValidation(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	settings := func(status int, url string) {
+		t.Helper()
+		s.expect(status, http.MethodPut, "/user/notifications/settings", alice, gin.H{"webhook_url": url})
+	}
+
+	settings(http.StatusBadRequest, "ftp://example.com/hook")
+	settings(http.StatusBadRequest, "http://169.254.169.254/latest/meta-data/")
+	settings(http.StatusBadRequest, "http://127.0.0.1:8080/admin")
+	settings(http.StatusBadRequest, "http://[::1]/hook")
+	settings(http.StatusOK, "https://example.com/hook")
+
+	cfg.Notifications.WebhookAllowedHosts = []string{"hooks.example.com"}
+	settings(http.StatusBadRequest, "https://example.com/hook")
+	settings(http.StatusOK, "https://HOOKS.example.com/hook")
+}
+
+// Доставка уведомления на вебхук url
+func deliverWebhook(url string) error {
+	return webhookChannel{}.Deliver(context.Background(), &User{Username: "alice"},
+		&NotificationSettings{WebhookURL: url}, &Notification{Type: NotificationTaskOverdue})
+}
+
+// Сервер, считающий полученные запросы
+func countingServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWebhookBlocksInternalAddresses(t *testing.T) {
+	newTestServer(t)
+	var hits atomic.Int32
+	srv := countingServer(t, &hits)
+
+	// Имя хоста проверяется после разрешения, при подключении
+	for _, url := range []string{srv.URL, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)} {
+		if err := deliverWebhook(url); !errors.Is(err, errWebhookAddressBlocked) {
+			t.Errorf("deliver to %s: err = %v, want %v", url, err, errWebhookAddressBlocked)
+		}
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("internal server got %d requests", n)
+	}
+}
+
+func TestWebhookRedirectNotFollowed(t *testing.T) {
+	newTestServer(t)
+	// Тестовые серверы слушают loopback: считаем его публичным адресом
+	allowed := webhookIPAllowed
+	webhookIPAllowed = func(net.IP) bool { return true }
+	t.Cleanup(func() { webhookIPAllowed = allowed })
+
+	var hits atomic.Int32
+	internal := countingServer(t, &hits)
+	redirect := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusFound))
+	t.Cleanup(redirect.Close)
+
+	if err := deliverWebhook(redirect.URL); err == nil {
+		t.Error("redirect response accepted as a successful delivery")
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("redirect followed: internal server got %d requests", n)
+	}
+}
@@ -0,0 +1,720 @@
+package GoAPIManager
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Учётная запись внешнего провайдера, привязанная к пользователю. Пользователь находится
+// по паре issuer + subject (sub из ID-токена), email провайдера только запоминается.
+type UserIdentity struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	Issuer      string    `gorm:"not null" json:"issuer"`
+	Subject     string    `gorm:"not null" json:"subject"`
+	Email       *string   `json:"email"`
+	CreatedAt   time.Time `gorm:"not null" json:"created_at"`
+	LastLoginAt time.Time `gorm:"not null" json:"last_login_at"`
+}
+
+// Начатый вход через провайдера: state из ссылки на провайдера, nonce для ID-токена
+// и code_verifier для PKCE. В базе хранится только SHA-256 от state.
+type OIDCLogin struct {
+	ID           uint      `gorm:"primaryKey"`
+	StateHash    string    `gorm:"not null;unique"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	DeviceName   string    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	UsedAt       *time.Time
+}
+
+// Без явного имени GORM назвал бы таблицу o_id_c_logins
+func (OIDCLogin) TableName() string {
+	return "oidc_logins"
+}
+
+// Метаданные провайдера (/.well-known/openid-configuration)
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims ID-токена, которые использует сервис (группы читаются отдельно, см. oidc.groups_claim)
+type oidcClaims struct {
+	Issuer            string           `json:"iss"`
+	Subject           string           `json:"sub"`
+	Audience          jwt.ClaimStrings `json:"aud"`
+	AuthorizedParty   string           `json:"azp"`
+	Nonce             string           `json:"nonce"`
+	Email             string           `json:"email"`
+	EmailVerified     interface{}      `json:"email_verified"` // bool, у некоторых провайдеров строка "true"
+	PreferredUsername string           `json:"preferred_username"`
+	Name              string           `json:"name"`
+	Groups            []string         `json:"-"`
+}
+
+const (
+	// Сколько действует ссылка на провайдера: за это время пользователь должен войти
+	oidcLoginTTL = 10 * time.Minute
+	// Как часто перечитываются метаданные провайдера
+	oidcMetadataTTL = time.Hour
+	// Ключи провайдера перечитываются при незнакомом kid, но не чаще этого интервала
+	oidcKeysMinRefresh = time.Minute
+)
+
+// Алгоритмы подписи ID-токенов, которые принимает сервис
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+var (
+	errOIDCLoginInvalid   = errors.New("invalid or expired login state")
+	errOIDCNotProvisioned = errors.New("user is not provisioned")
+	errOIDCGroupDenied    = errors.New("not a member of an allowed group")
+)
+
+var oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// Кеш метаданных и ключей провайдера
+type oidcProvider struct {
+	mu            sync.Mutex
+	issuer        string
+	metadata      *oidcMetadata
+	metadataAt    time.Time
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+var oidcClient = &oidcProvider{}
+
+// Вход через SSO включён, если задан провайдер
+func oidcEnabled() bool {
+	return cfg.OIDC.Issuer != ""
+}
+
+// GET-запрос к провайдеру с разбором JSON-ответа
+func fetchJSON(ctx context.Context, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// Метаданные провайдера. При смене oidc.issuer кеш сбрасывается.
+func (p *oidcProvider) getMetadata(ctx context.Context) (*oidcMetadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	issuer := cfg.OIDC.Issuer
+	if p.issuer != issuer {
+		p.issuer, p.metadata, p.keys, p.keysFetchedAt = issuer, nil, nil, time.Time{}
+	}
+	if p.metadata != nil && time.Since(p.metadataAt) < oidcMetadataTTL {
+		return p.metadata, nil
+	}
+
+	var metadata oidcMetadata
+	if err := fetchJSON(ctx, strings.TrimRight(issuer, "/")+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, err
+	}
+	// Провайдер обязан вернуть тот же issuer, что указан в настройках (OpenID Connect Discovery, п. 4.3)
+	if metadata.Issuer != issuer {
+		return nil, fmt.Errorf("issuer mismatch: configured %q, provider reports %q", issuer, metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("provider metadata is incomplete")
+	}
+	p.metadata = &metadata
+	p.metadataAt = time.Now()
+	return p.metadata, nil
+}
+
+// Открытый ключ провайдера по kid. Незнакомый kid означает, что провайдер сменил ключи:
+// набор перечитывается (не чаще раза в oidcKeysMinRefresh).
+func (p *oidcProvider) getKey(ctx context.Context, metadata *oidcMetadata, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < oidcKeysMinRefresh {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := fetchJSON(ctx, metadata.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	p.keys = make(map[string]crypto.PublicKey)
+	p.keysFetchedAt = time.Now()
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Ключи неподдерживаемых типов пропускаются
+		if key, err := k.publicKey(); err == nil {
+			p.keys[k.Kid] = key
+		}
+	}
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// Поиск ключа в кеше; токен без kid подходит, только если у провайдера один ключ
+func (p *oidcProvider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// Открытый ключ из JWK (RSA, EC P-256/P-384/P-521, Ed25519)
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	decode := func(s string) (*big.Int, []byte, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil || len(b) == 0 {
+			return nil, nil, fmt.Errorf("key %q: invalid parameter", k.Kid)
+		}
+		return new(big.Int).SetBytes(b), b, nil
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, _, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, _, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("key %q: invalid exponent", k.Kid)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("key %q: unsupported curve %q", k.Kid, k.Crv)
+		}
+		x, _, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, _, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("key %q: point is not on curve", k.Kid)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		_, x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key %q: unsupported curve %q", k.Kid, k.Crv)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("key %q: unsupported key type %q", k.Kid, k.Kty)
+}
+
+// Обмен кода авторизации на ID-токен. Секрет клиента передаётся через HTTP Basic
+// (client_secret_basic); публичный клиент без секрета защищён только PKCE.
+func (p *oidcProvider) exchangeCode(ctx context.Context, metadata *oidcMetadata, code, codeVerifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {cfg.OIDC.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	if cfg.OIDC.ClientSecret == "" {
+		form.Set("client_id", cfg.OIDC.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if cfg.OIDC.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(cfg.OIDC.ClientID), url.QueryEscape(cfg.OIDC.ClientSecret))
+	}
+
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("token endpoint: %s", resp.Status)
+	}
+	if body.Error != "" {
+		return "", fmt.Errorf("token endpoint: %s: %s", body.Error, body.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || body.IDToken == "" {
+		return "", fmt.Errorf("token endpoint: %s, no id_token", resp.Status)
+	}
+	return body.IDToken, nil
+}
+
+// Проверка ID-токена: подпись ключом провайдера, срок действия, issuer, audience и nonce
+func (p *oidcProvider) verifyIDToken(ctx context.Context, metadata *oidcMetadata, raw, nonce string) (*oidcClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(oidcSigningMethods))
+	mapClaims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(raw, mapClaims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, metadata, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// exp в ID-токене обязателен, а MapClaims проверяет его, только если он есть
+	if _, ok := mapClaims["exp"]; !ok {
+		return nil, errors.New("token has no exp")
+	}
+
+	data, err := json.Marshal(mapClaims)
+	if err != nil {
+		return nil, err
+	}
+	var claims oidcClaims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, err
+	}
+	claims.Groups = claimStrings(mapClaims[cfg.OIDC.GroupsClaim])
+
+	switch {
+	case claims.Issuer != metadata.Issuer:
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	case !slices.Contains(claims.Audience, cfg.OIDC.ClientID):
+		return nil, errors.New("token is issued for another client")
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != cfg.OIDC.ClientID:
+		return nil, errors.New("token is issued for another client")
+	case claims.Nonce != nonce:
+		return nil, errors.New("nonce mismatch")
+	case claims.Subject == "":
+		return nil, errors.New("token has no sub")
+	}
+	return &claims, nil
+}
+
+// Значение claim как список строк (массив или одна строка)
+func claimStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Подтвердил ли провайдер email
+func (c *oidcClaims) emailVerified() bool {
+	switch v := c.EmailVerified.(type) {
+	case bool:
+		return v
+	case string:
+		verified, _ := strconv.ParseBool(v)
+		return verified
+	}
+	return false
+}
+
+// Состоит ли пользователь хотя бы в одной из групп
+func inAnyGroup(groups, wanted []string) bool {
+	for _, g := range groups {
+		if slices.Contains(wanted, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// Случайная строка для state, nonce и code_verifier
+func randomOIDCToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// code_challenge для PKCE (метод S256, RFC 7636)
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Использование state из ответа провайдера: незнакомый, истёкший или уже
+// использованный state не принимается
+func consumeOIDCLogin(ctx context.Context, state string) (*OIDCLogin, error) {
+	if state == "" {
+		return nil, errOIDCLoginInvalid
+	}
+	login, err := store.OIDCLogins.GetByStateHash(ctx, hashToken(state))
+	if errors.Is(err, ErrNotFound) {
+		return nil, errOIDCLoginInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if login.UsedAt != nil || !now.Before(login.ExpiresAt) {
+		return nil, errOIDCLoginInvalid
+	}
+	ok, err := store.OIDCLogins.MarkUsed(ctx, login.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errOIDCLoginInvalid
+	}
+	return login, nil
+}
+
+var usernameDisallowed = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// Свободное имя для нового пользователя из preferred_username, email или name.
+// Если имя занято, к нему добавляется номер.
+func oidcUsername(ctx context.Context, users UserRepository, claims *oidcClaims) (string, error) {
+	base := ""
+	for _, candidate := range []string{claims.PreferredUsername, strings.Split(claims.Email, "@")[0], claims.Name} {
+		if base = usernameDisallowed.ReplaceAllString(candidate, ""); len(base) >= 3 {
+			break
+		}
+	}
+	if len(base) < 3 {
+		base = "user"
+	}
+	base = base[:min(len(base), 16)]
+
+	for i := 1; i < 1000; i++ {
+		username := base
+		if i > 1 {
+			username = base + strconv.Itoa(i)
+		}
+		_, err := users.GetByUsername(ctx, username)
+		if errors.Is(err, ErrNotFound) {
+			return username, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("no free username")
+}
+
+// Пользователь для учётной записи провайдера: уже привязанный; найденный по email,
+// подтверждённому и провайдером, и в сервисе (oidc.link_by_email); или новый (oidc.auto_provision).
+// Новый пользователь не получает пароля: войти он может только через SSO.
+func oidcUser(c *gin.Context, claims *oidcClaims) (*User, error) {
+	ctx := c.Request.Context()
+	now := time.Now()
+
+	var email *string
+	if normalized, err := normalizeEmail(claims.Email); err == nil {
+		email = &normalized
+	}
+
+	var user *User
+	var event string
+	err := store.Transaction(ctx, func(tx *Store) error {
+		identity, err := tx.Identities.Get(ctx, claims.Issuer, claims.Subject)
+		if err == nil {
+			if user, err = tx.Users.GetByID(ctx, identity.UserID); err != nil {
+				return err
+			}
+			return tx.Identities.RecordLogin(ctx, identity.ID, email, now)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		if cfg.OIDC.LinkByEmail && email != nil && claims.emailVerified() {
+			found, err := tx.Users.GetByEmail(ctx, *email)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+			// Адрес при регистрации не проверяется: без подтверждения любой мог бы
+			// заранее занять чужой email и получить вход через чужую учётную запись провайдера
+			if found != nil && found.EmailVerifiedAt != nil {
+				user = found
+				event = "oidc.link"
+			}
+		}
+		if user == nil {
+			if !cfg.OIDC.AutoProvision {
+				return errOIDCNotProvisioned
+			}
+			username, err := oidcUsername(ctx, tx.Users, claims)
+			if err != nil {
+				return err
+			}
+			user = &User{Username: username, Role: RoleUser}
+			// Адрес, уже занятый другим пользователем, новому пользователю не достаётся
+			if email != nil {
+				if _, err := tx.Users.GetByEmail(ctx, *email); errors.Is(err, ErrNotFound) {
+					user.Email = email
+					if claims.emailVerified() {
+						user.EmailVerifiedAt = &now
+					}
+				} else if err != nil {
+					return err
+				}
+			}
+			if err := tx.Users.Create(ctx, user); err != nil {
+				return err
+			}
+			event = "oidc.provision"
+		}
+
+		return tx.Identities.Create(ctx, &UserIdentity{
+			UserID:      user.ID,
+			Issuer:      claims.Issuer,
+			Subject:     claims.Subject,
+			Email:       email,
+			LastLoginAt: now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if event != "" {
+		auditEvent(c, event, gin.H{"username": user.Username, "issuer": claims.Issuer, "subject": claims.Subject})
+	}
+	return user, nil
+}
+
+// Роль по группам провайдера (oidc.admin_groups). Если группы не настроены, роль
+// назначается в сервисе и не меняется. Последнего администратора роль не понижает.
+func syncOIDCRole(c *gin.Context, user *User, groups []string) error {
+	if len(cfg.OIDC.AdminGroups) == 0 {
+		return nil
+	}
+	role := RoleUser
+	if inAnyGroup(groups, cfg.OIDC.AdminGroups) {
+		role = RoleAdmin
+	}
+	if role == user.Role {
+		return nil
+	}
+
+	previous := user.Role
+	user.Role = role
+	err := updateUserAndRevokeSessions(c.Request.Context(), user)
+	if errors.Is(err, errLastAdmin) {
+		user.Role = previous
+		auditEvent(c, "oidc.role_kept", gin.H{"username": user.Username, "role": previous, "reason": "last admin"})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	auditEvent(c, "oidc.role_change", gin.H{"username": user.Username, "from": previous, "to": role})
+	return nil
+}
+
+// @Summary Вход через SSO
+// @Description Перенаправляет на страницу входа провайдера OpenID Connect (authorization code + PKCE). После входа провайдер возвращает пользователя на /auth/oidc/callback. Ссылка действует 10 минут.
+// @Tags Аутентификация
+// @Produce json
+// @Param device_name query string false "Название устройства для списка сессий"
+// @Success 302 "Перенаправление на провайдера"
+// @Failure 404 {object} map[string]string "Вход через SSO не настроен"
+// @Failure 502 {object} map[string]string "Провайдер недоступен"
+// @Router /auth/oidc/login [get]
+func oidcLogin(c *gin.Context) {
+	if !oidcEnabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SSO login is not configured"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	metadata, err := oidcClient.getMetadata(ctx)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Identity provider unavailable", "details": err.Error()})
+		return
+	}
+
+	var state, nonce, verifier string
+	for _, v := range []*string{&state, &nonce, &verifier} {
+		if *v, err = randomOIDCToken(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start login"})
+			return
+		}
+	}
+	err = store.OIDCLogins.Create(ctx, &OIDCLogin{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		DeviceName:   c.Query("device_name"),
+		ExpiresAt:    time.Now().Add(oidcLoginTTL),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {cfg.OIDC.ClientID},
+		"redirect_uri":          {cfg.OIDC.RedirectURL},
+		"scope":                 {strings.Join(cfg.OIDC.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {pkceChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	c.Redirect(http.StatusFound, metadata.AuthorizationEndpoint+separator+query.Encode())
+}
+
+// @Summary Завершение входа через SSO
+// @Description Сюда провайдер OpenID Connect возвращает пользователя после входа. Код авторизации обменивается на ID-токен, пользователь находится по учётной записи провайдера, по подтверждённому email или создаётся. Роль назначается по группам провайдера (oidc.admin_groups). Ответ такой же, как у /login.
+// @Tags Аутентификация
+// @Produce json
+// @Param code query string true "Код авторизации"
+// @Param state query string true "State из ссылки на провайдера"
+// @Success 200 {object} map[string]interface{} "Успешная аутентификация. Возвращает access- и refresh-токен"
+// @Failure 400 {object} map[string]string "Неизвестный, истёкший или использованный state"
+// @Failure 401 {object} map[string]string "Провайдер отклонил вход или ID-токен недействителен"
+// @Failure 403 {object} map[string]string "Пользователь не создан, не состоит в разрешённой группе или заблокирован"
+// @Failure 404 {object} map[string]string "Вход через SSO не настроен"
+// @Failure 502 {object} map[string]string "Провайдер недоступен"
+// @Router /auth/oidc/callback [get]
+func oidcCallback(c *gin.Context) {
+	if !oidcEnabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SSO login is not configured"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	login, err := consumeOIDCLogin(ctx, c.Query("state"))
+	if errors.Is(err, errOIDCLoginInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login state"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Пользователь отменил вход или провайдер отказал
+	if providerError := c.Query("error"); providerError != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Identity provider denied login", "details": strings.TrimSpace(providerError + " " + c.Query("error_description"))})
+		return
+	}
+
+	metadata, err := oidcClient.getMetadata(ctx)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Identity provider unavailable", "details": err.Error()})
+		return
+	}
+	idToken, err := oidcClient.exchangeCode(ctx, metadata, c.Query("code"), login.CodeVerifier)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization code exchange failed", "details": err.Error()})
+		return
+	}
+	claims, err := oidcClient.verifyIDToken(ctx, metadata, idToken, login.Nonce)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid ID token", "details": err.Error()})
+		return
+	}
+
+	if len(cfg.OIDC.AllowedGroups) > 0 && !inAnyGroup(claims.Groups, cfg.OIDC.AllowedGroups) {
+		auditEvent(c, "oidc.denied", gin.H{"issuer": claims.Issuer, "subject": claims.Subject, "reason": errOIDCGroupDenied.Error()})
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not a member of an allowed group"})
+		return
+	}
+
+	user, err := oidcUser(c, claims)
+	if errors.Is(err, errOIDCNotProvisioned) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "User is not provisioned"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		return
+	}
+	if err := syncOIDCRole(c, user, claims.Groups); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	finishLogin(c, user, login.DeviceName)
+}
@@ -0,0 +1,102 @@
+package GoAPIManager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Вход через провайдера с учётной записью subject и подтверждённым провайдером email
+func oidcTestLogin(t *testing.T, subject, email string) *User {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/oidc/callback", nil)
+	user, err := oidcUser(c, &oidcClaims{Issuer: "https://idp.example", Subject: subject, Email: email, EmailVerified: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// Пользователь с email; verified — подтверждён ли адрес в сервисе
+func (s *testServer) userWithEmail(username, email string, verified bool) *User {
+	s.t.Helper()
+	s.register(username)
+	user, err := store.Users.GetByUsername(context.Background(), username)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	user.Email = &email
+	if verified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := store.Users.Update(context.Background(), user); err != nil {
+		s.t.Fatal(err)
+	}
+	return user
+}
+
+func TestOIDCLinkByEmailDisabledByDefault(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.userWithEmail("alice", "alice@corp.example", true)
+
+	if user := oidcTestLogin(t, "sub-1", "alice@corp.example"); user.ID == alice.ID {
+		t.Fatal("identity linked to an existing user with link_by_email off")
+	}
+}
+
+func TestOIDCLinkByEmailRequiresVerifiedAddress(t *testing.T) {
+	s := newTestServer(t)
+	cfg.OIDC.LinkByEmail = true
+	// Злоумышленник заранее зарегистрировал чужой адрес, не подтвердив его
+	mallory := s.userWithEmail("mallory", "victim@corp.example", false)
+	alice := s.userWithEmail("alice", "alice@corp.example", true)
+
+	victim := oidcTestLogin(t, "sub-victim", "victim@corp.example")
+	if victim.ID == mallory.ID {
+		t.Fatal("identity linked to a user with an unverified email")
+	}
+	if victim.Email != nil {
+		t.Errorf("new user got the taken email %q", *victim.Email)
+	}
+
+	if user := oidcTestLogin(t, "sub-alice", "alice@corp.example"); user.ID != alice.ID {
+		t.Errorf("verified email: linked to user %d, want %d", user.ID, alice.ID)
+	}
+	// Привязанная учётная запись находится по iss и sub, а не по email
+	if user := oidcTestLogin(t, "sub-victim", ""); user.ID != victim.ID {
+		t.Errorf("second login: user %d, want %d", user.ID, victim.ID)
+	}
+}
+
+func TestOIDCGroupsDoNotDemoteLastAdmin(t *testing.T) {
+	newTestServer(t)
+	cfg.OIDC.AdminGroups = []string{"ops"}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/oidc/callback", nil)
+
+	admin := oidcTestLogin(t, "sub-admin", "")
+	if err := syncOIDCRole(c, admin, []string{"ops"}); err != nil || admin.Role != RoleAdmin {
+		t.Fatalf("role %s (%v), want %s", admin.Role, err, RoleAdmin)
+	}
+	// Группу у единственного администратора отозвали у провайдера — роль сохраняется
+	if err := syncOIDCRole(c, admin, nil); err != nil || admin.Role != RoleAdmin {
+		t.Fatalf("last admin: role %s (%v), want %s", admin.Role, err, RoleAdmin)
+	}
+
+	other := oidcTestLogin(t, "sub-other", "")
+	if err := syncOIDCRole(c, other, []string{"ops"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := syncOIDCRole(c, admin, nil); err != nil || admin.Role != RoleUser {
+		t.Fatalf("second admin exists: role %s (%v), want %s", admin.Role, err, RoleUser)
+	}
+	if stored, err := store.Users.GetByID(context.Background(), admin.ID); err != nil || stored.Role != RoleUser {
+		t.Errorf("stored role %v (%v), want %s", stored, err, RoleUser)
+	}
+}
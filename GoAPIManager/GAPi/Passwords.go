@@ -0,0 +1,254 @@
+package GoAPIManager
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Имя пользователя: от 3 до 20 символов, только буквы и цифры
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9]{3,20}$`)
+
+// Минимальная длина пароля
+const minPasswordLength = 6
+
+// Тело запроса на смену пароля
+type changePasswordRequest struct {
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password"`
+}
+
+// Тело запроса на сброс забытого пароля
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// Тело запроса на установку нового пароля по токену из письма
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func validateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return errors.New("Invalid username format")
+	}
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return errors.New("Password must be at least 6 characters long")
+	}
+	return nil
+}
+
+// Хеш пароля для хранения в базе
+func hashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Временный пароль, который администратор передаёт пользователю после сброса
+func newTemporaryPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// @Summary Смена пароля
+// @Description Меняет пароль по имени пользователя и текущему (или временному, выданному администратором) паролю. Все сессии пользователя завершаются, после смены нужно войти заново.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body changePasswordRequest true "Имя пользователя, текущий и новый пароль"
+// @Success 200 {object} map[string]string "Пароль изменён"
+// @Failure 400 {object} map[string]string "Некорректные входные данные"
+// @Failure 401 {object} map[string]string "Неверное имя пользователя или пароль"
+// @Failure 403 {object} map[string]string "Учётная запись заблокирована"
+// @Failure 429 {object} map[string]interface{} "Слишком много неудачных попыток, вход временно заблокирован (см. заголовок Retry-After)"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /auth/change-password [post]
+func changePassword(c *gin.Context) {
+	var req changePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	// Проверка пароля здесь — такая же точка перебора, как /login
+	if !checkLoginLockout(c, req.Username) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := store.Users.GetByUsername(ctx, req.Username)
+	if err != nil {
+		respondLoginFailure(c, req.Username)
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		respondLoginFailure(c, req.Username)
+		return
+	}
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		return
+	}
+
+	if err := validatePassword(req.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.NewPassword == req.Password {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "New password must differ from the current one"})
+		return
+	}
+
+	hashed, err := hashPassword(req.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	user.Password = hashed
+	user.MustChangePassword = false
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Users.Update(ctx, user); err != nil {
+			return err
+		}
+		_, err := tx.Sessions.RevokeAll(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пароль успешно изменён, войдите с новым паролем"})
+}
+
+// @Summary Запрос на сброс пароля
+// @Description Отправляет на email письмо со ссылкой для сброса пароля. Ответ одинаковый независимо от того, зарегистрирован ли адрес. Ссылка одноразовая, срок действия — auth.password_reset_ttl, новая ссылка отменяет предыдущие.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body forgotPasswordRequest true "Email пользователя"
+// @Success 200 {object} map[string]string "Письмо отправлено, если адрес зарегистрирован"
+// @Failure 400 {object} map[string]string "Некорректный email"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /password/forgot [post]
+func forgotPassword(c *gin.Context) {
+	var req forgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := store.Users.GetByEmail(ctx, email)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		log.Printf("[PASSWORD RESET] no user with email %s", email)
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	case user.DisabledAt != nil:
+		log.Printf("[PASSWORD RESET] user %s is disabled", user.Username)
+	case user.Password == "":
+		// Пользователь создан при входе через SSO: пароль хранится у провайдера
+		log.Printf("[PASSWORD RESET] user %s signs in via SSO", user.Username)
+	default:
+		token, err := issueEmailToken(ctx, store.EmailTokens, user, emailTokenPasswordReset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		sendMail(passwordResetMessage(user, token))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Если адрес зарегистрирован, на него отправлено письмо со ссылкой для сброса пароля"})
+}
+
+// @Summary Сброс пароля по токену из письма
+// @Description Задаёт новый пароль по одноразовому токену из письма (POST /password/forgot). Адрес, на который пришло письмо, считается подтверждённым. Все сессии пользователя завершаются.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body resetPasswordRequest true "Токен из письма и новый пароль"
+// @Success 200 {object} map[string]string "Пароль изменён"
+// @Failure 400 {object} map[string]string "Некорректный пароль или токен недействителен, использован или истёк"
+// @Failure 403 {object} map[string]string "Учётная запись заблокирована"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /password/reset [post]
+func resetPassword(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if err := validatePassword(req.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hashed, err := hashPassword(req.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = store.Transaction(ctx, func(tx *Store) error {
+		token, err := consumeEmailToken(ctx, tx.EmailTokens, req.Token, emailTokenPasswordReset)
+		if err != nil {
+			return err
+		}
+		user, err := tx.Users.GetByID(ctx, token.UserID)
+		if err != nil {
+			return err
+		}
+		if user.DisabledAt != nil {
+			return errAccountDisabled
+		}
+		// Письмо отправлено на прежний адрес, а email с тех пор сменили
+		if user.Email == nil || *user.Email != token.Email {
+			return errEmailTokenInvalid
+		}
+
+		user.Password = hashed
+		user.MustChangePassword = false
+		if user.EmailVerifiedAt == nil {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+		}
+		if err := tx.Users.Update(ctx, user); err != nil {
+			return err
+		}
+		_, err = tx.Sessions.RevokeAll(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		respondEmailTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пароль успешно изменён, войдите с новым паролем"})
+}
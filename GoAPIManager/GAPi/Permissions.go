@@ -0,0 +1,353 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Права в проекте
+const (
+	PermProjectView   = "project.view"
+	PermProjectUpdate = "project.update"
+	PermProjectDelete = "project.delete"
+	PermFileDownload  = "file.download"
+	PermFileUpload    = "file.upload"
+	PermTaskView      = "task.view"
+	PermTaskCreate    = "task.create"
+	PermTaskUpdate    = "task.update"
+	PermTaskDelete    = "task.delete"
+	PermTaskAssign    = "task.assign"
+	PermMemberView    = "member.view"
+	PermMemberLeave   = "member.leave"
+	PermMemberAdd     = "member.add"
+	PermMemberUpdate  = "member.update"
+	PermMemberRemove  = "member.remove"
+	PermAuditView     = "audit.view"
+	PermCommentCreate = "comment.create"
+	// Удаление чужих комментариев
+	PermCommentModerate = "comment.moderate"
+	// Смена статуса задачи, несмотря на незавершённые блокирующие задачи
+	PermTaskOverrideBlockers = "task.override_blockers"
+	// Учёт своего времени (записи и таймеры) и удаление чужих записей
+	PermTimeLog       = "time.log"
+	PermTimeManage    = "time.manage"
+	PermTimesheetView = "timesheet.view"
+)
+
+// Права вне проектов
+const (
+	PermProjectCreate = "project.create"
+	PermAdminAccess   = "admin.access"
+)
+
+// Права, которые добавляет каждая роль проекта к правам предыдущей (по старшинству)
+var projectRoleGrants = []struct {
+	Role        string
+	Permissions []string
+}{
+	{ProjectRoleViewer, []string{PermProjectView, PermFileDownload, PermTaskView, PermMemberView, PermMemberLeave}},
+	{ProjectRoleMember, []string{PermFileUpload, PermTaskCreate, PermTaskUpdate, PermTaskDelete, PermTaskAssign, PermCommentCreate, PermTimeLog}},
+	{ProjectRoleMaintainer, []string{PermProjectUpdate, PermMemberAdd, PermMemberUpdate, PermMemberRemove, PermAuditView, PermCommentModerate, PermTaskOverrideBlockers,
+		PermTimeManage, PermTimesheetView}},
+	{ProjectRoleOwner, []string{PermProjectDelete}},
+}
+
+// Все права каждой роли проекта
+var projectRolePermissions = func() map[string][]string {
+	perms := make(map[string][]string)
+	var acc []string
+	for _, grant := range projectRoleGrants {
+		acc = append(acc, grant.Permissions...)
+		perms[grant.Role] = slices.Clone(acc)
+	}
+	return perms
+}()
+
+// Права ролей сервиса
+var globalRolePermissions = map[string][]string{
+	RoleUser:  {PermProjectCreate},
+	RoleAdmin: {PermProjectCreate, PermAdminAccess},
+}
+
+// Право, необходимое для маршрута проекта (ключ — шаблон пути Gin и метод). Для маршрутов
+// из этой карты проект :id загружается до обработчика и доступен через c.Get("project").
+var routePermissions = map[string]map[string]string{
+	"/projects/:id": {
+		http.MethodGet:    PermProjectView,
+		http.MethodPut:    PermProjectUpdate,
+		http.MethodDelete: PermProjectDelete,
+	},
+	"/projects/:id/upload": {
+		http.MethodPost: PermFileUpload,
+	},
+	"/projects/:id/download": {
+		http.MethodGet: PermFileDownload,
+	},
+	"/projects/:id/workflow": {
+		http.MethodGet: PermProjectView,
+		http.MethodPut: PermProjectUpdate,
+	},
+	"/projects/:id/custom-fields": {
+		http.MethodGet:  PermProjectView,
+		http.MethodPost: PermProjectUpdate,
+	},
+	"/projects/:id/custom-fields/:field_id": {
+		http.MethodPut:    PermProjectUpdate,
+		http.MethodDelete: PermProjectUpdate,
+	},
+	"/projects/:id/tasks": {
+		http.MethodPost: PermTaskCreate,
+		http.MethodGet:  PermTaskView,
+	},
+	"/projects/:id/tasks/:task_id": {
+		http.MethodPut:    PermTaskUpdate,
+		http.MethodDelete: PermTaskDelete,
+	},
+	"/projects/:id/tasks/:task_id/assign": {
+		http.MethodPost: PermTaskAssign,
+	},
+	"/projects/:id/tasks/:task_id/history": {
+		http.MethodGet: PermTaskView,
+	},
+	"/projects/:id/tasks/:task_id/comments": {
+		http.MethodGet:  PermTaskView,
+		http.MethodPost: PermCommentCreate,
+	},
+	// Изменить можно только свой комментарий, удалить чужой — только с comment.moderate
+	"/projects/:id/tasks/:task_id/comments/:comment_id": {
+		http.MethodPut:    PermCommentCreate,
+		http.MethodDelete: PermCommentCreate,
+	},
+	"/projects/:id/tasks/:task_id/checklist": {
+		http.MethodGet:  PermTaskView,
+		http.MethodPost: PermTaskUpdate,
+	},
+	"/projects/:id/tasks/:task_id/checklist/:item_id": {
+		http.MethodPut:    PermTaskUpdate,
+		http.MethodDelete: PermTaskUpdate,
+	},
+	"/projects/:id/tasks/:task_id/links": {
+		http.MethodGet:  PermTaskView,
+		http.MethodPost: PermTaskUpdate,
+	},
+	"/projects/:id/tasks/:task_id/links/:link_id": {
+		http.MethodDelete: PermTaskUpdate,
+	},
+	"/projects/:id/tasks/critical-path": {
+		http.MethodGet: PermTaskView,
+	},
+	"/projects/:id/tasks/:task_id/worklogs": {
+		http.MethodGet:  PermTaskView,
+		http.MethodPost: PermTimeLog,
+	},
+	// Удалить можно только свою запись, чужую — только с time.manage
+	"/projects/:id/tasks/:task_id/worklogs/:worklog_id": {
+		http.MethodDelete: PermTimeLog,
+	},
+	"/projects/:id/tasks/:task_id/timer/start": {
+		http.MethodPost: PermTimeLog,
+	},
+	"/projects/:id/tasks/:task_id/timer/stop": {
+		http.MethodPost: PermTimeLog,
+	},
+	"/projects/:id/timesheet": {
+		http.MethodGet: PermTimesheetView,
+	},
+	"/projects/:id/tasks/:task_id/recurrence": {
+		http.MethodPost: PermTaskUpdate,
+	},
+	"/projects/:id/recurrences": {
+		http.MethodGet: PermTaskView,
+	},
+	"/projects/:id/recurrences/:recurrence_id": {
+		http.MethodGet:    PermTaskView,
+		http.MethodPut:    PermTaskUpdate,
+		http.MethodDelete: PermTaskUpdate,
+	},
+	"/projects/:id/activity": {
+		http.MethodGet: PermProjectView,
+	},
+	"/projects/:id/members": {
+		http.MethodGet:  PermMemberView,
+		http.MethodPost: PermMemberAdd,
+	},
+	"/projects/:id/audit": {
+		http.MethodGet: PermAuditView,
+	},
+	"/projects/:id/members/:user_id": {
+		http.MethodPut: PermMemberUpdate,
+		// Покинуть проект может любой участник, исключить другого — только с member.remove
+		http.MethodDelete: PermMemberLeave,
+	},
+}
+
+// Дочерние ресурсы проекта в параметрах пути. Ресурс ищется только среди ресурсов
+// проекта :id, поэтому ресурс другого проекта неотличим от несуществующего.
+var projectResources = []struct {
+	Param     string // параметр пути
+	Key       string // ключ в контексте запроса
+	InvalidID string
+	NotFound  string
+	Load      func(ctx context.Context, projectID, id uint) (interface{}, error)
+}{
+	{"task_id", "task", "Invalid task ID", "Task not found", func(ctx context.Context, projectID, id uint) (interface{}, error) {
+		return store.Tasks.GetInProject(ctx, projectID, id)
+	}},
+	{"comment_id", "comment", "Invalid comment ID", "Comment not found", func(ctx context.Context, projectID, id uint) (interface{}, error) {
+		return store.Comments.GetInProject(ctx, projectID, id)
+	}},
+	{"item_id", "checklist_item", "Invalid checklist item ID", "Checklist item not found", func(ctx context.Context, projectID, id uint) (interface{}, error) {
+		return store.Checklists.GetInProject(ctx, projectID, id)
+	}},
+	{"link_id", "task_link", "Invalid link ID", "Link not found", func(ctx context.Context, projectID, id uint) (interface{}, error) {
+		return store.TaskLinks.GetInProject(ctx, projectID, id)
+	}},
+	{"worklog_id", "worklog", "Invalid worklog ID", "Worklog not found", func(ctx context.Context, projectID, id uint) (interface{}, error) {
+		return store.Worklogs.GetInProject(ctx, projectID, id)
+	}},
+	{"recurrence_id", "recurrence", "Invalid recurrence ID", "Recurrence not found", func(ctx context.Context, projectID, id uint) (interface{}, error) {
+		return store.Recurrences.GetInProject(ctx, projectID, id)
+	}},
+	{"field_id", "custom_field", "Invalid field ID", "Custom field not found", func(ctx context.Context, projectID, id uint) (interface{}, error) {
+		return store.CustomFields.GetInProject(ctx, projectID, id)
+	}},
+}
+
+// Есть ли у роли проекта право
+func projectRoleCan(role, permission string) bool {
+	return slices.Contains(projectRolePermissions[role], permission)
+}
+
+// Есть ли у роли сервиса право
+func globalRoleCan(role, permission string) bool {
+	return slices.Contains(globalRolePermissions[role], permission)
+}
+
+// Есть ли у участника текущего проекта право (роль определяет permissionMiddleware)
+func can(c *gin.Context, permission string) bool {
+	return projectRoleCan(c.GetString("project_role"), permission)
+}
+
+// Проверка прав на маршрут проекта: проект существует, у пользователя есть нужное право,
+// а дочерние ресурсы из пути принадлежат этому проекту. Найденные проект, роль и ресурсы
+// сохраняются в контексте запроса.
+func permissionMiddleware(c *gin.Context) {
+	required := routePermissions[c.FullPath()][c.Request.Method]
+	if required == "" {
+		c.Next()
+		return
+	}
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		c.Abort()
+		return
+	}
+
+	ctx := c.Request.Context()
+	project, err := store.Projects.GetByID(ctx, uint(projectID))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		c.Abort()
+		return
+	}
+
+	// Роль пользователя определяется участием в проекте
+	role, err := resolveProjectRole(ctx, project.ID, c.GetUint("id"), c.GetString("role"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		c.Abort()
+		return
+	}
+	if !projectRoleCan(role, required) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied", "details": "requires " + required})
+		c.Abort()
+		return
+	}
+
+	for _, res := range projectResources {
+		raw := c.Param(res.Param)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": res.InvalidID})
+			c.Abort()
+			return
+		}
+		value, err := res.Load(ctx, project.ID, uint(id))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": res.NotFound})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			c.Abort()
+			return
+		}
+		c.Set(res.Key, value)
+	}
+
+	c.Set("project", project)
+	c.Set("project_role", role)
+	c.Next()
+}
+
+// @Summary Права пользователя
+// @Description Возвращает права текущего пользователя: с project_id — права в проекте (project.view, task.update, member.add и т.д., пустой список, если пользователь не участник), без него — права в сервисе (project.create, admin.access). Нужен интерфейсу, чтобы скрывать недоступные действия.
+// @Tags Пользователи
+// @Produce json
+// @Param project_id query int false "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Роль и права"
+// @Failure 400 {object} map[string]string "Некорректный ID проекта"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/permissions [get]
+func getUserPermissions(c *gin.Context) {
+	rawProjectID := c.Query("project_id")
+	if rawProjectID == "" {
+		role := c.GetString("role")
+		c.JSON(http.StatusOK, gin.H{"Role": role, "Permissions": append([]string{}, globalRolePermissions[role]...)})
+		return
+	}
+
+	projectID, err := strconv.Atoi(rawProjectID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	project, err := store.Projects.GetByID(ctx, uint(projectID))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	role, err := resolveProjectRole(ctx, project.ID, c.GetUint("id"), c.GetString("role"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ProjectID": project.ID, "ProjectRole": role, "Permissions": append([]string{}, projectRolePermissions[role]...)})
+}
@@ -0,0 +1,122 @@
+package GoAPIManager
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Добавление пользователя в проект с ролью role
+func (s *testServer) addMember(token string, projectID uint, username, role string) {
+	s.t.Helper()
+	s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/members", projectID), token, gin.H{"username": username, "role": role})
+}
+
+func TestProjectRolePermissions(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.user("alice")
+	projectID := s.project(owner, "Alpha")
+	taskID := s.task(owner, projectID, "First")
+	project := fmt.Sprintf("/projects/%d", projectID)
+	task := fmt.Sprintf("%s/tasks/%d", project, taskID)
+
+	tokens := map[string]string{ProjectRoleOwner: owner}
+	for _, role := range []string{ProjectRoleViewer, ProjectRoleMember, ProjectRoleMaintainer} {
+		tokens[role] = s.user(role)
+		s.addMember(owner, projectID, role, role)
+	}
+	outsider := s.user("outsider")
+
+	newTask := gin.H{"title": "New", "priority": "Low", "deadline": time.Now().AddDate(0, 0, 1).UTC().Format(time.RFC3339)}
+	checks := []struct {
+		method, path string
+		body         gin.H
+		allowed      string // младшая роль, которой действие доступно
+	}{
+		{http.MethodGet, project, nil, ProjectRoleViewer},
+		{http.MethodGet, project + "/tasks", nil, ProjectRoleViewer},
+		{http.MethodGet, project + "/members", nil, ProjectRoleViewer},
+		{http.MethodPost, project + "/tasks", newTask, ProjectRoleMember},
+		{http.MethodPut, task, gin.H{"title": "Edited", "priority": "Low"}, ProjectRoleMember},
+		{http.MethodPost, project + "/members", gin.H{"username": "nobody", "role": ProjectRoleViewer}, ProjectRoleMaintainer},
+		{http.MethodGet, project + "/audit", nil, ProjectRoleMaintainer},
+		{http.MethodPut, project, gin.H{"name": "Beta", "description": "renamed"}, ProjectRoleMaintainer},
+		{http.MethodDelete, project, nil, ProjectRoleOwner},
+	}
+	roles := []string{ProjectRoleViewer, ProjectRoleMember, ProjectRoleMaintainer, ProjectRoleOwner}
+	for _, check := range checks {
+		if w := s.request(check.method, check.path, outsider, check.body); w.Code != http.StatusForbidden {
+			t.Errorf("%s %s as outsider: status %d, want %d", check.method, check.path, w.Code, http.StatusForbidden)
+		}
+		allowedFrom := slices.Index(roles, check.allowed)
+		for i, role := range roles {
+			w := s.request(check.method, check.path, tokens[role], check.body)
+			allowed := w.Code != http.StatusForbidden
+			if allowed != (i >= allowedFrom) {
+				t.Errorf("%s %s as %s: status %d", check.method, check.path, role, w.Code)
+			}
+		}
+	}
+}
+
+func TestProjectChildResourceOwnership(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	alpha := s.project(alice, "Alpha")
+	beta := s.project(alice, "Beta")
+	betaTask := s.task(alice, beta, "Beta task")
+
+	// Задача другого проекта через путь этого проекта неотличима от несуществующей
+	path := fmt.Sprintf("/projects/%d/tasks/%d", alpha, betaTask)
+	s.expect(http.StatusNotFound, http.MethodPut, path, alice, gin.H{"title": "Moved", "priority": "Low"})
+	s.expect(http.StatusNotFound, http.MethodDelete, path, alice, nil)
+	s.expect(http.StatusNotFound, http.MethodGet, path+"/history", alice, nil)
+	s.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/projects/%d/tasks/%d/history", beta, betaTask), alice, nil)
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("/projects/%d/tasks/%d", beta, betaTask), alice, gin.H{"title": "Edited", "priority": "Low", "status": "In_Line"})
+}
+
+func TestProjectCreatePermission(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	s.project(alice, "Alpha")
+
+	// Роль сервиса без project.create не может создавать проекты
+	defer func(perms []string) { globalRolePermissions[RoleUser] = perms }(globalRolePermissions[RoleUser])
+	globalRolePermissions[RoleUser] = nil
+	out := s.expect(http.StatusForbidden, http.MethodPost, "/projects", alice, gin.H{"name": "Beta", "description": "denied"})
+	if out["details"] != "requires "+PermProjectCreate {
+		t.Errorf("details = %v", out["details"])
+	}
+}
+
+func TestUserPermissions(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	bob := s.user("bobby")
+	outsider := s.user("outsider")
+	projectID := s.project(alice, "Alpha")
+	s.addMember(alice, projectID, "bobby", ProjectRoleViewer)
+	path := fmt.Sprintf("/user/permissions?project_id=%d", projectID)
+
+	permissions := func(token, path string) []interface{} {
+		t.Helper()
+		list, _ := s.expect(http.StatusOK, http.MethodGet, path, token, nil)["Permissions"].([]interface{})
+		return list
+	}
+
+	viewer := permissions(bob, path)
+	if !slices.Contains(viewer, interface{}(PermTaskView)) || slices.Contains(viewer, interface{}(PermTaskUpdate)) {
+		t.Errorf("viewer permissions = %v", viewer)
+	}
+	if list := permissions(outsider, path); len(list) != 0 {
+		t.Errorf("outsider permissions = %v, want none", list)
+	}
+	if list := permissions(bob, "/user/permissions"); !slices.Equal(list, []interface{}{PermProjectCreate}) {
+		t.Errorf("global permissions = %v, want [%s]", list, PermProjectCreate)
+	}
+	s.expect(http.StatusNotFound, http.MethodGet, "/user/permissions?project_id=999", bob, nil)
+}
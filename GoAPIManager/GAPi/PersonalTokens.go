@@ -0,0 +1,412 @@
+package GoAPIManager
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Персональный токен доступа для скриптов и CI. Действует вместо логина и пароля,
+// но только на маршрутах, разрешённых его областями (scopes). Сам токен показывается
+// один раз при создании, в базе хранится только его SHA-256.
+type PersonalToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	Name       string     `gorm:"not null" json:"name"`
+	TokenHash  string     `gorm:"not null;unique" json:"-"`
+	Prefix     string     `gorm:"not null" json:"prefix"` // начало токена, чтобы узнать его в списке
+	Scopes     []string   `gorm:"serializer:json;not null" json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // nil — бессрочный
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"-"`
+}
+
+// Токен в журнале аудита: только название и области действия, без самого токена и его начала
+func (t *PersonalToken) auditView() gin.H {
+	return gin.H{"name": t.Name, "scopes": t.Scopes}
+}
+
+// Тело запроса на создание токена
+type personalTokenRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+	// Срок действия (RFC 3339), без него токен бессрочный
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Области действия персональных токенов. Область :write включает соответствующую :read.
+const (
+	ScopeProjectsRead  = "projects:read"
+	ScopeProjectsWrite = "projects:write"
+	ScopeTasksRead     = "tasks:read"
+	ScopeTasksWrite    = "tasks:write"
+	ScopeFilesRead     = "files:read"
+	ScopeFilesWrite    = "files:write"
+)
+
+var personalTokenScopes = []string{
+	ScopeProjectsRead, ScopeProjectsWrite,
+	ScopeTasksRead, ScopeTasksWrite,
+	ScopeFilesRead, ScopeFilesWrite,
+}
+
+// Области, необходимые на маршрутах. Маршруты, которых здесь нет (сессии, сами токены),
+// доступны только по JWT, полученному при входе.
+var routeScopes = map[string]map[string]string{
+	"/projects": {
+		http.MethodPost: ScopeProjectsWrite,
+	},
+	"/user/projects": {
+		http.MethodGet: ScopeProjectsRead,
+	},
+	"/projects/:id": {
+		http.MethodGet:    ScopeProjectsRead,
+		http.MethodPut:    ScopeProjectsWrite,
+		http.MethodDelete: ScopeProjectsWrite,
+	},
+	"/projects/:id/upload": {
+		http.MethodPost: ScopeFilesWrite,
+	},
+	"/projects/:id/download": {
+		http.MethodGet: ScopeFilesRead,
+	},
+	"/projects/:id/workflow": {
+		http.MethodGet: ScopeProjectsRead,
+		http.MethodPut: ScopeProjectsWrite,
+	},
+	"/projects/:id/custom-fields": {
+		http.MethodGet:  ScopeProjectsRead,
+		http.MethodPost: ScopeProjectsWrite,
+	},
+	"/projects/:id/custom-fields/:field_id": {
+		http.MethodPut:    ScopeProjectsWrite,
+		http.MethodDelete: ScopeProjectsWrite,
+	},
+	"/projects/:id/members": {
+		http.MethodGet:  ScopeProjectsRead,
+		http.MethodPost: ScopeProjectsWrite,
+	},
+	"/projects/:id/members/:user_id": {
+		http.MethodPut:    ScopeProjectsWrite,
+		http.MethodDelete: ScopeProjectsWrite,
+	},
+	"/projects/:id/tasks": {
+		http.MethodPost: ScopeTasksWrite,
+		http.MethodGet:  ScopeTasksRead,
+	},
+	"/projects/:id/tasks/:task_id": {
+		http.MethodPut:    ScopeTasksWrite,
+		http.MethodDelete: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/assign": {
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/history": {
+		http.MethodGet: ScopeTasksRead,
+	},
+	"/projects/:id/tasks/:task_id/comments": {
+		http.MethodGet:  ScopeTasksRead,
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/comments/:comment_id": {
+		http.MethodPut:    ScopeTasksWrite,
+		http.MethodDelete: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/checklist": {
+		http.MethodGet:  ScopeTasksRead,
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/checklist/:item_id": {
+		http.MethodPut:    ScopeTasksWrite,
+		http.MethodDelete: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/links": {
+		http.MethodGet:  ScopeTasksRead,
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/links/:link_id": {
+		http.MethodDelete: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/critical-path": {
+		http.MethodGet: ScopeTasksRead,
+	},
+	"/projects/:id/tasks/:task_id/worklogs": {
+		http.MethodGet:  ScopeTasksRead,
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/worklogs/:worklog_id": {
+		http.MethodDelete: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/timer/start": {
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/projects/:id/tasks/:task_id/timer/stop": {
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/user/timer": {
+		http.MethodGet: ScopeTasksRead,
+	},
+	"/projects/:id/timesheet": {
+		http.MethodGet: ScopeProjectsRead,
+	},
+	"/projects/:id/tasks/:task_id/recurrence": {
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/projects/:id/recurrences": {
+		http.MethodGet: ScopeTasksRead,
+	},
+	"/projects/:id/recurrences/:recurrence_id": {
+		http.MethodGet:    ScopeTasksRead,
+		http.MethodPut:    ScopeTasksWrite,
+		http.MethodDelete: ScopeTasksWrite,
+	},
+	"/user/mentions": {
+		http.MethodGet: ScopeTasksRead,
+	},
+	// Настройки доставки уведомлений персональным токенам недоступны
+	"/user/notifications": {
+		http.MethodGet: ScopeTasksRead,
+	},
+	"/user/notifications/:id": {
+		http.MethodPut: ScopeTasksWrite,
+	},
+	"/user/notifications/read-all": {
+		http.MethodPost: ScopeTasksWrite,
+	},
+	"/projects/:id/activity": {
+		http.MethodGet: ScopeProjectsRead,
+	},
+	"/user/tasks": {
+		http.MethodGet: ScopeTasksRead,
+	},
+	"/user/permissions": {
+		http.MethodGet: ScopeProjectsRead,
+	},
+}
+
+// Персональные токены отличаются от JWT префиксом
+const personalTokenPrefix = "gapi_"
+
+// Длина видимой части токена в списке токенов
+const personalTokenPrefixLen = len(personalTokenPrefix) + 6
+
+// Новый персональный токен (случайная строка с префиксом gapi_)
+func newPersonalToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return personalTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Действует ли токен на момент now
+func (t *PersonalToken) active(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// Есть ли у токена область required (:write включает :read)
+func hasScope(scopes []string, required string) bool {
+	if slices.Contains(scopes, required) {
+		return true
+	}
+	if resource, ok := strings.CutSuffix(required, ":read"); ok {
+		return slices.Contains(scopes, resource+":write")
+	}
+	return false
+}
+
+// Аутентификация по персональному токену: пользователь токена записывается в контекст
+// так же, как при JWT, дополнительно — token_id и scopes
+func authenticatePersonalToken(c *gin.Context, raw string) error {
+	ctx := c.Request.Context()
+	token, err := store.PersonalTokens.GetByHash(ctx, hashToken(raw))
+	if errors.Is(err, ErrNotFound) {
+		return errTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil {
+		return errTokenInvalid
+	}
+	if !token.active(now) {
+		return errTokenExpired
+	}
+
+	user, err := store.Users.GetByID(ctx, token.UserID)
+	if errors.Is(err, ErrNotFound) {
+		return errTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if user.DisabledAt != nil {
+		return errAccountDisabled
+	}
+
+	// Время последнего использования обновляется не чаще раза в минуту
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > sessionTouchInterval {
+		if err := store.PersonalTokens.Touch(ctx, token.ID, now); err != nil {
+			return err
+		}
+	}
+
+	c.Set("id", user.ID)
+	c.Set("username", user.Username)
+	c.Set("role", user.Role)
+	c.Set("token_id", token.ID)
+	c.Set("scopes", token.Scopes)
+	return nil
+}
+
+// Проверка области персонального токена для текущего маршрута (для JWT ограничений нет)
+func checkTokenScope(c *gin.Context) bool {
+	value, ok := c.Get("scopes")
+	if !ok {
+		return true
+	}
+
+	required := routeScopes[c.FullPath()][c.Request.Method]
+	if required == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Personal access tokens are not allowed for this route"})
+		c.Abort()
+		return false
+	}
+	if !hasScope(value.([]string), required) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient token scope", "details": "requires " + required})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// @Summary Создание персонального токена доступа
+// @Description Создаёт долгоживущий токен для скриптов и CI с ограниченными областями действия: projects:read, projects:write, tasks:read, tasks:write, files:read, files:write (:write включает :read). Токен возвращается один раз, сохраните его. Передаётся так же, как JWT: заголовок "Authorization: Bearer gapi_...".
+// @Tags Токены доступа
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Param request body personalTokenRequest true "Название, области действия и срок действия"
+// @Success 201 {object} map[string]interface{} "Токен создан"
+// @Failure 400 {object} map[string]string "Некорректные входные данные"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Персональным токеном нельзя создать другой токен"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /user/tokens [post]
+func createPersonalToken(c *gin.Context) {
+	var req personalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	var scopes []string
+	for _, scope := range req.Scopes {
+		if !slices.Contains(personalTokenScopes, scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown scope: " + scope})
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	slices.Sort(scopes)
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Expiry must be in the future"})
+		return
+	}
+
+	raw, err := newPersonalToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	token := PersonalToken{
+		UserID:    c.GetUint("id"),
+		Name:      req.Name,
+		TokenHash: hashToken(raw),
+		Prefix:    raw[:personalTokenPrefixLen],
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := store.PersonalTokens.Create(c.Request.Context(), &token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "personal_token.create", "personal_token", token.ID, 0, nil, token.auditView())
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Токен создан. Сохраните его: повторно он не показывается", "Token": raw, "TokenInfo": token})
+}
+
+// @Summary Персональные токены пользователя
+// @Description Возвращает неотозванные персональные токены текущего пользователя (без самих токенов, только их начало)
+// @Tags Токены доступа
+// @Produce json
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Success 200 {object} map[string]interface{} "Список токенов"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/tokens [get]
+func getPersonalTokens(c *gin.Context) {
+	tokens, err := store.PersonalTokens.ListForUser(c.Request.Context(), c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Tokens": tokens})
+}
+
+// @Summary Отзыв персонального токена
+// @Description Отзывает персональный токен текущего пользователя, токен сразу перестаёт действовать
+// @Tags Токены доступа
+// @Produce json
+// @Param id path int true "ID токена"
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Success 200 {object} map[string]string "Токен отозван"
+// @Failure 400 {object} map[string]string "Некорректный ID токена"
+// @Failure 404 {object} map[string]string "Токен не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/tokens/{id} [delete]
+func deletePersonalToken(c *gin.Context) {
+	tokenID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := store.PersonalTokens.GetForUser(ctx, c.GetUint("id"), uint(tokenID))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Token not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	if err := store.PersonalTokens.Revoke(ctx, token.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "personal_token.revoke", "personal_token", token.ID, 0, token.auditView(), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Токен успешно отозван"})
+}
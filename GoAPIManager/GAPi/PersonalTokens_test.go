@@ -0,0 +1,80 @@
+package GoAPIManager
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Создание персонального токена; возвращает сам токен и его ID
+func (s *testServer) personalToken(jwt string, scopes ...string) (string, uint) {
+	s.t.Helper()
+	out := s.expect(http.StatusCreated, http.MethodPost, "/user/tokens", jwt, gin.H{"name": "ci", "scopes": scopes})
+	raw, _ := out["Token"].(string)
+	if !strings.HasPrefix(raw, personalTokenPrefix) {
+		s.t.Fatalf("token %q has no %q prefix", raw, personalTokenPrefix)
+	}
+	return raw, uint(field(s.t, out, "TokenInfo", "id").(float64))
+}
+
+func TestPersonalTokenScopes(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	s.task(alice, projectID, "First")
+	project := fmt.Sprintf("/projects/%d", projectID)
+
+	read, _ := s.personalToken(alice, ScopeProjectsRead)
+	s.expect(http.StatusOK, http.MethodGet, project, read, nil)
+	s.expect(http.StatusForbidden, http.MethodPut, project, read, gin.H{"name": "Beta"})
+	s.expect(http.StatusForbidden, http.MethodPost, "/projects", read, gin.H{"name": "Beta"})
+	s.expect(http.StatusForbidden, http.MethodGet, project+"/tasks", read, nil)
+	// Токенами нельзя управлять токенами и сессиями
+	s.expect(http.StatusForbidden, http.MethodPost, "/user/tokens", read, gin.H{"name": "x", "scopes": []string{ScopeTasksRead}})
+	s.expect(http.StatusForbidden, http.MethodPost, "/logout-all", read, nil)
+
+	// Область :write включает :read
+	write, _ := s.personalToken(alice, ScopeTasksWrite)
+	s.expect(http.StatusOK, http.MethodGet, project+"/tasks", write, nil)
+	s.expect(http.StatusCreated, http.MethodPost, project+"/tasks", write, gin.H{
+		"title": "From CI", "priority": "Low", "status": "In_Line", "deadline": time.Now().AddDate(0, 0, 1).UTC().Format(time.RFC3339),
+	})
+}
+
+func TestPersonalTokenValidation(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+
+	s.expect(http.StatusBadRequest, http.MethodPost, "/user/tokens", alice, gin.H{"name": "ci", "scopes": []string{"admin"}})
+	s.expect(http.StatusBadRequest, http.MethodPost, "/user/tokens", alice, gin.H{"name": "ci"})
+	s.expect(http.StatusBadRequest, http.MethodPost, "/user/tokens", alice, gin.H{
+		"name": "ci", "scopes": []string{ScopeTasksRead}, "expires_at": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+	})
+}
+
+func TestPersonalTokenRevocation(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	bob := s.user("bobby")
+	raw, id := s.personalToken(alice, ScopeTasksRead)
+	path := fmt.Sprintf("/user/tokens/%d", id)
+
+	s.expect(http.StatusOK, http.MethodGet, "/user/tasks", raw, nil)
+
+	// Список не раскрывает сами токены
+	out := s.request(http.MethodGet, "/user/tokens", alice, nil)
+	if out.Code != http.StatusOK || strings.Contains(out.Body.String(), raw) {
+		t.Fatalf("GET /user/tokens: %d %s", out.Code, out.Body.String())
+	}
+
+	s.expect(http.StatusNotFound, http.MethodDelete, path, bob, nil)
+	s.expect(http.StatusOK, http.MethodGet, "/user/tasks", raw, nil)
+
+	s.expect(http.StatusOK, http.MethodDelete, path, alice, nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/tasks", raw, nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/tasks", personalTokenPrefix+"unknown", nil)
+}
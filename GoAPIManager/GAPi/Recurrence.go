@@ -0,0 +1,611 @@
+package GoAPIManager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Статусы серии повторяющихся задач
+const (
+	RecurrenceActive   = "active"
+	RecurrenceFinished = "finished" // правило исчерпано (UNTIL или COUNT)
+	RecurrenceStopped  = "stopped"  // остановлена вручную
+)
+
+// Частоты повторения
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+)
+
+var recurrenceFrequencies = []string{freqDaily, freqWeekly, freqMonthly}
+
+// Ограничения правила повторения
+const (
+	maxRecurrenceInterval = 1000
+	maxRecurrenceCount    = 1000
+	// Сколько дат правила перебирается в поисках следующей
+	maxRecurrenceSteps = 100000
+)
+
+// Автор событий в ленте проекта, которые создаёт планировщик
+const schedulerActor = "scheduler"
+
+// Серия повторяющихся задач. Каждая задача серии создаётся по образцу предыдущей
+// со сроком по правилу повторения.
+type TaskRecurrence struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ProjectID uint   `gorm:"not null" json:"project_id"`
+	Rule      string `gorm:"not null" json:"rule"`
+	// Срок первой задачи серии (DTSTART)
+	StartsAt time.Time `gorm:"not null" json:"starts_at"`
+	// Текущая (последняя созданная) задача серии и её срок по правилу (null — задача удалена)
+	CurrentTaskID *uint     `json:"current_task_id"`
+	CurrentAt     time.Time `gorm:"not null" json:"current_at"`
+	// Сколько задач создано серией
+	Occurrences int        `gorm:"not null" json:"occurrences"`
+	Status      string     `gorm:"not null" json:"status"`
+	CreatedBy   *uint      `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	EndedAt     *time.Time `json:"ended_at"`
+	// Срок следующей задачи серии (null — серия не активна или правило исчерпано)
+	NextAt *time.Time `gorm:"-" json:"next_at"`
+}
+
+func (TaskRecurrence) TableName() string {
+	return "task_recurrences"
+}
+
+// Тело запроса на настройку повторения
+type recurrenceRequest struct {
+	// Например FREQ=WEEKLY;INTERVAL=2;COUNT=10
+	Rule string `json:"rule"`
+}
+
+// Правило повторения — подмножество RRULE из RFC 5545: FREQ (DAILY, WEEKLY, MONTHLY),
+// INTERVAL и не больше одного из UNTIL и COUNT
+type recurrenceRule struct {
+	Freq     string
+	Interval int
+	Until    time.Time // нулевое значение — без ограничения
+	Count    int       // 0 — без ограничения
+}
+
+func parseRecurrenceRule(raw string) (recurrenceRule, error) {
+	rule := recurrenceRule{Interval: 1}
+	raw = strings.TrimSpace(raw)
+	if len(raw) >= len("RRULE:") && strings.EqualFold(raw[:len("RRULE:")], "RRULE:") {
+		raw = raw[len("RRULE:"):]
+	}
+	if raw == "" {
+		return rule, errors.New("rule is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return rule, fmt.Errorf("invalid rule part %q", part)
+		}
+		if seen[name] {
+			return rule, fmt.Errorf("duplicate rule part %s", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(value)
+			if !slices.Contains(recurrenceFrequencies, rule.Freq) {
+				return rule, fmt.Errorf("unsupported FREQ %s, allowed values are: %s", value, strings.Join(recurrenceFrequencies, ", "))
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > maxRecurrenceInterval {
+				return rule, fmt.Errorf("INTERVAL must be 1-%d", maxRecurrenceInterval)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > maxRecurrenceCount {
+				return rule, fmt.Errorf("COUNT must be 1-%d", maxRecurrenceCount)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseRecurrenceUntil(value)
+			if err != nil {
+				return rule, err
+			}
+			rule.Until = until
+		default:
+			return rule, fmt.Errorf("unsupported rule part %s, allowed parts are: FREQ, INTERVAL, UNTIL, COUNT", name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return rule, errors.New("FREQ is required")
+	}
+	if seen["UNTIL"] && seen["COUNT"] {
+		return rule, errors.New("UNTIL and COUNT must not be used together")
+	}
+	return rule, nil
+}
+
+// UNTIL — время в UTC (YYYYMMDDTHHMMSSZ) или дата (YYYYMMDD, день входит в серию целиком)
+func parseRecurrenceUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	day, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid UNTIL %s, expected YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+// Правило в нормализованном виде, в котором оно хранится
+func (r recurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// k-я дата правила, считая от start
+func (r recurrenceRule) step(start time.Time, k int) time.Time {
+	switch r.Freq {
+	case freqDaily:
+		return start.AddDate(0, 0, k*r.Interval)
+	case freqWeekly:
+		return start.AddDate(0, 0, 7*k*r.Interval)
+	default:
+		return start.AddDate(0, k*r.Interval, 0)
+	}
+}
+
+// Первая дата серии, которая начинается в start, позже after. Как в RFC 5545, месяцы без
+// дня start (например, 31-го) пропускаются и не учитываются в COUNT. false — правило исчерпано.
+func (r recurrenceRule) next(start, after time.Time) (time.Time, bool) {
+	n := 0
+	for k := 0; k < maxRecurrenceSteps; k++ {
+		at := r.step(start, k)
+		if r.Freq == freqMonthly && at.Day() != start.Day() {
+			continue
+		}
+		n++
+		if r.Count > 0 && n > r.Count || !r.Until.IsZero() && at.After(r.Until) {
+			return time.Time{}, false
+		}
+		if at.After(after) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Заполнение срока следующей задачи активной серии
+func (rec *TaskRecurrence) fillNextAt(now time.Time) {
+	rec.NextAt = nil
+	if rec.Status != RecurrenceActive {
+		return
+	}
+	rule, err := parseRecurrenceRule(rec.Rule)
+	if err != nil {
+		return
+	}
+	if next, ok := rule.next(rec.StartsAt, laterTime(rec.CurrentAt, now)); ok {
+		rec.NextAt = &next
+	}
+}
+
+// Более позднее из двух времён
+func laterTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// Создание следующей задачи серии. Пропущенные даты (задачу завершили с опозданием, сервер
+// не работал) не создаются: следующая задача получает первую дату правила позже срока текущей
+// задачи и позже now. Если правило исчерпано или у серии не осталось задач, серия завершается.
+// Возвращает созданную задачу (nil — задача не создана).
+func advanceRecurrence(ctx context.Context, rec *TaskRecurrence, now time.Time) (*Task, error) {
+	rule, err := parseRecurrenceRule(rec.Rule)
+	if err != nil {
+		return nil, err
+	}
+	next, ok := rule.next(rec.StartsAt, laterTime(rec.CurrentAt, now))
+
+	var template *Task
+	if ok {
+		template, err = recurrenceTemplate(ctx, rec)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if template == nil {
+		err := store.Recurrences.End(ctx, rec.ID, RecurrenceFinished, now)
+		if errors.Is(err, ErrNotFound) {
+			// Серию уже завершили или остановили
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	assigneeIDs, err := recurrenceAssignees(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := store.Workflows.Get(ctx, template.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Следующая задача получает значения пользовательских полей образца
+	templates := []Task{*template}
+	if err := loadCustomFields(ctx, templates); err != nil {
+		return nil, err
+	}
+
+	task := templates[0].clone()
+	task.ID = 0
+	task.Status = workflow.initialStatus()
+	task.Deadline = next
+	task.AssigneeID = assigneeIDs[0]
+	task.AssigneeIDs = assigneeIDs
+	task.RecurrenceID = &rec.ID
+
+	details, err := json.Marshal(gin.H{"title": task.Title, "recurrence_id": rec.ID})
+	if err != nil {
+		return nil, err
+	}
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Tasks.Create(ctx, &task, assigneeIDs); err != nil {
+			return err
+		}
+		if err := tx.CustomFields.CopyValues(ctx, template.ID, task.ID); err != nil {
+			return err
+		}
+		if err := tx.Recurrences.Advance(ctx, rec, &task, next); err != nil {
+			return err
+		}
+		return tx.Activity.Create(ctx, &Activity{
+			ProjectID: task.ProjectID,
+			TaskID:    &task.ID,
+			Actor:     schedulerActor,
+			Type:      ActivityTaskCreated,
+			Details:   details,
+		})
+	})
+	if errors.Is(err, ErrNotFound) {
+		// Следующую задачу уже создал другой запрос или другой экземпляр сервера
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Образец следующей задачи: текущая задача серии, а если она удалена — последняя оставшаяся
+// (nil — у серии не осталось задач)
+func recurrenceTemplate(ctx context.Context, rec *TaskRecurrence) (*Task, error) {
+	if rec.CurrentTaskID != nil {
+		task, err := store.Tasks.GetByID(ctx, *rec.CurrentTaskID)
+		if err == nil {
+			return task, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	tasks, err := store.Recurrences.ListTasks(ctx, rec.ID)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	return &tasks[len(tasks)-1], nil
+}
+
+// Исполнители следующей задачи: исполнители образца, которые всё ещё могут работать над задачами
+// проекта, а если таких нет — владелец проекта
+func recurrenceAssignees(ctx context.Context, template *Task) ([]uint, error) {
+	tasks := []Task{*template}
+	if err := store.Tasks.LoadAssignees(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	var assigneeIDs []uint
+	if len(tasks[0].AssigneeIDs) > 0 {
+		roles, err := store.Projects.MemberRoles(ctx, template.ProjectID, tasks[0].AssigneeIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range tasks[0].AssigneeIDs {
+			if role, ok := roles[id]; ok && projectRoleAtLeast(role, ProjectRoleMember) {
+				assigneeIDs = append(assigneeIDs, id)
+			}
+		}
+	}
+	if len(assigneeIDs) > 0 {
+		return assigneeIDs, nil
+	}
+
+	members, err := store.Projects.ListMembers(ctx, template.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.Role == ProjectRoleOwner {
+			return []uint{member.UserID}, nil
+		}
+	}
+	return nil, fmt.Errorf("project %d has no owner", template.ProjectID)
+}
+
+// Завершение задачи серии: если это текущая задача активной серии, сразу создаётся следующая.
+// Ошибка не мешает обновлению задачи — следующую задачу создаст планировщик.
+func completeRecurringTask(ctx context.Context, task *Task) *Task {
+	rec, err := store.Recurrences.GetByID(ctx, *task.RecurrenceID)
+	if err != nil {
+		log.Printf("[SCHEDULER] Серия %d: %v", *task.RecurrenceID, err)
+		return nil
+	}
+	if rec.Status != RecurrenceActive || rec.CurrentTaskID == nil || *rec.CurrentTaskID != task.ID {
+		return nil
+	}
+	next, err := advanceRecurrence(ctx, rec, time.Now())
+	if err != nil {
+		log.Printf("[SCHEDULER] Серия %d: %v", rec.ID, err)
+		return nil
+	}
+	return next
+}
+
+// Фоновая задача планировщика: следующие задачи всех серий, у которых текущая задача
+// завершена или наступил её срок
+func processDueRecurrences(ctx context.Context, now time.Time) {
+	due, err := store.Recurrences.ListDue(ctx, now)
+	if err != nil {
+		log.Printf("[SCHEDULER] Ошибка чтения повторяющихся задач: %v", err)
+		return
+	}
+	for i := range due {
+		if _, err := advanceRecurrence(ctx, &due[i], now); err != nil {
+			log.Printf("[SCHEDULER] Серия %d: %v", due[i].ID, err)
+		}
+	}
+}
+
+// Привязка и проверка правила повторения из тела запроса. При ошибке отвечает 400 и возвращает false.
+func bindRecurrenceRule(c *gin.Context) (recurrenceRule, bool) {
+	var req recurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return recurrenceRule{}, false
+	}
+	rule, err := parseRecurrenceRule(req.Rule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule: " + err.Error()})
+		return recurrenceRule{}, false
+	}
+	return rule, true
+}
+
+// @Summary Настройка повторения задачи
+// @Description Делает задачу первой задачей серии повторяющихся задач. Правило — подмножество RRULE из RFC 5545: FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, UNTIL (YYYYMMDD или YYYYMMDDTHHMMSSZ) или COUNT, например FREQ=WEEKLY;INTERVAL=2;COUNT=10. Даты серии отсчитываются от дедлайна задачи. Когда текущая задача серии завершена (статус категории done) или наступил её дедлайн, создаётся следующая задача с теми же полями и исполнителями, первым статусом категории todo и дедлайном по правилу; пропущенные даты не создаются.
+// @Tags Повторяющиеся задачи
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body recurrenceRequest true "Правило повторения"
+// @Success 201 {object} map[string]interface{} "Серия создана"
+// @Failure 400 {object} map[string]string "Некорректное правило"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 409 {object} map[string]string "Задача уже входит в серию"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/recurrence [post]
+func createTaskRecurrence(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	rule, ok := bindRecurrenceRule(c)
+	if !ok {
+		return
+	}
+	if task.RecurrenceID != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Task already belongs to a recurrence"})
+		return
+	}
+
+	now := time.Now()
+	rec := TaskRecurrence{
+		ProjectID:     task.ProjectID,
+		Rule:          rule.String(),
+		StartsAt:      task.Deadline,
+		CurrentTaskID: &task.ID,
+		CurrentAt:     task.Deadline,
+		Occurrences:   1,
+		Status:        RecurrenceActive,
+		CreatedBy:     requestActor(c),
+		CreatedAt:     now,
+	}
+	err := store.Recurrences.Create(c.Request.Context(), &rec)
+	if errors.Is(err, ErrDuplicate) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Task already belongs to a recurrence"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create recurrence: " + err.Error()})
+		return
+	}
+	recordAudit(c, "recurrence.create", "recurrence", rec.ID, rec.ProjectID, nil, &rec)
+
+	rec.fillNextAt(now)
+	c.JSON(http.StatusCreated, gin.H{"message": "Повторение задачи настроено", "Recurrence": rec})
+}
+
+// @Summary Серии повторяющихся задач проекта
+// @Description Возвращает все серии проекта, включая завершённые и остановленные
+// @Tags Повторяющиеся задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Серии проекта"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/recurrences [get]
+func getProjectRecurrences(c *gin.Context) {
+	// Проект :id (найден в permissionMiddleware)
+	project := c.MustGet("project").(*Project)
+
+	recurrences, err := store.Recurrences.ListForProject(c.Request.Context(), project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	for i := range recurrences {
+		recurrences[i].fillNextAt(now)
+	}
+	c.JSON(http.StatusOK, gin.H{"Recurrences": recurrences})
+}
+
+// @Summary Серия повторяющихся задач
+// @Description Возвращает серию и все её задачи (старые первыми)
+// @Tags Повторяющиеся задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param recurrence_id path int true "ID серии"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Серия и её задачи"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Серия не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/recurrences/{recurrence_id} [get]
+func getRecurrence(c *gin.Context) {
+	// Серия проекта :id (найдена в permissionMiddleware)
+	rec := c.MustGet("recurrence").(*TaskRecurrence)
+
+	ctx := c.Request.Context()
+	tasks, err := store.Recurrences.ListTasks(ctx, rec.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err := store.Tasks.LoadAssignees(ctx, tasks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err := loadCustomFields(ctx, tasks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	rec.fillNextAt(time.Now())
+	c.JSON(http.StatusOK, gin.H{"Recurrence": rec, "Tasks": tasks})
+}
+
+// @Summary Изменение правила повторения
+// @Description Заменяет правило активной серии. Новое правило действует для следующих задач, даты по-прежнему отсчитываются от дедлайна первой задачи серии.
+// @Tags Повторяющиеся задачи
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param recurrence_id path int true "ID серии"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body recurrenceRequest true "Новое правило повторения"
+// @Success 200 {object} map[string]interface{} "Правило изменено"
+// @Failure 400 {object} map[string]string "Некорректное правило"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Серия не найдена"
+// @Failure 409 {object} map[string]string "Серия завершена или остановлена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/recurrences/{recurrence_id} [put]
+func updateRecurrence(c *gin.Context) {
+	// Серия проекта :id (найдена в permissionMiddleware)
+	found := c.MustGet("recurrence").(*TaskRecurrence)
+
+	rule, ok := bindRecurrenceRule(c)
+	if !ok {
+		return
+	}
+	if found.Status != RecurrenceActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Recurrence is not active"})
+		return
+	}
+
+	rec := *found
+	rec.Rule = rule.String()
+	if err := store.Recurrences.UpdateRule(c.Request.Context(), rec.ID, rec.Rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update recurrence: " + err.Error()})
+		return
+	}
+	recordAudit(c, "recurrence.update", "recurrence", rec.ID, rec.ProjectID, found, &rec)
+
+	rec.fillNextAt(time.Now())
+	c.JSON(http.StatusOK, gin.H{"message": "Правило повторения изменено", "Recurrence": rec})
+}
+
+// @Summary Остановка серии повторяющихся задач
+// @Description Останавливает серию: новые задачи больше не создаются, уже созданные задачи остаются
+// @Tags Повторяющиеся задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param recurrence_id path int true "ID серии"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Серия остановлена"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Серия не найдена"
+// @Failure 409 {object} map[string]string "Серия уже завершена или остановлена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/recurrences/{recurrence_id} [delete]
+func stopRecurrence(c *gin.Context) {
+	// Серия проекта :id (найдена в permissionMiddleware)
+	found := c.MustGet("recurrence").(*TaskRecurrence)
+
+	now := time.Now()
+	err := store.Recurrences.End(c.Request.Context(), found.ID, RecurrenceStopped, now)
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Recurrence is not active"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop recurrence: " + err.Error()})
+		return
+	}
+
+	rec := *found
+	rec.Status = RecurrenceStopped
+	rec.EndedAt = &now
+	recordAudit(c, "recurrence.stop", "recurrence", rec.ID, rec.ProjectID, found, &rec)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Повторение задачи остановлено", "Recurrence": rec})
+}
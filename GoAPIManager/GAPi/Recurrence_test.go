@@ -0,0 +1,262 @@
+package GoAPIManager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ID пользователя по имени
+func userID(t *testing.T, username string) uint {
+	t.Helper()
+	user, err := store.Users.GetByUsername(context.Background(), username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+// Время из JSON-ответа
+func jsonTime(t *testing.T, v interface{}) time.Time {
+	t.Helper()
+	s, _ := v.(string)
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("invalid time %v: %v", v, err)
+	}
+	return at
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	for _, tc := range []struct {
+		raw  string
+		want string // нормализованное правило; пусто — правило некорректно
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=weekly; interval=2 ;count=10", "FREQ=WEEKLY;INTERVAL=2;COUNT=10"},
+		{"FREQ=MONTHLY;INTERVAL=1", "FREQ=MONTHLY"},
+		{"FREQ=DAILY;INTERVAL=1000", "FREQ=DAILY;INTERVAL=1000"},
+		{"FREQ=DAILY;COUNT=1", "FREQ=DAILY;COUNT=1"},
+		{"FREQ=DAILY;COUNT=1000", "FREQ=DAILY;COUNT=1000"},
+		// UNTIL-дата включает весь день
+		{"FREQ=DAILY;UNTIL=20260301", "FREQ=DAILY;UNTIL=20260301T235959Z"},
+		{"FREQ=DAILY;UNTIL=20260301T120000Z", "FREQ=DAILY;UNTIL=20260301T120000Z"},
+
+		{"", ""},
+		{"RRULE:", ""},
+		{"INTERVAL=2", ""},
+		{"FREQ=YEARLY", ""},
+		{"FREQ", ""},
+		{"FREQ=DAILY;", ""},
+		{"FREQ=DAILY;FREQ=WEEKLY", ""},
+		{"FREQ=DAILY;BYDAY=MO", ""},
+		{"FREQ=DAILY;INTERVAL=0", ""},
+		{"FREQ=DAILY;INTERVAL=1001", ""},
+		{"FREQ=DAILY;INTERVAL=two", ""},
+		{"FREQ=DAILY;COUNT=0", ""},
+		{"FREQ=DAILY;COUNT=1001", ""},
+		{"FREQ=DAILY;UNTIL=2026-03-01", ""},
+		{"FREQ=DAILY;UNTIL=20260301T120000", ""},
+		{"FREQ=DAILY;UNTIL=20260301;COUNT=2", ""},
+	} {
+		rule, err := parseRecurrenceRule(tc.raw)
+		switch {
+		case tc.want == "" && err == nil:
+			t.Errorf("%q: parsed as %s, want error", tc.raw, rule)
+		case tc.want != "" && err != nil:
+			t.Errorf("%q: %v", tc.raw, err)
+		case tc.want != "" && rule.String() != tc.want:
+			t.Errorf("%q: rule = %s, want %s", tc.raw, rule, tc.want)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	jan1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	jan31 := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 10, 0, 0, 0, time.UTC) }
+
+	for _, tc := range []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		want  time.Time // нулевое значение — правило исчерпано
+	}{
+		{"first date", "FREQ=DAILY", jan1, jan1.Add(-time.Second), jan1},
+		{"daily interval", "FREQ=DAILY;INTERVAL=2", jan1, jan1, day(1, 3)},
+		{"between dates", "FREQ=DAILY;INTERVAL=2", jan1, day(1, 4), day(1, 5)},
+		{"weekly", "FREQ=WEEKLY", jan1, jan1, day(1, 8)},
+		{"monthly", "FREQ=MONTHLY;INTERVAL=2", jan1, jan1, day(3, 1)},
+		// В феврале и апреле нет 31-го числа
+		{"monthly skips february", "FREQ=MONTHLY", jan31, jan31, day(3, 31)},
+		{"monthly skips april", "FREQ=MONTHLY", jan31, day(3, 31), day(5, 31)},
+		// Пропущенные месяцы не учитываются в COUNT
+		{"skipped months not counted", "FREQ=MONTHLY;COUNT=3", jan31, day(3, 31), day(5, 31)},
+		{"monthly count exhausted", "FREQ=MONTHLY;COUNT=3", jan31, day(5, 31), time.Time{}},
+		{"count last", "FREQ=DAILY;COUNT=3", jan1, day(1, 2), day(1, 3)},
+		{"count exhausted", "FREQ=DAILY;COUNT=3", jan1, day(1, 3), time.Time{}},
+		{"single occurrence", "FREQ=WEEKLY;COUNT=1", jan1, jan1, time.Time{}},
+		{"until date includes day", "FREQ=DAILY;UNTIL=20260103", jan1, day(1, 2), day(1, 3)},
+		{"until date exhausted", "FREQ=DAILY;UNTIL=20260103", jan1, day(1, 3), time.Time{}},
+		{"until time excludes later date", "FREQ=DAILY;UNTIL=20260103T095959Z", jan1, day(1, 2), time.Time{}},
+		{"until time inclusive", "FREQ=DAILY;UNTIL=20260103T100000Z", jan1, day(1, 2), day(1, 3)},
+	} {
+		rule, err := parseRecurrenceRule(tc.rule)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got, ok := rule.next(tc.start, tc.after)
+		if ok != !tc.want.IsZero() || !got.Equal(tc.want) {
+			t.Errorf("%s: next = %v, %v; want %v", tc.name, got, ok, tc.want)
+		}
+	}
+}
+
+func TestRecurringTaskCompletion(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	s.user("bobby")
+	projectID := s.project(alice, "Alpha")
+	s.addMember(alice, projectID, "bobby", ProjectRoleMember)
+	aliceID, bobID := userID(t, "alice"), userID(t, "bobby")
+
+	deadline := time.Now().UTC().Truncate(time.Second).Add(time.Hour)
+	out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), alice, gin.H{
+		"title": "Weekly report", "priority": "Medium", "status": "In_Progress", "deadline": deadline.Format(time.RFC3339),
+		"assignee_ids": []uint{bobID, aliceID},
+	})
+	taskID := uint(field(t, out, "Task", "ID").(float64))
+
+	out = s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks/%d/recurrence", projectID, taskID), alice, gin.H{"rule": "FREQ=WEEKLY;COUNT=3"})
+	recurrenceID := uint(field(t, out, "Recurrence", "id").(float64))
+	if next := jsonTime(t, field(t, out, "Recurrence", "next_at")); !next.Equal(deadline.AddDate(0, 0, 7)) {
+		t.Errorf("next_at = %v, want %v", next, deadline.AddDate(0, 0, 7))
+	}
+	s.expect(http.StatusConflict, http.MethodPost, fmt.Sprintf("/projects/%d/tasks/%d/recurrence", projectID, taskID), alice, gin.H{"rule": "FREQ=DAILY"})
+
+	complete := func(id uint, title string, at time.Time) map[string]interface{} {
+		t.Helper()
+		return s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("/projects/%d/tasks/%d", projectID, id), alice, gin.H{
+			"title": title, "priority": "Medium", "status": "Done", "deadline": at.Format(time.RFC3339),
+		})
+	}
+
+	// Следующая задача: те же поля и исполнители, статус In_Line, срок по правилу
+	out = complete(taskID, "Weekly report", deadline)
+	next, ok := out["NextTask"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("no NextTask in %v", out)
+	}
+	if got := jsonTime(t, next["deadline"]); !got.Equal(deadline.AddDate(0, 0, 7)) {
+		t.Errorf("next deadline = %v, want %v", got, deadline.AddDate(0, 0, 7))
+	}
+	if next["status"] != "In_Line" || next["title"] != "Weekly report" || next["priority"] != "Medium" || next["recurrence_id"] != float64(recurrenceID) {
+		t.Errorf("next task = %v", next)
+	}
+	if got := responseIDs(t, next["assignee_ids"], ""); !slices.Equal(got, []uint{bobID, aliceID}) {
+		t.Errorf("next assignees = %v, want %v", got, []uint{bobID, aliceID})
+	}
+
+	// Повторное завершение прежней задачи не создаёт новую
+	if out := complete(taskID, "Weekly report", deadline); out["NextTask"] != nil {
+		t.Errorf("completed task again: NextTask = %v", out["NextTask"])
+	}
+
+	// Исполнитель, который больше не участник проекта, в следующую задачу не переходит
+	s.expect(http.StatusOK, http.MethodDelete, fmt.Sprintf("/projects/%d/members/%d", projectID, bobID), alice, nil)
+	secondID := uint(next["ID"].(float64))
+	out = complete(secondID, "Weekly report", deadline.AddDate(0, 0, 7))
+	third, _ := out["NextTask"].(map[string]interface{})
+	if got := responseIDs(t, third["assignee_ids"], ""); !slices.Equal(got, []uint{aliceID}) {
+		t.Errorf("third assignees = %v, want %v", got, []uint{aliceID})
+	}
+
+	// COUNT исчерпан: следующей задачи нет, серия завершена
+	if out := complete(uint(third["ID"].(float64)), "Weekly report", deadline.AddDate(0, 0, 14)); out["NextTask"] != nil {
+		t.Errorf("after COUNT: NextTask = %v", out["NextTask"])
+	}
+	out = s.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/projects/%d/recurrences/%d", projectID, recurrenceID), alice, nil)
+	if field(t, out, "Recurrence", "status") != RecurrenceFinished || field(t, out, "Recurrence", "next_at") != nil {
+		t.Errorf("finished recurrence = %v", out["Recurrence"])
+	}
+	if tasks, _ := out["Tasks"].([]interface{}); len(tasks) != 3 {
+		t.Errorf("recurrence tasks = %d, want 3", len(tasks))
+	}
+}
+
+func TestProcessDueRecurrences(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	ctx := context.Background()
+
+	deadline := time.Now().UTC().Truncate(time.Second).Add(time.Hour)
+	out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), alice, gin.H{
+		"title": "Backup", "priority": "Low", "status": "In_Line", "deadline": deadline.Format(time.RFC3339),
+	})
+	taskID := uint(field(t, out, "Task", "ID").(float64))
+	out = s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks/%d/recurrence", projectID, taskID), alice, gin.H{"rule": "FREQ=DAILY"})
+	recurrenceID := uint(field(t, out, "Recurrence", "id").(float64))
+
+	seriesDeadlines := func() []time.Time {
+		t.Helper()
+		tasks, err := store.Recurrences.ListTasks(ctx, recurrenceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var deadlines []time.Time
+		for _, task := range tasks {
+			deadlines = append(deadlines, task.Deadline.UTC())
+		}
+		return deadlines
+	}
+	checkDeadlines := func(name string, want ...time.Time) {
+		t.Helper()
+		if got := seriesDeadlines(); !slices.EqualFunc(got, want, time.Time.Equal) {
+			t.Errorf("%s: deadlines = %v, want %v", name, got, want)
+		}
+	}
+
+	// Срок текущей задачи не наступил, и она не завершена
+	processDueRecurrences(ctx, time.Now())
+	checkDeadlines("not due", deadline)
+
+	processDueRecurrences(ctx, deadline.Add(time.Minute))
+	checkDeadlines("due", deadline, deadline.AddDate(0, 0, 1))
+
+	// Пропущенные даты не создаются: следующий срок позже now
+	processDueRecurrences(ctx, deadline.AddDate(0, 0, 3).Add(time.Minute))
+	checkDeadlines("missed dates", deadline, deadline.AddDate(0, 0, 1), deadline.AddDate(0, 0, 4))
+
+	// Повторное продвижение серии по устаревшему состоянию (другой запрос или экземпляр
+	// сервера уже создал задачу) ничего не создаёт
+	rec, err := store.Recurrences.GetByID(ctx, recurrenceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := *rec
+	now := deadline.AddDate(0, 0, 4).Add(time.Minute)
+	if task, err := advanceRecurrence(ctx, rec, now); err != nil || task == nil {
+		t.Fatalf("advance: task %v, err %v", task, err)
+	}
+	if task, err := advanceRecurrence(ctx, &stale, now); err != nil || task != nil {
+		t.Errorf("duplicate advance: task %v, err %v", task, err)
+	}
+	checkDeadlines("duplicate advance", deadline, deadline.AddDate(0, 0, 1), deadline.AddDate(0, 0, 4), deadline.AddDate(0, 0, 5))
+
+	// Остановленная серия не продолжается
+	s.expect(http.StatusOK, http.MethodDelete, fmt.Sprintf("/projects/%d/recurrences/%d", projectID, recurrenceID), alice, nil)
+	processDueRecurrences(ctx, deadline.AddDate(0, 0, 30))
+	if n := len(seriesDeadlines()); n != 4 {
+		t.Errorf("stopped recurrence tasks = %d, want 4", n)
+	}
+	if task, err := advanceRecurrence(ctx, &stale, now); err != nil || task != nil {
+		t.Errorf("advance stopped recurrence: task %v, err %v", task, err)
+	}
+}
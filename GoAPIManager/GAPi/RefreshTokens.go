@@ -0,0 +1,185 @@
+package GoAPIManager
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Refresh-токен сессии. Сам токен — случайная строка, в базе хранится только её SHA-256.
+// Все токены одной сессии образуют семейство ротаций: каждый токен можно обменять ровно один раз.
+type RefreshToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	SessionID uint       `gorm:"not null;index"`
+	TokenHash string     `gorm:"not null;unique"`
+	CreatedAt time.Time  `gorm:"not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // время обмена на новый токен
+}
+
+// Тело запроса на обновление токенов
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+var (
+	errRefreshTokenInvalid = errors.New("invalid refresh token")
+	errRefreshTokenExpired = errors.New("refresh token expired")
+	errRefreshTokenReuse   = errors.New("refresh token reuse detected")
+)
+
+// Хеш токена (refresh или персонального) для хранения и поиска
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Выдача нового refresh-токена для сессии
+func issueRefreshToken(ctx context.Context, tokens RefreshTokenRepository, session *Session) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	err := tokens.Create(ctx, &RefreshToken{
+		SessionID: session.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(cfg.Auth.RefreshTokenTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Обмен refresh-токена на новый. Повторное предъявление уже обменянного токена означает,
+// что токен украден: вся сессия (семейство токенов) отзывается.
+func rotateRefreshToken(ctx context.Context, raw string) (*Session, string, error) {
+	current, err := store.RefreshTokens.GetByHash(ctx, hashToken(raw))
+	if errors.Is(err, ErrNotFound) {
+		return nil, "", errRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	session, err := store.Sessions.GetByID(ctx, current.SessionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	if current.UsedAt != nil {
+		return session, "", revokeRefreshFamily(ctx, session)
+	}
+	if !session.active(now) {
+		return nil, "", errSessionRevoked
+	}
+	if !now.Before(current.ExpiresAt) {
+		return nil, "", errRefreshTokenExpired
+	}
+
+	var next string
+	err = store.Transaction(ctx, func(tx *Store) error {
+		ok, err := tx.RefreshTokens.MarkUsed(ctx, current.ID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// Токен успели обменять параллельным запросом
+			return errRefreshTokenReuse
+		}
+		if next, err = issueRefreshToken(ctx, tx.RefreshTokens, session); err != nil {
+			return err
+		}
+		return tx.Sessions.Touch(ctx, session.ID, now, now.Add(cfg.Auth.RefreshTokenTTL))
+	})
+	if errors.Is(err, errRefreshTokenReuse) {
+		return session, "", revokeRefreshFamily(ctx, session)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return session, next, nil
+}
+
+// Отзыв сессии после повторного использования refresh-токена
+func revokeRefreshFamily(ctx context.Context, session *Session) error {
+	log.Printf("[SECURITY] refresh token reuse detected: user %d, session %d, ip %s", session.UserID, session.ID, session.IP)
+	if err := store.Sessions.Revoke(ctx, session.ID); err != nil {
+		return err
+	}
+	return errRefreshTokenReuse
+}
+
+// @Summary Обновление access и refresh токенов
+// @Description Обменивает refresh-токен на новую пару токенов. Каждый refresh-токен действует один раз: повторное использование уже обменянного токена завершает всю сессию.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param request body refreshRequest true "Refresh-токен"
+// @Success 200 {object} map[string]string "Новые access и refresh токены"
+// @Failure 400 {object} map[string]string "Некорректные входные данные"
+// @Failure 401 {object} map[string]string "Неверный, истёкший или повторно использованный refresh-токен"
+// @Failure 403 {object} map[string]string "Учётная запись заблокирована"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /auth/refresh [post]
+func refreshTokens(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	session, newRefreshToken, err := rotateRefreshToken(ctx, req.RefreshToken)
+	switch {
+	case errors.Is(err, errRefreshTokenInvalid):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	case errors.Is(err, errRefreshTokenExpired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token expired"})
+		return
+	case errors.Is(err, errRefreshTokenReuse):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, session revoked"})
+		return
+	case errors.Is(err, errSessionRevoked):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session revoked"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	user, err := store.Users.GetByID(ctx, session.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		return
+	}
+
+	newAccessToken, err := generateAccessToken(*user, session.JTI)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate access token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"AccessToken": newAccessToken, "RefreshToken": newRefreshToken})
+}
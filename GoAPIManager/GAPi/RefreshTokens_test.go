@@ -0,0 +1,76 @@
+package GoAPIManager
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func refreshToken(t *testing.T, tokens map[string]interface{}) string {
+	t.Helper()
+	token, _ := tokens["RefreshToken"].(string)
+	if token == "" {
+		t.Fatalf("no refresh token in %v", tokens)
+	}
+	return token
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	s := newTestServer(t)
+	s.register("alice")
+	first := refreshToken(t, s.loginTokens("alice"))
+
+	out := s.expect(http.StatusOK, http.MethodPost, "/auth/refresh", "", gin.H{"refresh_token": first})
+	second := refreshToken(t, out)
+	if second == first {
+		t.Fatal("refresh returned the same token")
+	}
+	access, _ := out["AccessToken"].(string)
+	s.expect(http.StatusOK, http.MethodGet, "/user/sessions", access, nil)
+
+	out = s.expect(http.StatusOK, http.MethodPost, "/auth/refresh", "", gin.H{"refresh_token": second})
+	refreshToken(t, out)
+
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/auth/refresh", "", gin.H{"refresh_token": "unknown"})
+	s.expect(http.StatusBadRequest, http.MethodPost, "/auth/refresh", "", gin.H{})
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	s := newTestServer(t)
+	s.register("alice")
+	tokens := s.loginTokens("alice")
+	access := accessToken(t, tokens)
+	stolen := refreshToken(t, tokens)
+	other := accessToken(t, s.loginTokens("alice"))
+
+	rotated := refreshToken(t, s.expect(http.StatusOK, http.MethodPost, "/auth/refresh", "", gin.H{"refresh_token": stolen}))
+
+	// Повторное использование уже обменянного токена отзывает всю сессию
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/auth/refresh", "", gin.H{"refresh_token": stolen})
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/auth/refresh", "", gin.H{"refresh_token": rotated})
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/sessions", access, nil)
+	// Другие сессии пользователя не затрагиваются
+	s.expect(http.StatusOK, http.MethodGet, "/user/sessions", other, nil)
+}
+
+func TestRefreshTokensStoredHashed(t *testing.T) {
+	s := newTestServer(t)
+	s.register("alice")
+	raw := refreshToken(t, s.loginTokens("alice"))
+
+	var hashes []string
+	if err := db.Model(&RefreshToken{}).Pluck("token_hash", &hashes).Error; err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, hash := range hashes {
+		if hash == raw {
+			t.Fatal("refresh token stored in plain text")
+		}
+		found = found || hash == hashToken(raw)
+	}
+	if !found {
+		t.Errorf("no stored hash for the issued token among %d rows", len(hashes))
+	}
+}
@@ -0,0 +1,52 @@
+package GoAPIManager
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Периодическая фоновая задача сервера
+type schedulerJob struct {
+	Name string
+	Run  func(ctx context.Context, now time.Time)
+}
+
+// Фоновые задачи, которые запускает планировщик
+var schedulerJobs = []schedulerJob{
+	{"recurrences", processDueRecurrences},
+	{"reminders", processReminders},
+}
+
+// Запуск планировщика: каждая фоновая задача выполняется сразу и затем раз в interval,
+// пока не отменён ctx
+func startScheduler(ctx context.Context, interval time.Duration) {
+	for _, job := range schedulerJobs {
+		go runSchedulerJob(ctx, job, interval)
+	}
+}
+
+func runSchedulerJob(ctx context.Context, job schedulerJob, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		runSchedulerJobOnce(ctx, job, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Один запуск фоновой задачи: не дольше interval, паника не останавливает сервер
+func runSchedulerJobOnce(ctx context.Context, job schedulerJob, interval time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[SCHEDULER] Фоновая задача %s завершилась с ошибкой: %v", job.Name, r)
+		}
+	}()
+	job.Run(ctx, time.Now())
+}
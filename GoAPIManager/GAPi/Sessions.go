@@ -0,0 +1,205 @@
+package GoAPIManager
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Сессия пользователя. Каждый вход создаёт сессию, её jti записывается в выданные токены,
+// поэтому отзыв сессии сразу делает недействительными все токены этого устройства.
+type Session struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	JTI        string     `gorm:"column:jti;not null;unique" json:"-"`
+	DeviceName string     `gorm:"not null" json:"device_name"`
+	IP         string     `gorm:"column:ip;not null" json:"ip"`
+	UserAgent  string     `gorm:"not null" json:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `gorm:"not null" json:"last_used_at"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"-"`
+}
+
+// Сессия в списке сессий пользователя
+type sessionView struct {
+	Session
+	Current bool `json:"current"`
+}
+
+// Как часто обновлять last_used_at (чтобы не писать в базу на каждый запрос)
+const sessionTouchInterval = time.Minute
+
+var errSessionRevoked = errors.New("session revoked")
+
+// Случайный идентификатор токена (jti)
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Активна ли сессия на момент now
+func (s *Session) active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Создание сессии для пользователя с данными устройства из запроса
+func createSession(c *gin.Context, sessions SessionRepository, user *User, deviceName string) (*Session, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	if deviceName == "" {
+		deviceName = c.Request.UserAgent()
+	}
+
+	now := time.Now()
+	session := &Session{
+		UserID:     user.ID,
+		JTI:        jti,
+		DeviceName: deviceName,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(cfg.Auth.RefreshTokenTTL),
+	}
+	if err := sessions.Create(c.Request.Context(), session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Проверка сессии токена: сессия должна существовать, принадлежать пользователю и быть активной
+func checkSession(ctx context.Context, jti string, userID uint) (*Session, error) {
+	if jti == "" {
+		return nil, errSessionRevoked
+	}
+	session, err := store.Sessions.GetByJTI(ctx, jti)
+	if errors.Is(err, ErrNotFound) {
+		return nil, errSessionRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID || !session.active(time.Now()) {
+		return nil, errSessionRevoked
+	}
+
+	// Время последнего использования обновляется не чаще раза в минуту
+	if now := time.Now(); now.Sub(session.LastUsedAt) > sessionTouchInterval {
+		if err := store.Sessions.Touch(ctx, session.ID, now, session.ExpiresAt); err != nil {
+			return nil, err
+		}
+		session.LastUsedAt = now
+	}
+	return session, nil
+}
+
+// @Summary Выход
+// @Description Завершает текущую сессию: access- и refresh-токены этого устройства перестают действовать
+// @Tags Сессии
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Сессия завершена"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /logout [post]
+func logout(c *gin.Context) {
+	if err := store.Sessions.Revoke(c.Request.Context(), c.GetUint("session_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Вы успешно вышли"})
+}
+
+// @Summary Выход на всех устройствах
+// @Description Завершает все сессии текущего пользователя, включая текущую
+// @Tags Сессии
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Сессии завершены"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /logout-all [post]
+func logoutAll(c *gin.Context) {
+	revoked, err := store.Sessions.RevokeAll(c.Request.Context(), c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Вы вышли на всех устройствах", "Revoked": revoked})
+}
+
+// @Summary Активные сессии пользователя
+// @Description Возвращает активные сессии текущего пользователя (устройство, IP, время входа и последнего использования)
+// @Tags Сессии
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Список сессий"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/sessions [get]
+func getUserSessions(c *gin.Context) {
+	sessions, err := store.Sessions.ListActive(c.Request.Context(), c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	current := c.GetUint("session_id")
+	views := make([]sessionView, len(sessions))
+	for i, s := range sessions {
+		views[i] = sessionView{Session: s, Current: s.ID == current}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Sessions": views})
+}
+
+// @Summary Завершение сессии
+// @Description Завершает одну из сессий текущего пользователя (например, на потерянном устройстве)
+// @Tags Сессии
+// @Produce json
+// @Param id path int true "ID сессии"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Сессия завершена"
+// @Failure 400 {object} map[string]string "Некорректный ID сессии"
+// @Failure 404 {object} map[string]string "Сессия не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/sessions/{id} [delete]
+func deleteUserSession(c *gin.Context) {
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	session, err := store.Sessions.GetForUser(ctx, c.GetUint("id"), uint(sessionID))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		}
+		return
+	}
+
+	if err := store.Sessions.Revoke(ctx, session.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Сессия успешно завершена"})
+}
@@ -0,0 +1,73 @@
+package GoAPIManager
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// Сессии текущего пользователя: ID текущей и остальных
+func listSessions(s *testServer, token string) (current uint, others []uint) {
+	s.t.Helper()
+	out := s.expect(http.StatusOK, http.MethodGet, "/user/sessions", token, nil)
+	sessions, _ := out["Sessions"].([]interface{})
+	for _, raw := range sessions {
+		session := raw.(map[string]interface{})
+		id := uint(session["id"].(float64))
+		if session["current"] == true {
+			current = id
+		} else {
+			others = append(others, id)
+		}
+	}
+	return current, others
+}
+
+func TestLogoutRevokesToken(t *testing.T) {
+	s := newTestServer(t)
+	token := s.user("alice")
+	other := accessToken(t, s.loginTokens("alice"))
+
+	s.expect(http.StatusOK, http.MethodPost, "/logout", token, nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/sessions", token, nil)
+	// Остальные сессии пользователя продолжают работать
+	s.expect(http.StatusOK, http.MethodGet, "/user/sessions", other, nil)
+}
+
+func TestLogoutAllRevokesEverySession(t *testing.T) {
+	s := newTestServer(t)
+	first := s.user("alice")
+	second := accessToken(t, s.loginTokens("alice"))
+	bob := s.user("bobby")
+
+	out := s.expect(http.StatusOK, http.MethodPost, "/logout-all", first, nil)
+	// Сессии регистрации и двух входов
+	if revoked := out["Revoked"]; revoked != float64(3) {
+		t.Errorf("Revoked = %v, want 3", revoked)
+	}
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/sessions", first, nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/sessions", second, nil)
+	s.expect(http.StatusOK, http.MethodGet, "/user/sessions", bob, nil)
+}
+
+func TestDeleteSession(t *testing.T) {
+	s := newTestServer(t)
+	token := s.user("alice")
+	lost := accessToken(t, s.loginTokens("alice"))
+	bob := s.user("bobby")
+
+	lostID, _ := listSessions(s, lost)
+	path := fmt.Sprintf("/user/sessions/%d", lostID)
+
+	// Чужую сессию завершить нельзя
+	s.expect(http.StatusNotFound, http.MethodDelete, path, bob, nil)
+	s.expect(http.StatusOK, http.MethodGet, "/user/sessions", lost, nil)
+
+	s.expect(http.StatusOK, http.MethodDelete, path, token, nil)
+	s.expect(http.StatusUnauthorized, http.MethodGet, "/user/sessions", lost, nil)
+	current, others := listSessions(s, token)
+	if current == 0 || slices.Contains(others, lostID) {
+		t.Errorf("sessions after delete: current %d, others %v; want %d gone", current, others, lostID)
+	}
+}
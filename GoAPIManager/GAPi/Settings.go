@@ -0,0 +1,116 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Настройка сервиса, которую меняют администраторы через API (в отличие от Config,
+// который задаётся при запуске)
+type Setting struct {
+	Key       string `gorm:"primaryKey"`
+	Value     string `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+// Настройки безопасности
+type securitySettings struct {
+	// Администраторы без 2FA могут только настроить её (см. /user/2fa)
+	RequireAdmin2FA bool `json:"require_admin_2fa"`
+}
+
+// Ключи настроек в таблице settings
+const settingRequireAdmin2FA = "require_admin_2fa"
+
+// Чтение логической настройки; незаданная настройка равна false
+func getBoolSetting(ctx context.Context, key string) (bool, error) {
+	value, err := store.Settings.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(value)
+}
+
+func loadSecuritySettings(ctx context.Context) (securitySettings, error) {
+	var settings securitySettings
+	var err error
+	settings.RequireAdmin2FA, err = getBoolSetting(ctx, settingRequireAdmin2FA)
+	return settings, err
+}
+
+// @Summary Настройки безопасности
+// @Description Возвращает настройки безопасности сервиса (например, обязательная 2FA для администраторов)
+// @Tags Администрирование
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Настройки"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/settings [get]
+func getSettings(c *gin.Context) {
+	settings, err := loadSecuritySettings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Settings": settings})
+}
+
+// @Summary Изменение настроек безопасности
+// @Description Изменяет настройки безопасности. Включить обязательную 2FA для администраторов можно, только если она уже включена у самого администратора. Администраторы без 2FA после этого могут только настроить её.
+// @Tags Администрирование
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Param request body securitySettings true "Настройки"
+// @Success 200 {object} map[string]interface{} "Настройки изменены"
+// @Failure 400 {object} map[string]string "Некорректные входные данные"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Нужны права администратора"
+// @Failure 409 {object} map[string]string "У администратора не включена 2FA"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /admin/settings [put]
+func updateSettings(c *gin.Context) {
+	var req securitySettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	before, err := loadSecuritySettings(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if req.RequireAdmin2FA {
+		// Иначе администратор сразу потерял бы доступ к остальным маршрутам
+		user, err := store.Users.GetByID(ctx, c.GetUint("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		if user.TOTPEnabledAt == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Enable two-factor authentication for your account first"})
+			return
+		}
+	}
+
+	if err := store.Settings.Set(ctx, settingRequireAdmin2FA, strconv.FormatBool(req.RequireAdmin2FA)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "settings.update", "settings", 0, 0, before, req)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Настройки успешно сохранены", "Settings": req})
+}
@@ -0,0 +1,456 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Ошибки хранилища, не зависящие от конкретной базы данных
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrDuplicate = errors.New("record already exists")
+)
+
+// Фильтр задач проекта
+type TaskFilter struct {
+	Status   string
+	Category string // категория статуса в процессе проекта (todo, in_progress, done)
+	Priority string
+	Deadline time.Time // учитывается только дата; нулевое значение — без фильтра
+	Fields   []TaskFieldFilter
+	Sort     TaskSort
+}
+
+// Условие на значение пользовательского поля задачи
+type TaskFieldFilter struct {
+	FieldID uint
+	Op      string // "=", ">=" или "<="; для multi_select "=" — задача содержит вариант
+	Numeric bool   // значение хранится в number_value (number, user), иначе в text_value
+	Value   interface{}
+}
+
+// Порядок списка задач: по столбцу задачи или по значению пользовательского поля
+// (задачи без значения — в конце); при равенстве — по ID
+type TaskSort struct {
+	Column  string // id, title или deadline (по умолчанию id)
+	FieldID uint   // пользовательское поле (0 — сортировка по Column)
+	Numeric bool
+	Desc    bool
+}
+
+// Фильтр отчёта о затраченном времени
+type TimesheetFilter struct {
+	From, To string // дни работы YYYY-MM-DD включительно; пустое значение — без ограничения
+	UserID   uint   // 0 — все пользователи
+}
+
+// Фильтр и страница списка пользователей
+type UserFilter struct {
+	Search string // подстрока имени пользователя или email, без учёта регистра
+	Offset int
+	Limit  int
+}
+
+// Фильтр и страница журнала аудита
+type AuditFilter struct {
+	ProjectID uint   // 0 — все проекты
+	ActorID   uint   // 0 — все пользователи
+	Actor     string // имя пользователя, без учёта регистра
+	Action    string // точное действие (task.update) или префикс с точкой (task.)
+	From, To  time.Time
+	Offset    int
+	Limit     int
+}
+
+// Фильтр и страница ленты событий проекта
+type ActivityFilter struct {
+	Type   string // пустое значение — события всех типов
+	Before uint   // курсор: события с ID меньше Before (0 — с самого нового)
+	Limit  int
+}
+
+// Страница упоминаний пользователя
+type MentionFilter struct {
+	Before uint // курсор: упоминания с ID меньше Before (0 — с самого нового)
+	Limit  int
+}
+
+// Страница уведомлений пользователя
+type NotificationFilter struct {
+	Unread bool // только непрочитанные
+	Before uint // курсор: уведомления с ID меньше Before (0 — с самого нового)
+	Limit  int
+}
+
+// Проекты и задачи пользователя, которые нужно передать другому перед удалением
+type UserOwnership struct {
+	Projects int64 `json:"projects"`
+	Tasks    int64 `json:"tasks"`
+}
+
+// Хранилище пользователей
+type UserRepository interface {
+	Create(ctx context.Context, user *User) error
+	GetByID(ctx context.Context, id uint) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	Update(ctx context.Context, user *User) error
+	// UseTOTPStep запоминает шаг TOTP, по которому прошёл вход; false — этот или более поздний
+	// шаг уже использован (защита от повторного использования кода)
+	UseTOTPStep(ctx context.Context, id uint, step int64) (bool, error)
+	// List возвращает страницу пользователей и общее количество подходящих под фильтр
+	List(ctx context.Context, filter UserFilter) ([]User, int64, error)
+	// LockActiveAdmins возвращает ID незаблокированных администраторов. В транзакции их строки
+	// блокируются до её завершения, поэтому параллельная смена ролей дождётся результата
+	LockActiveAdmins(ctx context.Context) ([]uint, error)
+	// Ownership считает проекты, которыми владеет пользователь, и задачи, где он исполнитель
+	Ownership(ctx context.Context, id uint) (UserOwnership, error)
+	// Delete удаляет пользователя; если reassignTo не 0, его проекты и задачи сначала
+	// передаются пользователю reassignTo
+	Delete(ctx context.Context, id, reassignTo uint) error
+}
+
+// Хранилище проектов и их участников
+type ProjectRepository interface {
+	// Create создаёт проект и делает ownerID его владельцем
+	Create(ctx context.Context, project *Project, ownerID uint) error
+	GetByID(ctx context.Context, id uint) (*Project, error)
+	// LockForUpdate блокирует строку проекта до конца транзакции
+	LockForUpdate(ctx context.Context, id uint) error
+	// ListForMember возвращает проекты, в которых пользователь состоит участником
+	ListForMember(ctx context.Context, userID uint) ([]Project, error)
+	Update(ctx context.Context, project *Project) error
+	Delete(ctx context.Context, id uint) error
+	SetFilePath(ctx context.Context, id uint, path string) error
+	GetFilePath(ctx context.Context, id uint) (string, error)
+
+	GetMember(ctx context.Context, projectID, userID uint) (*ProjectMember, error)
+	ListMembers(ctx context.Context, projectID uint) ([]projectMemberView, error)
+	// MemberRoles возвращает роли указанных пользователей в проекте (не участники в карту не попадают)
+	MemberRoles(ctx context.Context, projectID uint, userIDs []uint) (map[uint]string, error)
+	AddMember(ctx context.Context, member *ProjectMember) error
+	// UpdateMemberRole и RemoveMember возвращают errLastOwner, если проект остался бы без владельца
+	UpdateMemberRole(ctx context.Context, member *ProjectMember, role string) error
+	RemoveMember(ctx context.Context, member *ProjectMember) error
+}
+
+// Хранилище задач и их исполнителей
+type TaskRepository interface {
+	// Create создаёт задачу вместе со списком исполнителей
+	Create(ctx context.Context, task *Task, assigneeIDs []uint) error
+	GetByID(ctx context.Context, id uint) (*Task, error)
+	// GetInProject возвращает задачу, только если она принадлежит проекту
+	GetInProject(ctx context.Context, projectID, taskID uint) (*Task, error)
+	List(ctx context.Context, projectID uint, filter TaskFilter) ([]Task, error)
+	ListAssignedTo(ctx context.Context, userID uint) ([]Task, error)
+	// Update сохраняет задачу; если assigneeIDs не nil, список исполнителей заменяется
+	Update(ctx context.Context, task *Task, assigneeIDs []uint) error
+	Delete(ctx context.Context, id uint) error
+	// LoadAssignees заполняет AssigneeIDs у переданных задач
+	LoadAssignees(ctx context.Context, tasks []Task) error
+	// ListOpenDue возвращает незавершённые (не в статусе категории done) задачи всех проектов с дедлайном в промежутке
+	// [from, to] (нулевое from — без нижней границы)
+	ListOpenDue(ctx context.Context, from, to time.Time) ([]Task, error)
+	// Progress возвращает количество выполненных и всех подзадач и пунктов чек-листа
+	// у задач taskIDs (Percent не заполняется); задачи без подзадач и чек-листа в карту не попадают
+	Progress(ctx context.Context, taskIDs []uint) (map[uint]TaskProgress, error)
+}
+
+// Хранилище сессий пользователей
+type SessionRepository interface {
+	Create(ctx context.Context, session *Session) error
+	GetByID(ctx context.Context, id uint) (*Session, error)
+	GetByJTI(ctx context.Context, jti string) (*Session, error)
+	// GetForUser возвращает сессию, только если она принадлежит пользователю
+	GetForUser(ctx context.Context, userID, id uint) (*Session, error)
+	// ListActive возвращает неотозванные и неистёкшие сессии пользователя
+	ListActive(ctx context.Context, userID uint) ([]Session, error)
+	// Touch обновляет время последнего использования и срок действия сессии
+	Touch(ctx context.Context, id uint, lastUsedAt, expiresAt time.Time) error
+	Revoke(ctx context.Context, id uint) error
+	// RevokeAll отзывает все активные сессии пользователя и возвращает их количество
+	RevokeAll(ctx context.Context, userID uint) (int64, error)
+}
+
+// Хранилище refresh-токенов (только хеши)
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	// MarkUsed помечает токен использованным; false — токен уже был использован раньше
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+}
+
+// Хранилище персональных токенов доступа (только хеши)
+type PersonalTokenRepository interface {
+	Create(ctx context.Context, token *PersonalToken) error
+	GetByHash(ctx context.Context, hash string) (*PersonalToken, error)
+	// GetForUser возвращает неотозванный токен, только если он принадлежит пользователю
+	GetForUser(ctx context.Context, userID, id uint) (*PersonalToken, error)
+	// ListForUser возвращает неотозванные токены пользователя (в том числе истёкшие)
+	ListForUser(ctx context.Context, userID uint) ([]PersonalToken, error)
+	Touch(ctx context.Context, id uint, lastUsedAt time.Time) error
+	Revoke(ctx context.Context, id uint) error
+	// RevokeAll отзывает все токены пользователя и возвращает их количество
+	RevokeAll(ctx context.Context, userID uint) (int64, error)
+}
+
+// Хранилище одноразовых токенов из писем (только хеши)
+type EmailTokenRepository interface {
+	Create(ctx context.Context, token *EmailToken) error
+	GetByHash(ctx context.Context, hash string) (*EmailToken, error)
+	// MarkUsed помечает токен использованным; false — токен уже был использован раньше
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+	// Invalidate помечает использованными все неиспользованные токены пользователя с этим назначением
+	Invalidate(ctx context.Context, userID uint, purpose string, at time.Time) error
+}
+
+// Хранилище кодов восстановления 2FA (только хеши)
+type RecoveryCodeRepository interface {
+	// Replace заменяет все коды пользователя новыми
+	Replace(ctx context.Context, userID uint, hashes []string) error
+	// Use помечает код использованным; false — кода нет или он уже использован
+	Use(ctx context.Context, userID uint, hash string, at time.Time) (bool, error)
+	CountUnused(ctx context.Context, userID uint) (int64, error)
+	DeleteForUser(ctx context.Context, userID uint) error
+}
+
+// Хранилище незавершённых входов с двухфакторной аутентификацией
+type LoginChallengeRepository interface {
+	Create(ctx context.Context, challenge *LoginChallenge) error
+	GetByHash(ctx context.Context, hash string) (*LoginChallenge, error)
+	// TakeAttempt учитывает попытку ввода кода, если их сделано меньше max; false — попытки исчерпаны
+	TakeAttempt(ctx context.Context, id uint, max int) (bool, error)
+	// MarkUsed помечает вход завершённым; false — он уже был завершён раньше
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+}
+
+// Хранилище настроек сервиса (ключ — значение)
+type SettingRepository interface {
+	// Get возвращает ErrNotFound, если настройка не задана
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string) error
+}
+
+// Хранилище счётчиков неудачных попыток входа
+type LoginFailureRepository interface {
+	// Get возвращает ErrNotFound, если неудачных попыток не было
+	Get(ctx context.Context, key string) (*LoginFailure, error)
+	// RecordFailure учитывает неудачную попытку в момент at и возвращает обновлённый счётчик.
+	// Если предыдущая попытка (или конец блокировки) была раньше windowStart, счёт начинается заново.
+	RecordFailure(ctx context.Context, key string, at, windowStart time.Time) (*LoginFailure, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset удаляет счётчик и блокировку
+	Reset(ctx context.Context, key string) error
+}
+
+// Хранилище учётных записей внешнего провайдера входа (OpenID Connect)
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *UserIdentity) error
+	// Get возвращает ErrNotFound, если учётная запись провайдера ещё не привязана к пользователю
+	Get(ctx context.Context, issuer, subject string) (*UserIdentity, error)
+	// RecordLogin запоминает время входа и email, который сообщил провайдер
+	RecordLogin(ctx context.Context, id uint, email *string, at time.Time) error
+}
+
+// Хранилище начатых входов через OpenID Connect
+type OIDCLoginRepository interface {
+	Create(ctx context.Context, login *OIDCLogin) error
+	GetByStateHash(ctx context.Context, hash string) (*OIDCLogin, error)
+	// MarkUsed помечает вход завершённым; false — он уже был завершён раньше
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+}
+
+// Журнал аудита изменений. Записи только добавляются: изменить или удалить их нельзя.
+type AuditRepository interface {
+	Create(ctx context.Context, event *AuditEvent) error
+	// List возвращает страницу событий (новые первыми) и общее количество подходящих под фильтр
+	List(ctx context.Context, filter AuditFilter) ([]AuditEvent, int64, error)
+}
+
+// Хранилище истории изменений задач
+type TaskHistoryRepository interface {
+	// Create сохраняет изменения полей задачи (одна запись на поле)
+	Create(ctx context.Context, entries []TaskHistory) error
+	// ListForTask возвращает историю задачи, новые изменения первыми
+	ListForTask(ctx context.Context, taskID uint) ([]TaskHistory, error)
+}
+
+// Хранилище ленты событий проектов
+type ActivityRepository interface {
+	Create(ctx context.Context, activity *Activity) error
+	// List возвращает события проекта, новые первыми
+	List(ctx context.Context, projectID uint, filter ActivityFilter) ([]Activity, error)
+}
+
+// Хранилище комментариев к задачам и упоминаний в них
+type CommentRepository interface {
+	// Create сохраняет комментарий вместе с упоминаниями пользователей mentionIDs
+	Create(ctx context.Context, comment *Comment, mentionIDs []uint) error
+	// GetInProject возвращает неудалённый комментарий, только если его задача принадлежит проекту
+	GetInProject(ctx context.Context, projectID, id uint) (*Comment, error)
+	// ListForTask возвращает неудалённые комментарии задачи (старые первыми) с упоминаниями
+	ListForTask(ctx context.Context, taskID uint) ([]commentView, error)
+	// Update сохраняет текст и время правки; упоминания заменяются на mentionIDs
+	Update(ctx context.Context, comment *Comment, mentionIDs []uint) error
+	// Delete помечает комментарий удалённым
+	Delete(ctx context.Context, id uint, at time.Time) error
+	// ListMentions возвращает упоминания пользователя в неудалённых комментариях проектов,
+	// в которых он состоит, новые первыми
+	ListMentions(ctx context.Context, userID uint, filter MentionFilter) ([]mentionView, error)
+}
+
+// Хранилище пунктов чек-листов задач
+type ChecklistRepository interface {
+	Create(ctx context.Context, item *ChecklistItem) error
+	// GetInProject возвращает пункт, только если его задача принадлежит проекту
+	GetInProject(ctx context.Context, projectID, id uint) (*ChecklistItem, error)
+	// ListForTask возвращает пункты чек-листа задачи в порядке добавления
+	ListForTask(ctx context.Context, taskID uint) ([]ChecklistItem, error)
+	Update(ctx context.Context, item *ChecklistItem) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// Хранилище связей между задачами
+type TaskLinkRepository interface {
+	Create(ctx context.Context, link *TaskLink) error
+	// GetInProject возвращает связь, только если её задачи принадлежат проекту
+	GetInProject(ctx context.Context, projectID, id uint) (*TaskLink, error)
+	// ListForTask возвращает связи задачи в обе стороны вместе со связанными задачами
+	ListForTask(ctx context.Context, taskID uint) ([]taskLinkView, error)
+	// ListForProject возвращает связи задач проекта указанного типа
+	ListForProject(ctx context.Context, projectID uint, linkType string) ([]TaskLink, error)
+	// OpenBlockers возвращает незавершённые задачи, которые блокируют задачу
+	OpenBlockers(ctx context.Context, taskID uint) ([]Task, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// Хранилище записей о затраченном на задачи времени
+type WorklogRepository interface {
+	Create(ctx context.Context, worklog *Worklog) error
+	// GetInProject возвращает запись, только если её задача принадлежит проекту
+	GetInProject(ctx context.Context, projectID, id uint) (*Worklog, error)
+	// ListForTask возвращает записи задачи по дням работы (старые первыми) с именами пользователей
+	ListForTask(ctx context.Context, taskID uint) ([]worklogView, error)
+	Delete(ctx context.Context, id uint) error
+	// Timesheet возвращает затраченное в проекте время по пользователям и задачам
+	Timesheet(ctx context.Context, projectID uint, filter TimesheetFilter) ([]timesheetRow, error)
+}
+
+// Хранилище запущенных таймеров
+type TimerRepository interface {
+	// Start запускает таймер; если у пользователя уже есть таймер, возвращает ErrDuplicate
+	Start(ctx context.Context, timer *Timer) error
+	// GetForUser возвращает запущенный таймер пользователя
+	GetForUser(ctx context.Context, userID uint) (*Timer, error)
+	// Stop удаляет таймер; если таймер уже остановлен, возвращает ErrNotFound
+	Stop(ctx context.Context, id uint) error
+}
+
+// Хранилище серий повторяющихся задач
+type RecurrenceRepository interface {
+	// Create создаёт серию и делает её текущую задачу первой задачей серии;
+	// если задача уже входит в серию, возвращает ErrDuplicate
+	Create(ctx context.Context, recurrence *TaskRecurrence) error
+	GetByID(ctx context.Context, id uint) (*TaskRecurrence, error)
+	// GetInProject возвращает серию, только если она принадлежит проекту
+	GetInProject(ctx context.Context, projectID, id uint) (*TaskRecurrence, error)
+	ListForProject(ctx context.Context, projectID uint) ([]TaskRecurrence, error)
+	// ListDue возвращает активные серии, у которых текущая задача завершена или наступил её срок
+	ListDue(ctx context.Context, now time.Time) ([]TaskRecurrence, error)
+	// ListTasks возвращает задачи серии (старые первыми)
+	ListTasks(ctx context.Context, id uint) ([]Task, error)
+	UpdateRule(ctx context.Context, id uint, rule string) error
+	// Advance делает task текущей задачей серии со сроком at. Серия меняется, только если она
+	// активна и с момента чтения recurrence не создавала задач, иначе возвращается ErrNotFound.
+	Advance(ctx context.Context, recurrence *TaskRecurrence, task *Task, at time.Time) error
+	// End завершает активную серию со статусом status; если серия уже не активна, возвращает ErrNotFound
+	End(ctx context.Context, id uint, status string, at time.Time) error
+}
+
+// Хранилище рабочих процессов проектов
+type WorkflowRepository interface {
+	// Get возвращает статусы проекта по порядку и переходы между ними
+	Get(ctx context.Context, projectID uint) (*Workflow, error)
+	// CountTasks возвращает количество задач проекта в каждом статусе
+	CountTasks(ctx context.Context, projectID uint) (map[string]int64, error)
+	// Save заменяет процесс проекта. Статусы с ID сохраняются (с новыми именем, категорией
+	// и порядком), без ID — создаются, остальные статусы проекта удаляются; переходы задаются
+	// именами статусов. moves переводит задачи из статуса с ID ключа в статус с именем значения.
+	Save(ctx context.Context, workflow *Workflow, moves map[uint]string) error
+}
+
+// Хранилище пользовательских полей задач и их значений
+type CustomFieldRepository interface {
+	Create(ctx context.Context, field *CustomField) error
+	// GetInProject возвращает поле, только если оно принадлежит проекту
+	GetInProject(ctx context.Context, projectID, id uint) (*CustomField, error)
+	List(ctx context.Context, projectID uint) ([]CustomField, error)
+	// Update сохраняет поле и удаляет у задач значения вариантов removedOptions
+	Update(ctx context.Context, field *CustomField, removedOptions []string) error
+	// Delete удаляет поле вместе с его значениями
+	Delete(ctx context.Context, id uint) error
+	// ListValues возвращает значения полей задач по порядку полей
+	ListValues(ctx context.Context, taskIDs []uint) ([]taskFieldValueView, error)
+	// SetValues заменяет значения полей fieldIDs задачи строками rows
+	SetValues(ctx context.Context, taskID uint, fieldIDs []uint, rows []TaskFieldValue) error
+	// CopyValues копирует все значения полей задачи fromTaskID в задачу toTaskID
+	CopyValues(ctx context.Context, fromTaskID, toTaskID uint) error
+}
+
+// Хранилище уведомлений и настроек их доставки
+type NotificationRepository interface {
+	// Create сохраняет уведомление; false — у пользователя уже есть уведомление с тем же DedupKey
+	Create(ctx context.Context, notification *Notification) (bool, error)
+	// GetForUser возвращает уведомление, только если оно принадлежит пользователю
+	GetForUser(ctx context.Context, userID, id uint) (*Notification, error)
+	List(ctx context.Context, userID uint, filter NotificationFilter) ([]Notification, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	// SetRead отмечает уведомление прочитанным в readAt (nil — непрочитанным)
+	SetRead(ctx context.Context, id uint, readAt *time.Time) error
+	// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает их количество
+	MarkAllRead(ctx context.Context, userID uint, at time.Time) (int64, error)
+	// GetSettings возвращает настройки пользователя (без сохранённых настроек — значения по умолчанию)
+	GetSettings(ctx context.Context, userID uint) (*NotificationSettings, error)
+	SaveSettings(ctx context.Context, settings *NotificationSettings) error
+}
+
+// Store объединяет все хранилища сервиса
+type Store struct {
+	Users          UserRepository
+	Projects       ProjectRepository
+	Tasks          TaskRepository
+	Sessions       SessionRepository
+	RefreshTokens  RefreshTokenRepository
+	PersonalTokens PersonalTokenRepository
+	EmailTokens    EmailTokenRepository
+	RecoveryCodes  RecoveryCodeRepository
+	Challenges     LoginChallengeRepository
+	Settings       SettingRepository
+	LoginFailures  LoginFailureRepository
+	Identities     UserIdentityRepository
+	OIDCLogins     OIDCLoginRepository
+	Audit          AuditRepository
+	TaskHistory    TaskHistoryRepository
+	Activity       ActivityRepository
+	Comments       CommentRepository
+	Checklists     ChecklistRepository
+	TaskLinks      TaskLinkRepository
+	Worklogs       WorklogRepository
+	Timers         TimerRepository
+	Recurrences    RecurrenceRepository
+	Notifications  NotificationRepository
+	Workflows      WorkflowRepository
+	CustomFields   CustomFieldRepository
+
+	// Выполнение нескольких операций в одной транзакции
+	transaction func(ctx context.Context, fn func(tx *Store) error) error
+}
+
+// Transaction выполняет fn в транзакции: при ошибке все изменения откатываются
+func (s *Store) Transaction(ctx context.Context, fn func(tx *Store) error) error {
+	return s.transaction(ctx, fn)
+}
+
+// Текущее хранилище сервиса
+var store *Store
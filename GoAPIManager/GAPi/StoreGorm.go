@@ -0,0 +1,1757 @@
+package GoAPIManager
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Особенности SQL конкретной базы данных. Репозитории ниже общие для PostgreSQL и SQLite,
+// всё, что различается между базами, описывается диалектом.
+type sqlDialect interface {
+	// Name — имя набора миграций (каталог migrations/<name>)
+	Name() string
+	// DateEquals — условие "дата в колонке равна ?" (параметр в формате YYYY-MM-DD)
+	DateEquals(column string) string
+	// MigrationsTableSQL — создание таблицы учёта миграций
+	MigrationsTableSQL() string
+}
+
+// Приведение ошибок GORM к ошибкам хранилища
+func storeError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	}
+	return err
+}
+
+// Хранилище поверх GORM с указанным диалектом
+func newGormStore(conn *gorm.DB, dialect sqlDialect) *Store {
+	return &Store{
+		Users:          &gormUserRepository{db: conn},
+		Projects:       &gormProjectRepository{db: conn},
+		Tasks:          &gormTaskRepository{db: conn, dialect: dialect},
+		Sessions:       &gormSessionRepository{db: conn},
+		RefreshTokens:  &gormRefreshTokenRepository{db: conn},
+		PersonalTokens: &gormPersonalTokenRepository{db: conn},
+		EmailTokens:    &gormEmailTokenRepository{db: conn},
+		RecoveryCodes:  &gormRecoveryCodeRepository{db: conn},
+		Challenges:     &gormLoginChallengeRepository{db: conn},
+		Settings:       &gormSettingRepository{db: conn},
+		LoginFailures:  &gormLoginFailureRepository{db: conn},
+		Identities:     &gormUserIdentityRepository{db: conn},
+		OIDCLogins:     &gormOIDCLoginRepository{db: conn},
+		Audit:          &gormAuditRepository{db: conn},
+		TaskHistory:    &gormTaskHistoryRepository{db: conn},
+		Activity:       &gormActivityRepository{db: conn},
+		Comments:       &gormCommentRepository{db: conn},
+		Checklists:     &gormChecklistRepository{db: conn},
+		TaskLinks:      &gormTaskLinkRepository{db: conn},
+		Worklogs:       &gormWorklogRepository{db: conn},
+		Timers:         &gormTimerRepository{db: conn},
+		Recurrences:    &gormRecurrenceRepository{db: conn},
+		Notifications:  &gormNotificationRepository{db: conn},
+		Workflows:      &gormWorkflowRepository{db: conn},
+		CustomFields:   &gormCustomFieldRepository{db: conn},
+		transaction: func(ctx context.Context, fn func(tx *Store) error) error {
+			return conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				return fn(newGormStore(tx, dialect))
+			})
+		},
+	}
+}
+
+// Пользователи
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormUserRepository) Create(ctx context.Context, user *User) error {
+	return storeError(r.db.WithContext(ctx).Create(user).Error)
+}
+
+func (r *gormUserRepository) GetByID(ctx context.Context, id uint) (*User, error) {
+	var user User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) GetByUsername(ctx context.Context, username string) (*User, error) {
+	var user User
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
+	var user User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) Update(ctx context.Context, user *User) error {
+	return storeError(r.db.WithContext(ctx).Save(user).Error)
+}
+
+func (r *gormUserRepository) UseTOTPStep(ctx context.Context, id uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, storeError(result.Error)
+}
+
+func (r *gormUserRepository) List(ctx context.Context, filter UserFilter) ([]User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&User{})
+	if filter.Search != "" {
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR email LIKE ?", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, storeError(err)
+	}
+
+	var users []User
+	err := query.Order("id").Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error
+	return users, total, storeError(err)
+}
+
+func (r *gormUserRepository) LockActiveAdmins(ctx context.Context) ([]uint, error) {
+	// SQLite не поддерживает FOR UPDATE, но там транзакции и так выполняются по одной
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND disabled_at IS NULL", RoleAdmin).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, storeError(err)
+}
+
+func (r *gormUserRepository) Ownership(ctx context.Context, id uint) (UserOwnership, error) {
+	var o UserOwnership
+	db := r.db.WithContext(ctx)
+	err := db.Model(&Project{}).
+		Where("assignee_id = ? OR id IN (?)", id,
+			db.Model(&ProjectMember{}).Select("project_id").Where("user_id = ? AND role = ?", id, ProjectRoleOwner)).
+		Count(&o.Projects).Error
+	if err != nil {
+		return o, storeError(err)
+	}
+	err = db.Model(&Task{}).
+		Where("assignee_id = ? OR id IN (?)", id,
+			db.Model(&TaskAssignee{}).Select("task_id").Where("user_id = ?", id)).
+		Count(&o.Tasks).Error
+	return o, storeError(err)
+}
+
+func (r *gormUserRepository) Delete(ctx context.Context, id, reassignTo uint) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if reassignTo != 0 {
+			if err := reassignUserOwnership(tx, id, reassignTo); err != nil {
+				return err
+			}
+		}
+		// Участие в проектах, исполнители задач, сессии и токены удаляются каскадно
+		return tx.Delete(&User{}, id).Error
+	}))
+}
+
+// Передача проектов и задач пользователя from пользователю to: to становится владельцем
+// проектов from и исполнителем его задач (и, если нужно, участником их проектов)
+func reassignUserOwnership(tx *gorm.DB, from, to uint) error {
+	var owned []ProjectMember
+	if err := tx.Where("user_id = ? AND role = ?", from, ProjectRoleOwner).Find(&owned).Error; err != nil {
+		return err
+	}
+	for _, m := range owned {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "project_id"}, {Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"role": ProjectRoleOwner}),
+		}).Create(&ProjectMember{ProjectID: m.ProjectID, UserID: to, Role: ProjectRoleOwner}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Model(&Project{}).Where("assignee_id = ?", from).Update("assignee_id", to).Error; err != nil {
+		return err
+	}
+
+	// Задачи, где from — основной исполнитель или один из исполнителей
+	var tasks []Task
+	err := tx.Where("assignee_id = ? OR id IN (?)", from,
+		tx.Model(&TaskAssignee{}).Select("task_id").Where("user_id = ?", from)).
+		Find(&tasks).Error
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		// Исполнитель задачи должен быть участником проекта не ниже member
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "project_id"}, {Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"role": ProjectRoleMember}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: "project_members", Name: "role"}, Value: ProjectRoleViewer}}},
+		}).Create(&ProjectMember{ProjectID: task.ProjectID, UserID: to, Role: ProjectRoleMember}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&TaskAssignee{TaskID: task.ID, UserID: to}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Model(&Task{}).Where("assignee_id = ?", from).Update("assignee_id", to).Error
+}
+
+// Проекты и участники
+
+type gormProjectRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormProjectRepository) Create(ctx context.Context, project *Project, ownerID uint) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(project).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&ProjectMember{ProjectID: project.ID, UserID: ownerID, Role: ProjectRoleOwner}).Error; err != nil {
+			return err
+		}
+		return saveWorkflow(tx, defaultWorkflow(project.ID), nil)
+	}))
+}
+
+func (r *gormProjectRepository) GetByID(ctx context.Context, id uint) (*Project, error) {
+	var project Project
+	if err := r.db.WithContext(ctx).First(&project, id).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &project, nil
+}
+
+func (r *gormProjectRepository) LockForUpdate(ctx context.Context, id uint) error {
+	// SQLite не поддерживает FOR UPDATE, но там транзакции и так выполняются по одной
+	var project Project
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&project, id).Error
+	return storeError(err)
+}
+
+func (r *gormProjectRepository) ListForMember(ctx context.Context, userID uint) ([]Project, error) {
+	var projects []Project
+	err := r.db.WithContext(ctx).
+		Joins("JOIN project_members ON project_members.project_id = projects.id").
+		Where("project_members.user_id = ?", userID).
+		Order("projects.id").
+		Find(&projects).Error
+	return projects, storeError(err)
+}
+
+func (r *gormProjectRepository) Update(ctx context.Context, project *Project) error {
+	return storeError(r.db.WithContext(ctx).Save(project).Error)
+}
+
+func (r *gormProjectRepository) Delete(ctx context.Context, id uint) error {
+	return storeError(r.db.WithContext(ctx).Delete(&Project{}, id).Error)
+}
+
+func (r *gormProjectRepository) SetFilePath(ctx context.Context, id uint, path string) error {
+	return storeError(r.db.WithContext(ctx).Model(&Project{}).Where("id = ?", id).Update("file_path", path).Error)
+}
+
+func (r *gormProjectRepository) GetFilePath(ctx context.Context, id uint) (string, error) {
+	var filePath *string
+	err := r.db.WithContext(ctx).Model(&Project{}).Select("file_path").Where("id = ?", id).Scan(&filePath).Error
+	if err != nil || filePath == nil {
+		return "", storeError(err)
+	}
+	return *filePath, nil
+}
+
+func (r *gormProjectRepository) GetMember(ctx context.Context, projectID, userID uint) (*ProjectMember, error) {
+	var member ProjectMember
+	if err := r.db.WithContext(ctx).Where("project_id = ? AND user_id = ?", projectID, userID).First(&member).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &member, nil
+}
+
+func (r *gormProjectRepository) ListMembers(ctx context.Context, projectID uint) ([]projectMemberView, error) {
+	var members []projectMemberView
+	err := r.db.WithContext(ctx).Table("project_members").
+		Select("project_members.user_id, users.username, project_members.role, project_members.created_at").
+		Joins("JOIN users ON users.id = project_members.user_id").
+		Where("project_members.project_id = ?", projectID).
+		Order("project_members.created_at").
+		Scan(&members).Error
+	return members, storeError(err)
+}
+
+func (r *gormProjectRepository) MemberRoles(ctx context.Context, projectID uint, userIDs []uint) (map[uint]string, error) {
+	var members []ProjectMember
+	if err := r.db.WithContext(ctx).Where("project_id = ? AND user_id IN ?", projectID, userIDs).Find(&members).Error; err != nil {
+		return nil, storeError(err)
+	}
+
+	roles := make(map[uint]string, len(members))
+	for _, m := range members {
+		roles[m.UserID] = m.Role
+	}
+	return roles, nil
+}
+
+func (r *gormProjectRepository) AddMember(ctx context.Context, member *ProjectMember) error {
+	return storeError(r.db.WithContext(ctx).Create(member).Error)
+}
+
+// Количество владельцев проекта (последнего владельца нельзя понизить или удалить)
+func countProjectOwners(tx *gorm.DB, projectID uint) (int64, error) {
+	var count int64
+	err := tx.Model(&ProjectMember{}).Where("project_id = ? AND role = ?", projectID, ProjectRoleOwner).Count(&count).Error
+	return count, err
+}
+
+func (r *gormProjectRepository) UpdateMemberRole(ctx context.Context, member *ProjectMember, role string) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if member.Role == ProjectRoleOwner && role != ProjectRoleOwner {
+			owners, err := countProjectOwners(tx, member.ProjectID)
+			if err != nil {
+				return err
+			}
+			if owners <= 1 {
+				return errLastOwner
+			}
+		}
+		if err := tx.Model(member).Update("role", role).Error; err != nil {
+			return err
+		}
+		member.Role = role
+		return nil
+	}))
+}
+
+func (r *gormProjectRepository) RemoveMember(ctx context.Context, member *ProjectMember) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if member.Role == ProjectRoleOwner {
+			owners, err := countProjectOwners(tx, member.ProjectID)
+			if err != nil {
+				return err
+			}
+			if owners <= 1 {
+				return errLastOwner
+			}
+		}
+		return tx.Delete(member).Error
+	}))
+}
+
+// Задачи и исполнители
+
+type gormTaskRepository struct {
+	db      *gorm.DB
+	dialect sqlDialect
+}
+
+// Замена списка исполнителей задачи. Первый исполнитель становится основным (assignee_id).
+func replaceTaskAssignees(tx *gorm.DB, task *Task, userIDs []uint) error {
+	if err := tx.Where("task_id = ?", task.ID).Delete(&TaskAssignee{}).Error; err != nil {
+		return err
+	}
+
+	for _, id := range userIDs {
+		if err := tx.Create(&TaskAssignee{TaskID: task.ID, UserID: id}).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(userIDs) > 0 && task.AssigneeID != userIDs[0] {
+		task.AssigneeID = userIDs[0]
+		if err := tx.Model(task).Update("assignee_id", task.AssigneeID).Error; err != nil {
+			return err
+		}
+	}
+	task.AssigneeIDs = userIDs
+	return nil
+}
+
+func (r *gormTaskRepository) Create(ctx context.Context, task *Task, assigneeIDs []uint) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		return replaceTaskAssignees(tx, task, assigneeIDs)
+	}))
+}
+
+func (r *gormTaskRepository) GetByID(ctx context.Context, id uint) (*Task, error) {
+	var task Task
+	if err := r.db.WithContext(ctx).First(&task, id).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &task, nil
+}
+
+func (r *gormTaskRepository) GetInProject(ctx context.Context, projectID, taskID uint) (*Task, error) {
+	var task Task
+	if err := r.db.WithContext(ctx).Where("id = ? AND project_id = ?", taskID, projectID).First(&task).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &task, nil
+}
+
+func (r *gormTaskRepository) List(ctx context.Context, projectID uint, filter TaskFilter) ([]Task, error) {
+	query := r.db.WithContext(ctx).Where("project_id = ?", projectID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		query = query.Where(taskStatusCategorySQL, filter.Category)
+	}
+	if !filter.Deadline.IsZero() {
+		query = query.Where(r.dialect.DateEquals("deadline"), filter.Deadline.Format("2006-01-02"))
+	}
+	if filter.Priority != "" {
+		query = query.Where("priority = ?", filter.Priority)
+	}
+	for _, f := range filter.Fields {
+		query = query.Where("EXISTS (SELECT 1 FROM task_field_values WHERE task_field_values.task_id = tasks.id"+
+			" AND task_field_values.field_id = ? AND task_field_values."+fieldValueColumn(f.Numeric)+" "+f.Op+" ?)", f.FieldID, f.Value)
+	}
+
+	var tasks []Task
+	if err := query.Order(taskOrder(filter.Sort)).Find(&tasks).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return tasks, nil
+}
+
+func (r *gormTaskRepository) ListAssignedTo(ctx context.Context, userID uint) ([]Task, error) {
+	var tasks []Task
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&TaskAssignee{}).Select("task_id").Where("user_id = ?", userID)).
+		Order("deadline").
+		Find(&tasks).Error
+	return tasks, storeError(err)
+}
+
+func (r *gormTaskRepository) Update(ctx context.Context, task *Task, assigneeIDs []uint) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(task).Error; err != nil {
+			return err
+		}
+		if assigneeIDs != nil {
+			return replaceTaskAssignees(tx, task, assigneeIDs)
+		}
+		return nil
+	}))
+}
+
+func (r *gormTaskRepository) ListOpenDue(ctx context.Context, from, to time.Time) ([]Task, error) {
+	query := r.db.WithContext(ctx).Where("NOT "+taskStatusCategorySQL, StatusCategoryDone).Where("deadline <= ?", to)
+	if !from.IsZero() {
+		query = query.Where("deadline >= ?", from)
+	}
+
+	var tasks []Task
+	if err := query.Order("id").Find(&tasks).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return tasks, nil
+}
+
+func (r *gormTaskRepository) Delete(ctx context.Context, id uint) error {
+	return storeError(r.db.WithContext(ctx).Delete(&Task{}, id).Error)
+}
+
+func (r *gormTaskRepository) LoadAssignees(ctx context.Context, tasks []Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+
+	var rows []TaskAssignee
+	if err := r.db.WithContext(ctx).Where("task_id IN ?", ids).Order("id").Find(&rows).Error; err != nil {
+		return storeError(err)
+	}
+
+	byTask := make(map[uint][]uint)
+	for _, row := range rows {
+		byTask[row.TaskID] = append(byTask[row.TaskID], row.UserID)
+	}
+	for i := range tasks {
+		tasks[i].AssigneeIDs = byTask[tasks[i].ID]
+		if tasks[i].AssigneeIDs == nil {
+			tasks[i].AssigneeIDs = []uint{}
+		}
+	}
+	return nil
+}
+
+func (r *gormTaskRepository) Progress(ctx context.Context, taskIDs []uint) (map[uint]TaskProgress, error) {
+	progress := make(map[uint]TaskProgress)
+	if len(taskIDs) == 0 {
+		return progress, nil
+	}
+
+	type count struct {
+		TaskID uint
+		Done   int
+		Total  int
+	}
+	db := r.db.WithContext(ctx)
+
+	var subtasks []count
+	err := db.Model(&Task{}).
+		Select("parent_task_id AS task_id, SUM(CASE WHEN "+taskStatusCategorySQL+" THEN 1 ELSE 0 END) AS done, COUNT(*) AS total", StatusCategoryDone).
+		Where("parent_task_id IN ?", taskIDs).
+		Group("parent_task_id").
+		Scan(&subtasks).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+	for _, row := range subtasks {
+		p := progress[row.TaskID]
+		p.Subtasks = progressCount{Done: row.Done, Total: row.Total}
+		progress[row.TaskID] = p
+	}
+
+	var items []count
+	err = db.Model(&ChecklistItem{}).
+		Select("task_id, SUM(CASE WHEN done THEN 1 ELSE 0 END) AS done, COUNT(*) AS total").
+		Where("task_id IN ?", taskIDs).
+		Group("task_id").
+		Scan(&items).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+	for _, row := range items {
+		p := progress[row.TaskID]
+		p.Checklist = progressCount{Done: row.Done, Total: row.Total}
+		progress[row.TaskID] = p
+	}
+	return progress, nil
+}
+
+// Сессии
+
+type gormSessionRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormSessionRepository) Create(ctx context.Context, session *Session) error {
+	return storeError(r.db.WithContext(ctx).Create(session).Error)
+}
+
+func (r *gormSessionRepository) GetByID(ctx context.Context, id uint) (*Session, error) {
+	var session Session
+	if err := r.db.WithContext(ctx).First(&session, id).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &session, nil
+}
+
+func (r *gormSessionRepository) GetByJTI(ctx context.Context, jti string) (*Session, error) {
+	var session Session
+	if err := r.db.WithContext(ctx).Where("jti = ?", jti).First(&session).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &session, nil
+}
+
+func (r *gormSessionRepository) GetForUser(ctx context.Context, userID, id uint) (*Session, error) {
+	var session Session
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &session, nil
+}
+
+func (r *gormSessionRepository) ListActive(ctx context.Context, userID uint) ([]Session, error) {
+	var sessions []Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, storeError(err)
+}
+
+func (r *gormSessionRepository) Touch(ctx context.Context, id uint, lastUsedAt, expiresAt time.Time) error {
+	return storeError(r.db.WithContext(ctx).Model(&Session{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": lastUsedAt, "expires_at": expiresAt}).Error)
+}
+
+func (r *gormSessionRepository) Revoke(ctx context.Context, id uint) error {
+	return storeError(r.db.WithContext(ctx).Model(&Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error)
+}
+
+func (r *gormSessionRepository) RevokeAll(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, storeError(result.Error)
+}
+
+// Refresh-токены
+
+type gormRefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormRefreshTokenRepository) Create(ctx context.Context, token *RefreshToken) error {
+	return storeError(r.db.WithContext(ctx).Create(token).Error)
+}
+
+func (r *gormRefreshTokenRepository) GetByHash(ctx context.Context, hash string) (*RefreshToken, error) {
+	var token RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &token, nil
+}
+
+func (r *gormRefreshTokenRepository) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	// Условие used_at IS NULL защищает от одновременного использования одного токена
+	result := r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return result.RowsAffected == 1, storeError(result.Error)
+}
+
+// Персональные токены
+
+type gormPersonalTokenRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormPersonalTokenRepository) Create(ctx context.Context, token *PersonalToken) error {
+	return storeError(r.db.WithContext(ctx).Create(token).Error)
+}
+
+func (r *gormPersonalTokenRepository) GetByHash(ctx context.Context, hash string) (*PersonalToken, error) {
+	var token PersonalToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &token, nil
+}
+
+func (r *gormPersonalTokenRepository) GetForUser(ctx context.Context, userID, id uint) (*PersonalToken, error) {
+	var token PersonalToken
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		First(&token).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return &token, nil
+}
+
+func (r *gormPersonalTokenRepository) ListForUser(ctx context.Context, userID uint) ([]PersonalToken, error) {
+	var tokens []PersonalToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, storeError(err)
+}
+
+func (r *gormPersonalTokenRepository) Touch(ctx context.Context, id uint, lastUsedAt time.Time) error {
+	return storeError(r.db.WithContext(ctx).Model(&PersonalToken{}).Where("id = ?", id).
+		Update("last_used_at", lastUsedAt).Error)
+}
+
+func (r *gormPersonalTokenRepository) Revoke(ctx context.Context, id uint) error {
+	return storeError(r.db.WithContext(ctx).Model(&PersonalToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error)
+}
+
+func (r *gormPersonalTokenRepository) RevokeAll(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&PersonalToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, storeError(result.Error)
+}
+
+// Токены из писем
+
+type gormEmailTokenRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormEmailTokenRepository) Create(ctx context.Context, token *EmailToken) error {
+	return storeError(r.db.WithContext(ctx).Create(token).Error)
+}
+
+func (r *gormEmailTokenRepository) GetByHash(ctx context.Context, hash string) (*EmailToken, error) {
+	var token EmailToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &token, nil
+}
+
+func (r *gormEmailTokenRepository) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	// Условие used_at IS NULL защищает от одновременного использования одного токена
+	result := r.db.WithContext(ctx).Model(&EmailToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return result.RowsAffected == 1, storeError(result.Error)
+}
+
+func (r *gormEmailTokenRepository) Invalidate(ctx context.Context, userID uint, purpose string, at time.Time) error {
+	return storeError(r.db.WithContext(ctx).Model(&EmailToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", at).Error)
+}
+
+// Коды восстановления
+
+type gormRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormRecoveryCodeRepository) Replace(ctx context.Context, userID uint, hashes []string) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]RecoveryCode, len(hashes))
+		for i, hash := range hashes {
+			codes[i] = RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(&codes).Error
+	}))
+}
+
+func (r *gormRecoveryCodeRepository) Use(ctx context.Context, userID uint, hash string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", at)
+	return result.RowsAffected > 0, storeError(result.Error)
+}
+
+func (r *gormRecoveryCodeRepository) CountUnused(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, storeError(err)
+}
+
+func (r *gormRecoveryCodeRepository) DeleteForUser(ctx context.Context, userID uint) error {
+	return storeError(r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error)
+}
+
+// Незавершённые входы
+
+type gormLoginChallengeRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormLoginChallengeRepository) Create(ctx context.Context, challenge *LoginChallenge) error {
+	return storeError(r.db.WithContext(ctx).Create(challenge).Error)
+}
+
+func (r *gormLoginChallengeRepository) GetByHash(ctx context.Context, hash string) (*LoginChallenge, error) {
+	var challenge LoginChallenge
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&challenge).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &challenge, nil
+}
+
+func (r *gormLoginChallengeRepository) TakeAttempt(ctx context.Context, id uint, max int) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&LoginChallenge{}).
+		Where("id = ? AND attempts < ?", id, max).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected == 1, storeError(result.Error)
+}
+
+func (r *gormLoginChallengeRepository) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&LoginChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return result.RowsAffected == 1, storeError(result.Error)
+}
+
+// Настройки
+
+type gormSettingRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormSettingRepository) Get(ctx context.Context, key string) (string, error) {
+	var setting Setting
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&setting).Error; err != nil {
+		return "", storeError(err)
+	}
+	return setting.Value, nil
+}
+
+func (r *gormSettingRepository) Set(ctx context.Context, key, value string) error {
+	return storeError(r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&Setting{Key: key, Value: value}).Error)
+}
+
+// Неудачные попытки входа
+
+type gormLoginFailureRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormLoginFailureRepository) Get(ctx context.Context, key string) (*LoginFailure, error) {
+	var failure LoginFailure
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&failure).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &failure, nil
+}
+
+func (r *gormLoginFailureRepository) RecordFailure(ctx context.Context, key string, at, windowStart time.Time) (*LoginFailure, error) {
+	db := r.db.WithContext(ctx)
+	// Счётчик увеличивается одним запросом, чтобы одновременные попытки не терялись
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":       gorm.Expr("CASE WHEN COALESCE(login_failures.locked_until, login_failures.last_failed_at) < ? THEN 1 ELSE login_failures.failures + 1 END", windowStart),
+			"last_failed_at": at,
+		}),
+	}).Create(&LoginFailure{Key: key, Failures: 1, LastFailedAt: at}).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return r.Get(ctx, key)
+}
+
+func (r *gormLoginFailureRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	return storeError(r.db.WithContext(ctx).Model(&LoginFailure{}).
+		Where("key = ?", key).
+		Update("locked_until", until).Error)
+}
+
+func (r *gormLoginFailureRepository) Reset(ctx context.Context, key string) error {
+	return storeError(r.db.WithContext(ctx).Where("key = ?", key).Delete(&LoginFailure{}).Error)
+}
+
+// Учётные записи внешнего провайдера входа
+
+type gormUserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormUserIdentityRepository) Create(ctx context.Context, identity *UserIdentity) error {
+	return storeError(r.db.WithContext(ctx).Create(identity).Error)
+}
+
+func (r *gormUserIdentityRepository) Get(ctx context.Context, issuer, subject string) (*UserIdentity, error) {
+	var identity UserIdentity
+	if err := r.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &identity, nil
+}
+
+func (r *gormUserIdentityRepository) RecordLogin(ctx context.Context, id uint, email *string, at time.Time) error {
+	return storeError(r.db.WithContext(ctx).Model(&UserIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"email": email, "last_login_at": at}).Error)
+}
+
+// Начатые входы через OpenID Connect
+
+type gormOIDCLoginRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormOIDCLoginRepository) Create(ctx context.Context, login *OIDCLogin) error {
+	return storeError(r.db.WithContext(ctx).Create(login).Error)
+}
+
+func (r *gormOIDCLoginRepository) GetByStateHash(ctx context.Context, hash string) (*OIDCLogin, error) {
+	var login OIDCLogin
+	if err := r.db.WithContext(ctx).Where("state_hash = ?", hash).First(&login).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &login, nil
+}
+
+func (r *gormOIDCLoginRepository) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&OIDCLogin{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return result.RowsAffected == 1, storeError(result.Error)
+}
+
+// Журнал аудита
+
+type gormAuditRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormAuditRepository) Create(ctx context.Context, event *AuditEvent) error {
+	return storeError(r.db.WithContext(ctx).Create(event).Error)
+}
+
+func (r *gormAuditRepository) List(ctx context.Context, filter AuditFilter) ([]AuditEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&AuditEvent{})
+	if filter.ProjectID != 0 {
+		query = query.Where("project_id = ?", filter.ProjectID)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("LOWER(actor) = ?", strings.ToLower(filter.Actor))
+	}
+	if strings.HasSuffix(filter.Action, ".") {
+		query = query.Where("action LIKE ?", filter.Action+"%")
+	} else if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, storeError(err)
+	}
+
+	var events []AuditEvent
+	err := query.Order("created_at DESC, id DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&events).Error
+	return events, total, storeError(err)
+}
+
+// История изменений задач
+
+type gormTaskHistoryRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormTaskHistoryRepository) Create(ctx context.Context, entries []TaskHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return storeError(r.db.WithContext(ctx).Create(&entries).Error)
+}
+
+func (r *gormTaskHistoryRepository) ListForTask(ctx context.Context, taskID uint) ([]TaskHistory, error) {
+	var entries []TaskHistory
+	err := r.db.WithContext(ctx).Where("task_id = ?", taskID).Order("id DESC").Find(&entries).Error
+	return entries, storeError(err)
+}
+
+// Лента событий проектов
+
+type gormActivityRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormActivityRepository) Create(ctx context.Context, activity *Activity) error {
+	return storeError(r.db.WithContext(ctx).Create(activity).Error)
+}
+
+func (r *gormActivityRepository) List(ctx context.Context, projectID uint, filter ActivityFilter) ([]Activity, error) {
+	query := r.db.WithContext(ctx).Where("project_id = ?", projectID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Before != 0 {
+		query = query.Where("id < ?", filter.Before)
+	}
+
+	var activities []Activity
+	err := query.Order("id DESC").Limit(filter.Limit).Find(&activities).Error
+	return activities, storeError(err)
+}
+
+// Комментарии к задачам
+
+type gormCommentRepository struct {
+	db *gorm.DB
+}
+
+// Добавление упоминаний, которых ещё нет, и удаление остальных упоминаний комментария
+func replaceCommentMentions(tx *gorm.DB, commentID uint, userIDs []uint, at time.Time) error {
+	query := tx.Where("comment_id = ?", commentID)
+	if len(userIDs) > 0 {
+		query = query.Where("user_id NOT IN ?", userIDs)
+	}
+	if err := query.Delete(&CommentMention{}).Error; err != nil {
+		return err
+	}
+
+	for _, id := range userIDs {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&CommentMention{CommentID: commentID, UserID: id, CreatedAt: at}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *gormCommentRepository) Create(ctx context.Context, comment *Comment, mentionIDs []uint) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return replaceCommentMentions(tx, comment.ID, mentionIDs, comment.CreatedAt)
+	}))
+}
+
+func (r *gormCommentRepository) GetInProject(ctx context.Context, projectID, id uint) (*Comment, error) {
+	var comment Comment
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Where("task_id IN (?)", r.db.Model(&Task{}).Select("id").Where("project_id = ?", projectID)).
+		First(&comment).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return &comment, nil
+}
+
+func (r *gormCommentRepository) ListForTask(ctx context.Context, taskID uint) ([]commentView, error) {
+	db := r.db.WithContext(ctx)
+	var comments []commentView
+	err := db.Table("task_comments").
+		Select("task_comments.id, task_comments.task_id, task_comments.author_id, COALESCE(users.username, '') AS author, "+
+			"task_comments.body, task_comments.created_at, task_comments.edited_at").
+		Joins("LEFT JOIN users ON users.id = task_comments.author_id").
+		Where("task_comments.task_id = ? AND task_comments.deleted_at IS NULL", taskID).
+		Order("task_comments.id").
+		Scan(&comments).Error
+	if err != nil || len(comments) == 0 {
+		return comments, storeError(err)
+	}
+
+	ids := make([]uint, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	var mentions []struct {
+		CommentID uint
+		Username  string
+	}
+	err = db.Table("comment_mentions").
+		Select("comment_mentions.comment_id, users.username").
+		Joins("JOIN users ON users.id = comment_mentions.user_id").
+		Where("comment_mentions.comment_id IN ?", ids).
+		Order("comment_mentions.id").
+		Scan(&mentions).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	byComment := make(map[uint][]string)
+	for _, m := range mentions {
+		byComment[m.CommentID] = append(byComment[m.CommentID], m.Username)
+	}
+	for i := range comments {
+		comments[i].Mentions = byComment[comments[i].ID]
+		if comments[i].Mentions == nil {
+			comments[i].Mentions = []string{}
+		}
+	}
+	return comments, nil
+}
+
+func (r *gormCommentRepository) Update(ctx context.Context, comment *Comment, mentionIDs []uint) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(comment).Updates(map[string]interface{}{"body": comment.Body, "edited_at": comment.EditedAt}).Error
+		if err != nil {
+			return err
+		}
+		return replaceCommentMentions(tx, comment.ID, mentionIDs, *comment.EditedAt)
+	}))
+}
+
+func (r *gormCommentRepository) Delete(ctx context.Context, id uint, at time.Time) error {
+	return storeError(r.db.WithContext(ctx).Model(&Comment{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", at).Error)
+}
+
+func (r *gormCommentRepository) ListMentions(ctx context.Context, userID uint, filter MentionFilter) ([]mentionView, error) {
+	query := r.db.WithContext(ctx).Table("comment_mentions").
+		Select("comment_mentions.id, task_comments.id AS comment_id, tasks.project_id, tasks.id AS task_id, "+
+			"tasks.title AS task_title, COALESCE(users.username, '') AS author, task_comments.body, "+
+			"task_comments.created_at, task_comments.edited_at").
+		Joins("JOIN task_comments ON task_comments.id = comment_mentions.comment_id AND task_comments.deleted_at IS NULL").
+		Joins("JOIN tasks ON tasks.id = task_comments.task_id").
+		Joins("JOIN project_members ON project_members.project_id = tasks.project_id AND project_members.user_id = comment_mentions.user_id").
+		Joins("LEFT JOIN users ON users.id = task_comments.author_id").
+		Where("comment_mentions.user_id = ?", userID)
+	if filter.Before != 0 {
+		query = query.Where("comment_mentions.id < ?", filter.Before)
+	}
+
+	var mentions []mentionView
+	err := query.Order("comment_mentions.id DESC").Limit(filter.Limit).Scan(&mentions).Error
+	return mentions, storeError(err)
+}
+
+// Чек-листы задач
+
+type gormChecklistRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormChecklistRepository) Create(ctx context.Context, item *ChecklistItem) error {
+	return storeError(r.db.WithContext(ctx).Create(item).Error)
+}
+
+func (r *gormChecklistRepository) GetInProject(ctx context.Context, projectID, id uint) (*ChecklistItem, error) {
+	var item ChecklistItem
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Where("task_id IN (?)", r.db.Model(&Task{}).Select("id").Where("project_id = ?", projectID)).
+		First(&item).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return &item, nil
+}
+
+func (r *gormChecklistRepository) ListForTask(ctx context.Context, taskID uint) ([]ChecklistItem, error) {
+	var items []ChecklistItem
+	err := r.db.WithContext(ctx).Where("task_id = ?", taskID).Order("id").Find(&items).Error
+	return items, storeError(err)
+}
+
+func (r *gormChecklistRepository) Update(ctx context.Context, item *ChecklistItem) error {
+	return storeError(r.db.WithContext(ctx).Save(item).Error)
+}
+
+func (r *gormChecklistRepository) Delete(ctx context.Context, id uint) error {
+	return storeError(r.db.WithContext(ctx).Delete(&ChecklistItem{}, id).Error)
+}
+
+// Связи задач
+
+type gormTaskLinkRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormTaskLinkRepository) Create(ctx context.Context, link *TaskLink) error {
+	return storeError(r.db.WithContext(ctx).Create(link).Error)
+}
+
+func (r *gormTaskLinkRepository) GetInProject(ctx context.Context, projectID, id uint) (*TaskLink, error) {
+	var link TaskLink
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Where("source_task_id IN (?)", r.db.Model(&Task{}).Select("id").Where("project_id = ?", projectID)).
+		First(&link).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return &link, nil
+}
+
+func (r *gormTaskLinkRepository) ListForTask(ctx context.Context, taskID uint) ([]taskLinkView, error) {
+	db := r.db.WithContext(ctx)
+	columns := "task_links.id, task_links.type, task_links.created_at, tasks.id AS task_id, tasks.title, tasks.status, tasks.deadline"
+
+	var outgoing []taskLinkView
+	err := db.Table("task_links").
+		Select(columns).
+		Joins("JOIN tasks ON tasks.id = task_links.target_task_id").
+		Where("task_links.source_task_id = ?", taskID).
+		Scan(&outgoing).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	var incoming []taskLinkView
+	err = db.Table("task_links").
+		Select(columns).
+		Joins("JOIN tasks ON tasks.id = task_links.source_task_id").
+		Where("task_links.target_task_id = ?", taskID).
+		Scan(&incoming).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	for i := range outgoing {
+		outgoing[i].Outgoing = true
+	}
+	links := append(outgoing, incoming...)
+	slices.SortFunc(links, func(a, b taskLinkView) int { return cmp.Compare(a.ID, b.ID) })
+	return links, nil
+}
+
+func (r *gormTaskLinkRepository) ListForProject(ctx context.Context, projectID uint, linkType string) ([]TaskLink, error) {
+	var links []TaskLink
+	err := r.db.WithContext(ctx).
+		Where("type = ?", linkType).
+		Where("source_task_id IN (?)", r.db.Model(&Task{}).Select("id").Where("project_id = ?", projectID)).
+		Order("id").
+		Find(&links).Error
+	return links, storeError(err)
+}
+
+func (r *gormTaskLinkRepository) OpenBlockers(ctx context.Context, taskID uint) ([]Task, error) {
+	var tasks []Task
+	err := r.db.WithContext(ctx).
+		Where("NOT "+taskStatusCategorySQL, StatusCategoryDone).
+		Where("id IN (?)", r.db.Model(&TaskLink{}).Select("source_task_id").
+			Where("target_task_id = ? AND type = ?", taskID, TaskLinkBlocks)).
+		Order("id").
+		Find(&tasks).Error
+	return tasks, storeError(err)
+}
+
+func (r *gormTaskLinkRepository) Delete(ctx context.Context, id uint) error {
+	return storeError(r.db.WithContext(ctx).Delete(&TaskLink{}, id).Error)
+}
+
+// Учёт времени
+
+type gormWorklogRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormWorklogRepository) Create(ctx context.Context, worklog *Worklog) error {
+	return storeError(r.db.WithContext(ctx).Create(worklog).Error)
+}
+
+func (r *gormWorklogRepository) GetInProject(ctx context.Context, projectID, id uint) (*Worklog, error) {
+	var worklog Worklog
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Where("task_id IN (?)", r.db.Model(&Task{}).Select("id").Where("project_id = ?", projectID)).
+		First(&worklog).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return &worklog, nil
+}
+
+func (r *gormWorklogRepository) ListForTask(ctx context.Context, taskID uint) ([]worklogView, error) {
+	var worklogs []worklogView
+	err := r.db.WithContext(ctx).Table("task_worklogs").
+		Select("task_worklogs.id, task_worklogs.task_id, task_worklogs.user_id, COALESCE(users.username, '') AS username, "+
+			"task_worklogs.duration_minutes, task_worklogs.work_date, task_worklogs.note, task_worklogs.created_at").
+		Joins("LEFT JOIN users ON users.id = task_worklogs.user_id").
+		Where("task_worklogs.task_id = ?", taskID).
+		Order("task_worklogs.work_date, task_worklogs.id").
+		Scan(&worklogs).Error
+	return worklogs, storeError(err)
+}
+
+func (r *gormWorklogRepository) Delete(ctx context.Context, id uint) error {
+	return storeError(r.db.WithContext(ctx).Delete(&Worklog{}, id).Error)
+}
+
+func (r *gormWorklogRepository) Timesheet(ctx context.Context, projectID uint, filter TimesheetFilter) ([]timesheetRow, error) {
+	query := r.db.WithContext(ctx).Table("task_worklogs").
+		Select("task_worklogs.user_id, COALESCE(users.username, '') AS username, tasks.id AS task_id, tasks.title, "+
+			"tasks.estimate_minutes, SUM(task_worklogs.duration_minutes) AS minutes").
+		Joins("JOIN tasks ON tasks.id = task_worklogs.task_id").
+		Joins("LEFT JOIN users ON users.id = task_worklogs.user_id").
+		Where("tasks.project_id = ?", projectID)
+	if filter.From != "" {
+		query = query.Where("task_worklogs.work_date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		query = query.Where("task_worklogs.work_date <= ?", filter.To)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("task_worklogs.user_id = ?", filter.UserID)
+	}
+
+	var rows []timesheetRow
+	err := query.
+		Group("task_worklogs.user_id, users.username, tasks.id, tasks.title, tasks.estimate_minutes").
+		Order("username, tasks.id").
+		Scan(&rows).Error
+	return rows, storeError(err)
+}
+
+type gormTimerRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormTimerRepository) Start(ctx context.Context, timer *Timer) error {
+	return storeError(r.db.WithContext(ctx).Create(timer).Error)
+}
+
+func (r *gormTimerRepository) GetForUser(ctx context.Context, userID uint) (*Timer, error) {
+	var timer Timer
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&timer).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &timer, nil
+}
+
+func (r *gormTimerRepository) Stop(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&Timer{}, id)
+	if result.Error != nil {
+		return storeError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Повторяющиеся задачи
+
+type gormRecurrenceRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormRecurrenceRepository) Create(ctx context.Context, recurrence *TaskRecurrence) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(recurrence).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&Task{}).
+			Where("id = ? AND recurrence_id IS NULL", recurrence.CurrentTaskID).
+			Update("recurrence_id", recurrence.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDuplicate
+		}
+		return nil
+	}))
+}
+
+func (r *gormRecurrenceRepository) GetByID(ctx context.Context, id uint) (*TaskRecurrence, error) {
+	var recurrence TaskRecurrence
+	if err := r.db.WithContext(ctx).First(&recurrence, id).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &recurrence, nil
+}
+
+func (r *gormRecurrenceRepository) GetInProject(ctx context.Context, projectID, id uint) (*TaskRecurrence, error) {
+	var recurrence TaskRecurrence
+	if err := r.db.WithContext(ctx).Where("id = ? AND project_id = ?", id, projectID).First(&recurrence).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &recurrence, nil
+}
+
+func (r *gormRecurrenceRepository) ListForProject(ctx context.Context, projectID uint) ([]TaskRecurrence, error) {
+	var recurrences []TaskRecurrence
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id").Find(&recurrences).Error
+	return recurrences, storeError(err)
+}
+
+func (r *gormRecurrenceRepository) ListDue(ctx context.Context, now time.Time) ([]TaskRecurrence, error) {
+	var recurrences []TaskRecurrence
+	err := r.db.WithContext(ctx).
+		Select("task_recurrences.*").
+		Joins("LEFT JOIN tasks ON tasks.id = task_recurrences.current_task_id").
+		Where("task_recurrences.status = ?", RecurrenceActive).
+		Where("(task_recurrences.current_at <= ? OR "+taskStatusCategorySQL+")", now, StatusCategoryDone).
+		Order("task_recurrences.id").
+		Find(&recurrences).Error
+	return recurrences, storeError(err)
+}
+
+func (r *gormRecurrenceRepository) ListTasks(ctx context.Context, id uint) ([]Task, error) {
+	var tasks []Task
+	err := r.db.WithContext(ctx).Where("recurrence_id = ?", id).Order("id").Find(&tasks).Error
+	return tasks, storeError(err)
+}
+
+func (r *gormRecurrenceRepository) UpdateRule(ctx context.Context, id uint, rule string) error {
+	return storeError(r.db.WithContext(ctx).Model(&TaskRecurrence{}).Where("id = ?", id).Update("rule", rule).Error)
+}
+
+func (r *gormRecurrenceRepository) Advance(ctx context.Context, recurrence *TaskRecurrence, task *Task, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&TaskRecurrence{}).
+		Where("id = ? AND status = ? AND occurrences = ?", recurrence.ID, RecurrenceActive, recurrence.Occurrences).
+		Updates(map[string]interface{}{
+			"current_task_id": task.ID,
+			"current_at":      at,
+			"occurrences":     recurrence.Occurrences + 1,
+		})
+	if result.Error != nil {
+		return storeError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormRecurrenceRepository) End(ctx context.Context, id uint, status string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&TaskRecurrence{}).
+		Where("id = ? AND status = ?", id, RecurrenceActive).
+		Updates(map[string]interface{}{"status": status, "ended_at": at})
+	if result.Error != nil {
+		return storeError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Уведомления
+
+type gormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormNotificationRepository) Create(ctx context.Context, notification *Notification) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "dedup_key"}},
+		DoNothing: true,
+	}).Create(notification)
+	if result.Error != nil {
+		return false, storeError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *gormNotificationRepository) GetForUser(ctx context.Context, userID, id uint) (*Notification, error) {
+	var notification Notification
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &notification, nil
+}
+
+func (r *gormNotificationRepository) List(ctx context.Context, userID uint, filter NotificationFilter) ([]Notification, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if filter.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+	if filter.Before != 0 {
+		query = query.Where("id < ?", filter.Before)
+	}
+
+	var notifications []Notification
+	err := query.Order("id DESC").Limit(filter.Limit).Find(&notifications).Error
+	return notifications, storeError(err)
+}
+
+func (r *gormNotificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, storeError(err)
+}
+
+func (r *gormNotificationRepository) SetRead(ctx context.Context, id uint, readAt *time.Time) error {
+	return storeError(r.db.WithContext(ctx).Model(&Notification{}).Where("id = ?", id).Update("read_at", readAt).Error)
+}
+
+func (r *gormNotificationRepository) MarkAllRead(ctx context.Context, userID uint, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	return result.RowsAffected, storeError(result.Error)
+}
+
+func (r *gormNotificationRepository) GetSettings(ctx context.Context, userID uint) (*NotificationSettings, error) {
+	var settings NotificationSettings
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &NotificationSettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return &settings, nil
+}
+
+func (r *gormNotificationRepository) SaveSettings(ctx context.Context, settings *NotificationSettings) error {
+	return storeError(r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "webhook_url", "webhook_secret", "updated_at"}),
+	}).Create(settings).Error)
+}
+
+// Рабочие процессы проектов
+
+// Условие «задача (таблица tasks) в статусе категории ? процесса своего проекта»
+const taskStatusCategorySQL = "EXISTS (SELECT 1 FROM workflow_statuses WHERE workflow_statuses.project_id = tasks.project_id" +
+	" AND workflow_statuses.name = tasks.status AND workflow_statuses.category = ?)"
+
+type gormWorkflowRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormWorkflowRepository) Get(ctx context.Context, projectID uint) (*Workflow, error) {
+	db := r.db.WithContext(ctx)
+	workflow := &Workflow{ProjectID: projectID}
+	if err := db.Where("project_id = ?", projectID).Order("position, id").Find(&workflow.Statuses).Error; err != nil {
+		return nil, storeError(err)
+	}
+	if err := db.Where("project_id = ?", projectID).Order("id").Find(&workflow.Transitions).Error; err != nil {
+		return nil, storeError(err)
+	}
+
+	names := make(map[uint]string, len(workflow.Statuses))
+	for _, s := range workflow.Statuses {
+		names[s.ID] = s.Name
+	}
+	for i := range workflow.Transitions {
+		workflow.Transitions[i].From = names[workflow.Transitions[i].FromStatusID]
+		workflow.Transitions[i].To = names[workflow.Transitions[i].ToStatusID]
+	}
+	return workflow, nil
+}
+
+func (r *gormWorkflowRepository) CountTasks(ctx context.Context, projectID uint) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&Task{}).
+		Select("status, COUNT(*) AS count").
+		Where("project_id = ?", projectID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *gormWorkflowRepository) Save(ctx context.Context, workflow *Workflow, moves map[uint]string) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveWorkflow(tx, workflow, moves)
+	}))
+}
+
+// Замена процесса проекта в транзакции tx (см. WorkflowRepository.Save)
+func saveWorkflow(tx *gorm.DB, workflow *Workflow, moves map[uint]string) error {
+	projectID := workflow.ProjectID
+	var existing []WorkflowStatus
+	if err := tx.Where("project_id = ?", projectID).Find(&existing).Error; err != nil {
+		return err
+	}
+	kept := make(map[uint]bool)
+	for _, s := range workflow.Statuses {
+		if s.ID != 0 {
+			kept[s.ID] = true
+		}
+	}
+
+	// Задачи перемещаемых статусов и сохраняемые статусы сначала получают временные имена
+	// "#<id>" (в именах статусов '#' не допускается), чтобы обмен именами не нарушал уникальность
+	var removed []uint
+	for _, s := range existing {
+		temp := fmt.Sprintf("#%d", s.ID)
+		if _, ok := moves[s.ID]; ok {
+			err := tx.Model(&Task{}).Where("project_id = ? AND status = ?", projectID, s.Name).Update("status", temp).Error
+			if err != nil {
+				return err
+			}
+		}
+		if !kept[s.ID] {
+			removed = append(removed, s.ID)
+			continue
+		}
+		if err := tx.Model(&WorkflowStatus{}).Where("id = ?", s.ID).Update("name", temp).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&WorkflowTransition{}).Error; err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		if err := tx.Delete(&WorkflowStatus{}, removed).Error; err != nil {
+			return err
+		}
+	}
+
+	ids := make(map[string]uint, len(workflow.Statuses))
+	for i := range workflow.Statuses {
+		s := &workflow.Statuses[i]
+		s.ProjectID = projectID
+		s.Position = i
+		if s.ID != 0 {
+			err := tx.Model(&WorkflowStatus{}).
+				Where("id = ? AND project_id = ?", s.ID, projectID).
+				Updates(map[string]interface{}{"name": s.Name, "category": s.Category, "position": s.Position}).Error
+			if err != nil {
+				return err
+			}
+		} else if err := tx.Create(s).Error; err != nil {
+			return err
+		}
+		ids[s.Name] = s.ID
+	}
+
+	for id, name := range moves {
+		err := tx.Model(&Task{}).Where("project_id = ? AND status = ?", projectID, fmt.Sprintf("#%d", id)).Update("status", name).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(workflow.Transitions) == 0 {
+		return nil
+	}
+	for i := range workflow.Transitions {
+		t := &workflow.Transitions[i]
+		t.ID = 0
+		t.ProjectID = projectID
+		t.FromStatusID = ids[t.From]
+		t.ToStatusID = ids[t.To]
+	}
+	return tx.Create(&workflow.Transitions).Error
+}
+
+// Пользовательские поля
+
+type gormCustomFieldRepository struct {
+	db *gorm.DB
+}
+
+// Столбец task_field_values со значением поля
+func fieldValueColumn(numeric bool) string {
+	if numeric {
+		return "number_value"
+	}
+	return "text_value"
+}
+
+// Порядок списка задач (см. TaskSort)
+func taskOrder(sort TaskSort) interface{} {
+	dir := "ASC"
+	if sort.Desc {
+		dir = "DESC"
+	}
+	if sort.FieldID == 0 {
+		column := cmp.Or(sort.Column, "id")
+		if column == "id" {
+			return "id " + dir
+		}
+		return column + " " + dir + ", id"
+	}
+
+	// У multi_select несколько значений: по возрастанию сравнивается наименьшее, по убыванию — наибольшее
+	aggregate := "MIN"
+	if sort.Desc {
+		aggregate = "MAX"
+	}
+	value := "(SELECT " + aggregate + "(" + fieldValueColumn(sort.Numeric) + ") FROM task_field_values" +
+		" WHERE task_field_values.task_id = tasks.id AND task_field_values.field_id = ?)"
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                value + " IS NULL, " + value + " " + dir + ", id",
+		Vars:               []interface{}{sort.FieldID, sort.FieldID},
+		WithoutParentheses: true,
+	}}
+}
+
+func (r *gormCustomFieldRepository) Create(ctx context.Context, field *CustomField) error {
+	return storeError(r.db.WithContext(ctx).Create(field).Error)
+}
+
+func (r *gormCustomFieldRepository) GetInProject(ctx context.Context, projectID, id uint) (*CustomField, error) {
+	var field CustomField
+	if err := r.db.WithContext(ctx).Where("id = ? AND project_id = ?", id, projectID).First(&field).Error; err != nil {
+		return nil, storeError(err)
+	}
+	return &field, nil
+}
+
+func (r *gormCustomFieldRepository) List(ctx context.Context, projectID uint) ([]CustomField, error) {
+	var fields []CustomField
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id").Find(&fields).Error
+	return fields, storeError(err)
+}
+
+func (r *gormCustomFieldRepository) Update(ctx context.Context, field *CustomField, removedOptions []string) error {
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(field).Error; err != nil {
+			return err
+		}
+		if len(removedOptions) == 0 {
+			return nil
+		}
+		return tx.Where("field_id = ? AND text_value IN ?", field.ID, removedOptions).Delete(&TaskFieldValue{}).Error
+	}))
+}
+
+func (r *gormCustomFieldRepository) Delete(ctx context.Context, id uint) error {
+	// Значения поля удаляются каскадно
+	return storeError(r.db.WithContext(ctx).Delete(&CustomField{}, id).Error)
+}
+
+func (r *gormCustomFieldRepository) ListValues(ctx context.Context, taskIDs []uint) ([]taskFieldValueView, error) {
+	var rows []taskFieldValueView
+	if len(taskIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.WithContext(ctx).Model(&TaskFieldValue{}).
+		Select("task_field_values.task_id, custom_fields.name, custom_fields.type, task_field_values.text_value, task_field_values.number_value").
+		Joins("JOIN custom_fields ON custom_fields.id = task_field_values.field_id").
+		Where("task_field_values.task_id IN ?", taskIDs).
+		Order("custom_fields.id, task_field_values.id").
+		Scan(&rows).Error
+	return rows, storeError(err)
+}
+
+func (r *gormCustomFieldRepository) SetValues(ctx context.Context, taskID uint, fieldIDs []uint, rows []TaskFieldValue) error {
+	if len(fieldIDs) == 0 {
+		return nil
+	}
+	return storeError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ? AND field_id IN ?", taskID, fieldIDs).Delete(&TaskFieldValue{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		for i := range rows {
+			rows[i].ID = 0
+			rows[i].TaskID = taskID
+		}
+		return tx.Create(&rows).Error
+	}))
+}
+
+func (r *gormCustomFieldRepository) CopyValues(ctx context.Context, fromTaskID, toTaskID uint) error {
+	return storeError(r.db.WithContext(ctx).Exec(
+		"INSERT INTO task_field_values (task_id, field_id, text_value, number_value)"+
+			" SELECT ?, field_id, text_value, number_value FROM task_field_values WHERE task_id = ? ORDER BY id",
+		toTaskID, fromTaskID).Error)
+}
@@ -0,0 +1,28 @@
+package GoAPIManager
+
+import (
+	"gorm.io/gorm"
+)
+
+// Диалект PostgreSQL
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) DateEquals(column string) string {
+	return "date_trunc('day', " + column + ") = ?"
+}
+
+func (postgresDialect) MigrationsTableSQL() string {
+	return `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		dirty      BOOLEAN NOT NULL DEFAULT FALSE,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`
+}
+
+// NewPostgresStore создаёт хранилище поверх подключения к PostgreSQL
+func NewPostgresStore(conn *gorm.DB) *Store {
+	return newGormStore(conn, postgresDialect{})
+}
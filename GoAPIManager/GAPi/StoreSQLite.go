@@ -0,0 +1,28 @@
+package GoAPIManager
+
+import (
+	"gorm.io/gorm"
+)
+
+// Диалект SQLite. Колонки времени объявляются как DATETIME, иначе драйвер не разбирает их в time.Time.
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) DateEquals(column string) string {
+	return "date(" + column + ") = ?"
+}
+
+func (sqliteDialect) MigrationsTableSQL() string {
+	return `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		dirty      BOOLEAN NOT NULL DEFAULT FALSE,
+		applied_at DATETIME NOT NULL
+	)`
+}
+
+// NewSQLiteStore создаёт хранилище поверх подключения к SQLite
+func NewSQLiteStore(conn *gorm.DB) *Store {
+	return newGormStore(conn, sqliteDialect{})
+}
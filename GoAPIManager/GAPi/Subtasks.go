@@ -0,0 +1,416 @@
+package GoAPIManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Максимальная глубина вложенности задач (задача верхнего уровня — первый уровень)
+const maxTaskDepth = 5
+
+// Ограничения чек-листа задачи
+const (
+	maxChecklistItems       = 100
+	maxChecklistTitleLength = 255
+)
+
+var (
+	errParentNotFound = errors.New("parent task not found in this project")
+	errTaskCycle      = errors.New("a task cannot be a subtask of itself or of its subtasks")
+	errTaskTooDeep    = fmt.Errorf("subtasks cannot be nested deeper than %d levels", maxTaskDepth)
+)
+
+// Пункт чек-листа задачи
+type ChecklistItem struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TaskID    uint       `gorm:"not null" json:"task_id"`
+	Title     string     `gorm:"not null" json:"title"`
+	Done      bool       `gorm:"not null" json:"done"`
+	CreatedAt time.Time  `json:"created_at"`
+	DoneAt    *time.Time `json:"done_at"`
+}
+
+func (ChecklistItem) TableName() string {
+	return "task_checklist_items"
+}
+
+// Тело запроса на добавление или изменение пункта чек-листа (при изменении пустые поля не меняются)
+type checklistItemRequest struct {
+	Title *string `json:"title"`
+	Done  *bool   `json:"done"`
+}
+
+// Выполнено из общего количества
+type progressCount struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// Выполнение задачи: прямые подзадачи в статусах категории done и отмеченные пункты чек-листа.
+// Percent — доля выполненного от всех подзадач и пунктов; если их нет, 100 для завершённой
+// задачи и 0 для остальных.
+type TaskProgress struct {
+	Subtasks  progressCount `json:"subtasks"`
+	Checklist progressCount `json:"checklist"`
+	Percent   int           `json:"percent"`
+}
+
+// Задача в списке задач проекта: с категорией статуса, выполнением и, при ?tree=true, с подзадачами
+type taskView struct {
+	Task
+	StatusCategory string       `json:"status_category"`
+	Progress       TaskProgress `json:"progress"`
+	Subtasks       []*taskView  `json:"subtasks,omitempty"`
+}
+
+// Процент выполнения задачи (finished — задача в статусе категории done)
+func (p *TaskProgress) complete(finished bool) {
+	done := p.Subtasks.Done + p.Checklist.Done
+	total := p.Subtasks.Total + p.Checklist.Total
+	switch {
+	case total > 0:
+		p.Percent = done * 100 / total
+	case finished:
+		p.Percent = 100
+	default:
+		p.Percent = 0
+	}
+}
+
+// Выполнение одной задачи
+func loadTaskProgress(ctx context.Context, task *Task) (TaskProgress, error) {
+	progress, err := store.Tasks.Progress(ctx, []uint{task.ID})
+	if err != nil {
+		return TaskProgress{}, err
+	}
+	workflow, err := store.Workflows.Get(ctx, task.ProjectID)
+	if err != nil {
+		return TaskProgress{}, err
+	}
+	p := progress[task.ID]
+	p.complete(workflow.isDone(task.Status))
+	return p, nil
+}
+
+// Задачи проекта с процессом workflow с выполнением для ответа API
+func newTaskViews(ctx context.Context, workflow *Workflow, tasks []Task) ([]taskView, error) {
+	ids := make([]uint, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	progress, err := store.Tasks.Progress(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]taskView, len(tasks))
+	for i, t := range tasks {
+		views[i] = taskView{Task: t, StatusCategory: workflow.category(t.Status), Progress: progress[t.ID]}
+		views[i].Progress.complete(workflow.isDone(t.Status))
+	}
+	return views, nil
+}
+
+// Дерево задач: подзадачи вкладываются в родительские задачи из того же списка,
+// задачи, чьей родительской задачи в списке нет, становятся корнями. Порядок сохраняется.
+func buildTaskTree(views []taskView) []*taskView {
+	byID := make(map[uint]*taskView, len(views))
+	for i := range views {
+		byID[views[i].ID] = &views[i]
+	}
+
+	roots := []*taskView{}
+	for i := range views {
+		node := &views[i]
+		if node.ParentTaskID != nil {
+			if parent, ok := byID[*node.ParentTaskID]; ok {
+				parent.Subtasks = append(parent.Subtasks, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// Проверка родительской задачи: она принадлежит тому же проекту, задача не становится
+// подзадачей самой себя или своих подзадач, а вложенность не превышает maxTaskDepth.
+// Для новой задачи task.ID равен 0.
+func validateParentTask(ctx context.Context, task *Task) error {
+	if task.ParentTaskID == nil {
+		return nil
+	}
+
+	tasks, err := store.Tasks.List(ctx, task.ProjectID, TaskFilter{})
+	if err != nil {
+		return err
+	}
+	parents := make(map[uint]*uint, len(tasks))
+	for _, t := range tasks {
+		parents[t.ID] = t.ParentTaskID
+	}
+	if _, ok := parents[*task.ParentTaskID]; !ok {
+		return errParentNotFound
+	}
+
+	// Уровень родительской задачи; по пути вверх не должна встретиться сама задача
+	depth := 0
+	for id := task.ParentTaskID; id != nil; id = parents[*id] {
+		if *id == task.ID {
+			return errTaskCycle
+		}
+		depth++
+		if depth > maxTaskDepth {
+			break
+		}
+	}
+
+	// Высота поддерева задачи (1 — подзадач нет)
+	height := 1
+	if task.ID != 0 {
+		children := make(map[uint][]uint)
+		for _, t := range tasks {
+			if t.ParentTaskID != nil {
+				children[*t.ParentTaskID] = append(children[*t.ParentTaskID], t.ID)
+			}
+		}
+		level := []uint{task.ID}
+		for {
+			var next []uint
+			for _, id := range level {
+				next = append(next, children[id]...)
+			}
+			if len(next) == 0 || height > maxTaskDepth {
+				break
+			}
+			level = next
+			height++
+		}
+	}
+
+	if depth+height > maxTaskDepth {
+		return errTaskTooDeep
+	}
+	return nil
+}
+
+// Ответ на ошибку проверки родительской задачи
+func respondParentError(c *gin.Context, err error) {
+	if errors.Is(err, errParentNotFound) || errors.Is(err, errTaskCycle) || errors.Is(err, errTaskTooDeep) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent_task_id: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+}
+
+// Одинаковы ли родительские задачи
+func sameParentTask(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Проверка названия пункта чек-листа; при ошибке отвечает 400 и возвращает false
+func validChecklistTitle(c *gin.Context, title string) bool {
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Checklist item title is required"})
+		return false
+	}
+	if utf8.RuneCountInString(title) > maxChecklistTitleLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Checklist item title cannot exceed %d characters", maxChecklistTitleLength)})
+		return false
+	}
+	return true
+}
+
+// Пункт чек-листа из пути, принадлежащий задаче из пути (найдены в permissionMiddleware).
+// Если пункт относится к другой задаче проекта, отвечает 404 и возвращает nil.
+func pathChecklistItem(c *gin.Context) (*Task, *ChecklistItem) {
+	task := c.MustGet("task").(*Task)
+	item := c.MustGet("checklist_item").(*ChecklistItem)
+	if item.TaskID != task.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checklist item not found"})
+		return nil, nil
+	}
+	return task, item
+}
+
+// @Summary Чек-лист задачи
+// @Description Возвращает пункты чек-листа задачи в порядке добавления и выполнение задачи (подзадачи и чек-лист)
+// @Tags Задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Чек-лист и выполнение"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/checklist [get]
+func getTaskChecklist(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	ctx := c.Request.Context()
+	items, err := store.Checklists.ListForTask(ctx, task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	progress, err := loadTaskProgress(ctx, task)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"TaskID": task.ID, "Checklist": items, "Progress": progress})
+}
+
+// @Summary Добавление пункта чек-листа
+// @Description Добавляет пункт в конец чек-листа задачи (не больше 100 пунктов, название до 255 символов)
+// @Tags Задачи
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body checklistItemRequest true "Название и отметка о выполнении"
+// @Success 201 {object} map[string]interface{} "Пункт добавлен"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/checklist [post]
+func createChecklistItem(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	var req checklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	var title string
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+	}
+	if !validChecklistTitle(c, title) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	items, err := store.Checklists.ListForTask(ctx, task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if len(items) >= maxChecklistItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A checklist cannot have more than %d items", maxChecklistItems)})
+		return
+	}
+
+	item := ChecklistItem{TaskID: task.ID, Title: title, CreatedAt: time.Now()}
+	if req.Done != nil && *req.Done {
+		item.Done = true
+		item.DoneAt = &item.CreatedAt
+	}
+	if err := store.Checklists.Create(ctx, &item); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add checklist item: " + err.Error()})
+		return
+	}
+	recordAudit(c, "checklist_item.create", "checklist_item", item.ID, task.ProjectID, nil, &item)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Пункт чек-листа успешно добавлен", "Item": item})
+}
+
+// @Summary Изменение пункта чек-листа
+// @Description Изменяет название пункта и/или отмечает его выполненным (done). Поля, которых нет в запросе, не меняются.
+// @Tags Задачи
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param item_id path int true "ID пункта чек-листа"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body checklistItemRequest true "Новое название и/или отметка о выполнении"
+// @Success 200 {object} map[string]interface{} "Пункт изменён"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Пункт не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/checklist/{item_id} [put]
+func updateChecklistItem(c *gin.Context) {
+	task, item := pathChecklistItem(c)
+	if item == nil {
+		return
+	}
+
+	var req checklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	before := *item
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if !validChecklistTitle(c, title) {
+			return
+		}
+		item.Title = title
+	}
+	if req.Done != nil && *req.Done != item.Done {
+		item.Done = *req.Done
+		item.DoneAt = nil
+		if item.Done {
+			now := time.Now()
+			item.DoneAt = &now
+		}
+	}
+
+	if err := store.Checklists.Update(c.Request.Context(), item); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update checklist item: " + err.Error()})
+		return
+	}
+	recordAudit(c, "checklist_item.update", "checklist_item", item.ID, task.ProjectID, &before, item)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пункт чек-листа успешно изменён", "Item": item})
+}
+
+// @Summary Удаление пункта чек-листа
+// @Description Удаляет пункт из чек-листа задачи
+// @Tags Задачи
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param item_id path int true "ID пункта чек-листа"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Пункт удалён"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Пункт не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/checklist/{item_id} [delete]
+func deleteChecklistItem(c *gin.Context) {
+	task, item := pathChecklistItem(c)
+	if item == nil {
+		return
+	}
+
+	if err := store.Checklists.Delete(c.Request.Context(), item.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete checklist item: " + err.Error()})
+		return
+	}
+	recordAudit(c, "checklist_item.delete", "checklist_item", item.ID, task.ProjectID, item, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пункт чек-листа успешно удалён"})
+}
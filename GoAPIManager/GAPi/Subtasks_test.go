@@ -0,0 +1,123 @@
+package GoAPIManager
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Тело запроса задачи с родительской задачей parent (0 — задача верхнего уровня)
+func subtaskBody(title string, parent uint) gin.H {
+	body := gin.H{"title": title, "priority": "Low", "status": "In_Line", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339)}
+	if parent != 0 {
+		body["parent_task_id"] = parent
+	}
+	return body
+}
+
+// Создание подзадачи; возвращает её ID
+func (s *testServer) subtask(token string, projectID, parent uint, title string) uint {
+	s.t.Helper()
+	out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), token, subtaskBody(title, parent))
+	return uint(field(s.t, out, "Task", "ID").(float64))
+}
+
+func TestValidateParentTask(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	alpha := s.project(alice, "Alpha")
+	beta := s.project(alice, "Beta")
+	tasks := fmt.Sprintf("/projects/%d/tasks", alpha)
+
+	// Цепочка из maxTaskDepth уровней: root -> ... -> chain[maxTaskDepth-1]
+	chain := []uint{s.task(alice, alpha, "Root")}
+	for i := 1; i < maxTaskDepth; i++ {
+		chain = append(chain, s.subtask(alice, alpha, chain[i-1], fmt.Sprintf("Level %d", i+1)))
+	}
+
+	// Боковая ветка до 4-го уровня
+	side := s.subtask(alice, alpha, chain[0], "Side 2")
+	side = s.subtask(alice, alpha, side, "Side 3")
+	side = s.subtask(alice, alpha, side, "Side 4")
+
+	for name, tc := range map[string]struct {
+		method, path string
+		body         gin.H
+	}{
+		"parent in another project": {http.MethodPost, tasks, subtaskBody("Foreign", s.task(alice, beta, "Beta task"))},
+		"missing parent":            {http.MethodPost, tasks, subtaskBody("Orphan", 9999)},
+		"too deep":                  {http.MethodPost, tasks, subtaskBody("Level 6", chain[maxTaskDepth-1])},
+		"own parent":                {http.MethodPut, fmt.Sprintf("%s/%d", tasks, chain[1]), subtaskBody("Level 2", chain[1])},
+		"parent is a subtask":       {http.MethodPut, fmt.Sprintf("%s/%d", tasks, chain[0]), subtaskBody("Root", chain[2])},
+		// Поддерево высотой 2 под задачей 4-го уровня превысило бы глубину
+		"subtree too deep": {http.MethodPut, fmt.Sprintf("%s/%d", tasks, chain[maxTaskDepth-2]), subtaskBody("Moved", side)},
+	} {
+		if w := s.request(tc.method, tc.path, alice, tc.body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want %d: %s", name, w.Code, http.StatusBadRequest, w.Body)
+		}
+	}
+
+	// Перенос поддерева, если глубина позволяет, и вынос на верхний уровень
+	other := s.task(alice, alpha, "Other")
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("%s/%d", tasks, chain[maxTaskDepth-2]), alice, subtaskBody("Moved", other))
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("%s/%d", tasks, chain[1]), alice, subtaskBody("Level 2", 0))
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("%s/%d", tasks, chain[0]), alice, subtaskBody("Root", other))
+}
+
+func TestTaskProgressRollUp(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	tasks := fmt.Sprintf("/projects/%d/tasks", projectID)
+	parent := s.task(alice, projectID, "Parent")
+	done := s.subtask(alice, projectID, parent, "Done subtask")
+	s.subtask(alice, projectID, parent, "Open subtask")
+	done2 := subtaskBody("Done subtask", parent)
+	done2["status"] = "Done"
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("%s/%d", tasks, done), alice, done2)
+
+	checklist := fmt.Sprintf("%s/%d/checklist", tasks, parent)
+	var items []uint
+	for _, title := range []string{"Spec", "Review"} {
+		out := s.expect(http.StatusCreated, http.MethodPost, checklist, alice, gin.H{"title": title})
+		items = append(items, uint(field(t, out, "Item", "id").(float64)))
+	}
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("%s/%d", checklist, items[0]), alice, gin.H{"done": true})
+
+	progress := func() map[string]interface{} {
+		return s.expect(http.StatusOK, http.MethodGet, checklist, alice, nil)["Progress"].(map[string]interface{})
+	}
+	// Выполнено 1 из 2 подзадач и 1 из 2 пунктов
+	p := progress()
+	if !jsonEqual(t, p, gin.H{"subtasks": gin.H{"done": 1, "total": 2}, "checklist": gin.H{"done": 1, "total": 2}, "percent": 50}) {
+		t.Errorf("progress = %v", p)
+	}
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("%s/%d", checklist, items[1]), alice, gin.H{"done": true})
+	if p := progress(); p["percent"] != float64(75) {
+		t.Errorf("percent after checking the list = %v, want 75", p["percent"])
+	}
+
+	// В дереве у подзадачи без подзадач и чек-листа выполнение по статусу
+	out := s.expect(http.StatusOK, http.MethodGet, tasks+"?tree=true", alice, nil)
+	roots := out["Задачи"].([]interface{})
+	if len(roots) != 1 {
+		t.Fatalf("tree roots = %v, want only the parent", roots)
+	}
+	root := roots[0].(map[string]interface{})
+	if root["progress"].(map[string]interface{})["percent"] != float64(75) {
+		t.Errorf("root progress = %v", root["progress"])
+	}
+	for _, sub := range root["subtasks"].([]interface{}) {
+		view := sub.(map[string]interface{})
+		want := float64(0)
+		if view["status"] == "Done" {
+			want = 100
+		}
+		if percent := view["progress"].(map[string]interface{})["percent"]; percent != want {
+			t.Errorf("subtask %v percent = %v, want %v", view["title"], percent, want)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package GoAPIManager
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ограничения записи о затраченном времени
+const (
+	maxWorklogMinutes    = 24 * 60
+	maxWorklogNoteLength = 1000
+)
+
+// Затраченное на задачу время
+type Worklog struct {
+	ID     uint `gorm:"primaryKey" json:"id"`
+	TaskID uint `gorm:"not null" json:"task_id"`
+	// Пользователь, который работал над задачей (null — пользователь удалён)
+	UserID          *uint     `json:"user_id"`
+	DurationMinutes int       `gorm:"not null" json:"duration_minutes"`
+	WorkDate        string    `gorm:"not null" json:"date"` // YYYY-MM-DD
+	Note            string    `gorm:"not null" json:"note"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (Worklog) TableName() string {
+	return "task_worklogs"
+}
+
+// Запущенный таймер пользователя. При остановке он превращается в запись о затраченном времени.
+type Timer struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TaskID    uint      `gorm:"not null" json:"task_id"`
+	UserID    uint      `gorm:"not null" json:"user_id"`
+	StartedAt time.Time `gorm:"not null" json:"started_at"`
+}
+
+func (Timer) TableName() string {
+	return "task_timers"
+}
+
+// Запись о затраченном времени в ответах API: с именем пользователя
+type worklogView struct {
+	ID              uint      `json:"id"`
+	TaskID          uint      `json:"task_id"`
+	UserID          *uint     `json:"user_id"`
+	Username        string    `json:"username"`
+	DurationMinutes int       `json:"duration_minutes"`
+	WorkDate        string    `json:"date"`
+	Note            string    `json:"note"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Таймер в ответах API: с задачей и временем с момента запуска
+type timerView struct {
+	ID             uint      `json:"id"`
+	TaskID         uint      `json:"task_id"`
+	ProjectID      uint      `json:"project_id"`
+	TaskTitle      string    `json:"task_title"`
+	StartedAt      time.Time `json:"started_at"`
+	ElapsedMinutes int       `json:"elapsed_minutes"`
+}
+
+// Тело запроса на добавление записи о затраченном времени
+type worklogRequest struct {
+	DurationMinutes int    `json:"duration_minutes"`
+	Date            string `json:"date"` // YYYY-MM-DD, по умолчанию — сегодня (UTC)
+	Note            string `json:"note"`
+}
+
+// Тело запроса на остановку таймера (необязательное)
+type stopTimerRequest struct {
+	Note string `json:"note"`
+}
+
+// Время пользователя на задаче за период (строка отчёта из базы)
+type timesheetRow struct {
+	UserID          *uint
+	Username        string
+	TaskID          uint
+	Title           string
+	EstimateMinutes *int
+	Minutes         int
+}
+
+// Время на задаче в отчёте
+type timesheetTask struct {
+	TaskID          uint   `json:"task_id"`
+	Title           string `json:"title"`
+	EstimateMinutes *int   `json:"estimate_minutes"`
+	Minutes         int    `json:"minutes"`
+}
+
+// Время пользователя в отчёте: всего и по задачам
+type timesheetUser struct {
+	UserID   *uint           `json:"user_id"`
+	Username string          `json:"username"`
+	Minutes  int             `json:"minutes"`
+	Tasks    []timesheetTask `json:"tasks"`
+}
+
+// Проверка записи о затраченном времени; при ошибке отвечает 400 и возвращает false
+func validWorklog(c *gin.Context, worklog *Worklog) bool {
+	if worklog.DurationMinutes < 1 || worklog.DurationMinutes > maxWorklogMinutes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid duration_minutes, allowed values are 1-%d", maxWorklogMinutes)})
+		return false
+	}
+	if _, err := time.Parse("2006-01-02", worklog.WorkDate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, expected YYYY-MM-DD"})
+		return false
+	}
+	if utf8.RuneCountInString(worklog.Note) > maxWorklogNoteLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Note cannot exceed %d characters", maxWorklogNoteLength)})
+		return false
+	}
+	return true
+}
+
+// Таймер в ответе API
+func newTimerView(timer *Timer, task *Task) timerView {
+	return timerView{
+		ID:             timer.ID,
+		TaskID:         task.ID,
+		ProjectID:      task.ProjectID,
+		TaskTitle:      task.Title,
+		StartedAt:      timer.StartedAt,
+		ElapsedMinutes: int(time.Since(timer.StartedAt) / time.Minute),
+	}
+}
+
+// @Summary Затраченное на задачу время
+// @Description Возвращает записи о затраченном на задачу времени (по дням работы), их сумму и оценку задачи
+// @Tags Учёт времени
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Записи о времени"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/worklogs [get]
+func getTaskWorklogs(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	worklogs, err := store.Worklogs.ListForTask(c.Request.Context(), task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	total := 0
+	for _, w := range worklogs {
+		total += w.DurationMinutes
+	}
+
+	c.JSON(http.StatusOK, gin.H{"TaskID": task.ID, "EstimateMinutes": task.EstimateMinutes, "TotalMinutes": total, "Worklogs": worklogs})
+}
+
+// @Summary Добавление затраченного времени
+// @Description Записывает время, затраченное текущим пользователем на задачу: длительность в минутах (1-1440), день работы (по умолчанию сегодня) и комментарий
+// @Tags Учёт времени
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body worklogRequest true "Длительность, день и комментарий"
+// @Success 201 {object} map[string]interface{} "Время записано"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/worklogs [post]
+func createWorklog(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	var req worklogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	worklog := Worklog{
+		TaskID:          task.ID,
+		UserID:          requestActor(c),
+		DurationMinutes: req.DurationMinutes,
+		WorkDate:        req.Date,
+		Note:            strings.TrimSpace(req.Note),
+		CreatedAt:       time.Now(),
+	}
+	if worklog.WorkDate == "" {
+		worklog.WorkDate = worklog.CreatedAt.UTC().Format("2006-01-02")
+	}
+	if !validWorklog(c, &worklog) {
+		return
+	}
+
+	if err := store.Worklogs.Create(c.Request.Context(), &worklog); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log time: " + err.Error()})
+		return
+	}
+	recordAudit(c, "worklog.create", "worklog", worklog.ID, task.ProjectID, nil, &worklog)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Время успешно записано", "Worklog": worklog})
+}
+
+// @Summary Удаление затраченного времени
+// @Description Удаляет запись о затраченном времени. Удалить можно свою запись; мейнтейнеры и владельцы проекта могут удалять любые.
+// @Tags Учёт времени
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param worklog_id path int true "ID записи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]string "Запись удалена"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Запись не найдена"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/worklogs/{worklog_id} [delete]
+func deleteWorklog(c *gin.Context) {
+	// Задача и запись проекта :id (найдены в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+	worklog := c.MustGet("worklog").(*Worklog)
+	if worklog.TaskID != task.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worklog not found"})
+		return
+	}
+	own := worklog.UserID != nil && *worklog.UserID == c.GetUint("id")
+	if !own && !can(c, PermTimeManage) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot delete this worklog"})
+		return
+	}
+
+	if err := store.Worklogs.Delete(c.Request.Context(), worklog.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete worklog: " + err.Error()})
+		return
+	}
+	recordAudit(c, "worklog.delete", "worklog", worklog.ID, task.ProjectID, worklog, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Запись о времени успешно удалена"})
+}
+
+// @Summary Запуск таймера
+// @Description Запускает таймер текущего пользователя на задаче. У пользователя может быть только один запущенный таймер.
+// @Tags Учёт времени
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Success 201 {object} map[string]interface{} "Таймер запущен"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Задача не найдена"
+// @Failure 409 {object} map[string]interface{} "Уже запущен другой таймер"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/timer/start [post]
+func startTimer(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	ctx := c.Request.Context()
+	timer := Timer{TaskID: task.ID, UserID: c.GetUint("id"), StartedAt: time.Now()}
+	err := store.Timers.Start(ctx, &timer)
+	if errors.Is(err, ErrDuplicate) {
+		running, err := store.Timers.GetForUser(ctx, timer.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "A timer is already running, stop it first", "TimerTaskID": running.TaskID})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start timer: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Таймер запущен", "Timer": newTimerView(&timer, task)})
+}
+
+// @Summary Остановка таймера
+// @Description Останавливает таймер текущего пользователя на задаче и записывает затраченное время (минуты округляются вверх, не больше 1440) на день запуска таймера
+// @Tags Учёт времени
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param task_id path int true "ID задачи"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body stopTimerRequest false "Комментарий к записи о времени"
+// @Success 200 {object} map[string]interface{} "Таймер остановлен, время записано"
+// @Failure 400 {object} map[string]string "Некорректные данные"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Таймер на задаче не запущен"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/tasks/{task_id}/timer/stop [post]
+func stopTimer(c *gin.Context) {
+	// Задача проекта :id (найдена в permissionMiddleware)
+	task := c.MustGet("task").(*Task)
+
+	var req stopTimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	timer, err := store.Timers.GetForUser(ctx, c.GetUint("id"))
+	if errors.Is(err, ErrNotFound) || err == nil && timer.TaskID != task.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No running timer on this task"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	minutes := int((now.Sub(timer.StartedAt) + time.Minute - 1) / time.Minute)
+	worklog := Worklog{
+		TaskID:          task.ID,
+		UserID:          requestActor(c),
+		DurationMinutes: min(max(minutes, 1), maxWorklogMinutes),
+		WorkDate:        timer.StartedAt.UTC().Format("2006-01-02"),
+		Note:            strings.TrimSpace(req.Note),
+		CreatedAt:       now,
+	}
+	if !validWorklog(c, &worklog) {
+		return
+	}
+
+	// Таймер удаляется в одной транзакции с записью, чтобы время не записалось дважды
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Timers.Stop(ctx, timer.ID); err != nil {
+			return err
+		}
+		return tx.Worklogs.Create(ctx, &worklog)
+	})
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No running timer on this task"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop timer: " + err.Error()})
+		return
+	}
+	recordAudit(c, "worklog.create", "worklog", worklog.ID, task.ProjectID, nil, &worklog)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Таймер остановлен, время записано", "Worklog": worklog})
+}
+
+// @Summary Запущенный таймер
+// @Description Возвращает запущенный таймер текущего пользователя (Timer равен null, если таймер не запущен)
+// @Tags Учёт времени
+// @Produce json
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Таймер"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/timer [get]
+func getUserTimer(c *gin.Context) {
+	ctx := c.Request.Context()
+	timer, err := store.Timers.GetForUser(ctx, c.GetUint("id"))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusOK, gin.H{"Timer": nil})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	task, err := store.Tasks.GetByID(ctx, timer.TaskID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Timer": newTimerView(timer, task)})
+}
+
+// @Summary Отчёт о затраченном времени
+// @Description Возвращает время, затраченное на задачи проекта за период: по пользователям (с разбивкой по задачам) и по задачам (с оценкой). Доступно мейнтейнерам и владельцам проекта.
+// @Tags Учёт времени
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param from query string false "Первый день периода (YYYY-MM-DD)"
+// @Param to query string false "Последний день периода (YYYY-MM-DD)"
+// @Param user query string false "Имя пользователя"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Отчёт"
+// @Failure 400 {object} map[string]string "Некорректные параметры"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект или пользователь не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/timesheet [get]
+func getProjectTimesheet(c *gin.Context) {
+	// Проект :id (найден в permissionMiddleware)
+	project := c.MustGet("project").(*Project)
+
+	filter := TimesheetFilter{From: c.Query("from"), To: c.Query("to")}
+	for _, p := range []struct{ name, value string }{{"from", filter.From}, {"to", filter.To}} {
+		if p.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", p.value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + p.name + " format, expected YYYY-MM-DD"})
+			return
+		}
+	}
+	if filter.From != "" && filter.To != "" && filter.From > filter.To {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be later than to"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if username := strings.TrimSpace(c.Query("user")); username != "" {
+		user, err := store.Users.GetByUsername(ctx, username)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		filter.UserID = user.ID
+	}
+
+	rows, err := store.Worklogs.Timesheet(ctx, project.ID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Строки отчёта упорядочены по пользователю, поэтому время пользователя идёт подряд
+	users := []timesheetUser{}
+	tasks := []timesheetTask{}
+	taskIndex := make(map[uint]int)
+	total := 0
+	for _, row := range rows {
+		if n := len(users); n == 0 || users[n-1].Username != row.Username {
+			users = append(users, timesheetUser{UserID: row.UserID, Username: row.Username})
+		}
+		user := &users[len(users)-1]
+		user.Minutes += row.Minutes
+		user.Tasks = append(user.Tasks, timesheetTask{TaskID: row.TaskID, Title: row.Title, EstimateMinutes: row.EstimateMinutes, Minutes: row.Minutes})
+
+		i, ok := taskIndex[row.TaskID]
+		if !ok {
+			i = len(tasks)
+			taskIndex[row.TaskID] = i
+			tasks = append(tasks, timesheetTask{TaskID: row.TaskID, Title: row.Title, EstimateMinutes: row.EstimateMinutes})
+		}
+		tasks[i].Minutes += row.Minutes
+		total += row.Minutes
+	}
+	slices.SortFunc(tasks, func(a, b timesheetTask) int { return cmp.Compare(a.TaskID, b.TaskID) })
+
+	c.JSON(http.StatusOK, gin.H{"From": filter.From, "To": filter.To, "TotalMinutes": total, "Users": users, "Tasks": tasks})
+}
@@ -0,0 +1,203 @@
+package GoAPIManager
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Запись о затраченном времени на задачу; возвращает её ID
+func (s *testServer) worklog(token string, projectID, taskID uint, minutes int, date string) uint {
+	s.t.Helper()
+	out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks/%d/worklogs", projectID, taskID), token, gin.H{"duration_minutes": minutes, "date": date})
+	return uint(field(s.t, out, "Worklog", "id").(float64))
+}
+
+func TestTimers(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.user("alice")
+	bob := s.user("bobby")
+	viewer := s.user("viewer")
+	projectID := s.project(owner, "Alpha")
+	s.addMember(owner, projectID, "bobby", ProjectRoleMember)
+	s.addMember(owner, projectID, "viewer", ProjectRoleViewer)
+	first := s.task(owner, projectID, "First")
+	second := s.task(owner, projectID, "Second")
+	timer := func(taskID uint, action string) string {
+		return fmt.Sprintf("/projects/%d/tasks/%d/timer/%s", projectID, taskID, action)
+	}
+
+	if out := s.expect(http.StatusOK, http.MethodGet, "/user/timer", bob, nil); out["Timer"] != nil {
+		t.Errorf("timer before start = %v", out["Timer"])
+	}
+	s.expect(http.StatusForbidden, http.MethodPost, timer(first, "start"), viewer, nil)
+	s.expect(http.StatusCreated, http.MethodPost, timer(first, "start"), bob, nil)
+	if got := field(t, s.expect(http.StatusOK, http.MethodGet, "/user/timer", bob, nil), "Timer", "task_id"); got != float64(first) {
+		t.Errorf("running timer task = %v, want %d", got, first)
+	}
+
+	// Второй таймер не запускается, пока работает первый
+	out := s.expect(http.StatusConflict, http.MethodPost, timer(second, "start"), bob, nil)
+	if out["TimerTaskID"] != float64(first) {
+		t.Errorf("TimerTaskID = %v, want %d", out["TimerTaskID"], first)
+	}
+	s.expect(http.StatusNotFound, http.MethodPost, timer(second, "stop"), bob, nil)
+
+	// Таймеры разных пользователей независимы
+	s.expect(http.StatusCreated, http.MethodPost, timer(second, "start"), owner, nil)
+
+	// Минуты округляются вверх, время записывается на день запуска
+	started := time.Now().Add(-90*time.Minute - 30*time.Second)
+	if err := db.Model(&Timer{}).Where("task_id = ?", first).Update("started_at", started).Error; err != nil {
+		t.Fatal(err)
+	}
+	out = s.expect(http.StatusOK, http.MethodPost, timer(first, "stop"), bob, gin.H{"note": "  review  "})
+	if got := field(t, out, "Worklog", "duration_minutes"); got != float64(91) {
+		t.Errorf("duration = %v, want 91", got)
+	}
+	if got := field(t, out, "Worklog", "date"); got != started.UTC().Format("2006-01-02") {
+		t.Errorf("date = %v, want %s", got, started.UTC().Format("2006-01-02"))
+	}
+	if got := field(t, out, "Worklog", "note"); got != "review" {
+		t.Errorf("note = %q, want %q", got, "review")
+	}
+	if out := s.expect(http.StatusOK, http.MethodGet, "/user/timer", bob, nil); out["Timer"] != nil {
+		t.Errorf("timer after stop = %v", out["Timer"])
+	}
+	s.expect(http.StatusNotFound, http.MethodPost, timer(first, "stop"), bob, nil)
+
+	// Таймер дольше суток записывает не больше 1440 минут; тело запроса необязательно
+	if err := db.Model(&Timer{}).Where("task_id = ?", second).Update("started_at", time.Now().Add(-30*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	out = s.expect(http.StatusOK, http.MethodPost, timer(second, "stop"), owner, nil)
+	if got := field(t, out, "Worklog", "duration_minutes"); got != float64(maxWorklogMinutes) {
+		t.Errorf("duration = %v, want %d", got, maxWorklogMinutes)
+	}
+
+	// После остановки можно запустить новый таймер
+	s.expect(http.StatusCreated, http.MethodPost, timer(second, "start"), bob, nil)
+}
+
+func TestWorklogValidation(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.user("alice")
+	bob := s.user("bobby")
+	carol := s.user("carol")
+	projectID := s.project(owner, "Alpha")
+	s.addMember(owner, projectID, "bobby", ProjectRoleMember)
+	s.addMember(owner, projectID, "carol", ProjectRoleMember)
+	taskID := s.task(owner, projectID, "First")
+	otherID := s.task(owner, projectID, "Other")
+	path := fmt.Sprintf("/projects/%d/tasks/%d/worklogs", projectID, taskID)
+
+	for name, body := range map[string]gin.H{
+		"zero duration":     {"duration_minutes": 0},
+		"negative duration": {"duration_minutes": -5},
+		"over a day":        {"duration_minutes": maxWorklogMinutes + 1},
+		"invalid date":      {"duration_minutes": 30, "date": "2026-13-01"},
+		"wrong date format": {"duration_minutes": 30, "date": "01.02.2026"},
+		"long note":         {"duration_minutes": 30, "note": strings.Repeat("я", maxWorklogNoteLength+1)},
+	} {
+		if w := s.request(http.MethodPost, path, bob, body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want %d: %s", name, w.Code, http.StatusBadRequest, w.Body)
+		}
+	}
+
+	// Границы допустимы, дата по умолчанию — сегодня (UTC)
+	s.expect(http.StatusCreated, http.MethodPost, path, bob, gin.H{"duration_minutes": maxWorklogMinutes, "note": strings.Repeat("я", maxWorklogNoteLength)})
+	out := s.expect(http.StatusCreated, http.MethodPost, path, bob, gin.H{"duration_minutes": 1})
+	if got := field(t, out, "Worklog", "date"); got != time.Now().UTC().Format("2006-01-02") {
+		t.Errorf("default date = %v", got)
+	}
+	own := uint(field(t, out, "Worklog", "id").(float64))
+
+	out = s.expect(http.StatusOK, http.MethodGet, path, carol, nil)
+	if out["TotalMinutes"] != float64(maxWorklogMinutes+1) {
+		t.Errorf("TotalMinutes = %v, want %d", out["TotalMinutes"], maxWorklogMinutes+1)
+	}
+
+	// Чужую запись удаляет только мейнтейнер или владелец, запись другой задачи не найдена
+	worklog := fmt.Sprintf("%s/%d", path, own)
+	s.expect(http.StatusNotFound, http.MethodDelete, fmt.Sprintf("/projects/%d/tasks/%d/worklogs/%d", projectID, otherID, own), bob, nil)
+	s.expect(http.StatusForbidden, http.MethodDelete, worklog, carol, nil)
+	s.expect(http.StatusOK, http.MethodDelete, worklog, bob, nil)
+	s.expect(http.StatusNotFound, http.MethodDelete, worklog, bob, nil)
+	s.expect(http.StatusOK, http.MethodDelete, fmt.Sprintf("%s/%d", path, s.worklog(bob, projectID, taskID, 10, "")), owner, nil)
+}
+
+func TestProjectTimesheet(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.user("alice")
+	bob := s.user("bobby")
+	projectID := s.project(owner, "Alpha")
+	s.addMember(owner, projectID, "bobby", ProjectRoleMember)
+	first := s.task(owner, projectID, "First")
+	out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), owner, gin.H{
+		"title": "Second", "priority": "Low", "status": "In_Line", "estimate_minutes": 120,
+		"deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+	})
+	second := uint(field(t, out, "Task", "ID").(float64))
+
+	// Время другого проекта в отчёт не попадает
+	other := s.project(owner, "Beta")
+	s.worklog(owner, other, s.task(owner, other, "Foreign"), 500, "2026-03-02")
+
+	s.worklog(bob, projectID, first, 30, "2026-03-01")
+	s.worklog(bob, projectID, first, 45, "2026-03-02")
+	s.worklog(bob, projectID, second, 60, "2026-03-03")
+	s.worklog(owner, projectID, second, 90, "2026-03-02")
+	s.worklog(owner, projectID, first, 15, "2026-02-28")
+
+	path := fmt.Sprintf("/projects/%d/timesheet", projectID)
+	type task struct {
+		id       uint
+		minutes  float64
+		estimate interface{}
+	}
+	check := func(name, query string, total float64, users map[string]float64, tasks []task) {
+		t.Helper()
+		out := s.expect(http.StatusOK, http.MethodGet, path+query, owner, nil)
+		if out["TotalMinutes"] != total {
+			t.Errorf("%s: TotalMinutes = %v, want %v", name, out["TotalMinutes"], total)
+		}
+		gotUsers := map[string]float64{}
+		for _, u := range out["Users"].([]interface{}) {
+			u := u.(map[string]interface{})
+			gotUsers[u["username"].(string)] = u["minutes"].(float64)
+		}
+		if fmt.Sprint(gotUsers) != fmt.Sprint(users) {
+			t.Errorf("%s: users = %v, want %v", name, gotUsers, users)
+		}
+		gotTasks := out["Tasks"].([]interface{})
+		if len(gotTasks) != len(tasks) {
+			t.Fatalf("%s: tasks = %v, want %v", name, gotTasks, tasks)
+		}
+		for i, want := range tasks {
+			got := gotTasks[i].(map[string]interface{})
+			if got["task_id"] != float64(want.id) || got["minutes"] != want.minutes || got["estimate_minutes"] != want.estimate {
+				t.Errorf("%s: task %d = %v, want %+v", name, i, got, want)
+			}
+		}
+	}
+
+	check("all", "", 240, map[string]float64{"alice": 105, "bobby": 135}, []task{{first, 90, nil}, {second, 150, float64(120)}})
+	check("period", "?from=2026-03-01&to=2026-03-02", 165, map[string]float64{"alice": 90, "bobby": 75}, []task{{first, 75, nil}, {second, 90, float64(120)}})
+	check("user", "?user=bobby", 135, map[string]float64{"bobby": 135}, []task{{first, 75, nil}, {second, 60, float64(120)}})
+	check("empty", "?from=2027-01-01", 0, map[string]float64{}, []task{})
+
+	// Пользователь с разбивкой по задачам
+	out = s.expect(http.StatusOK, http.MethodGet, path+"?user=alice", owner, nil)
+	if tasks := field(t, out, "Users").([]interface{})[0].(map[string]interface{})["tasks"].([]interface{}); len(tasks) != 2 {
+		t.Errorf("alice tasks = %v", tasks)
+	}
+
+	s.expect(http.StatusBadRequest, http.MethodGet, path+"?from=01.03.2026", owner, nil)
+	s.expect(http.StatusBadRequest, http.MethodGet, path+"?from=2026-03-02&to=2026-03-01", owner, nil)
+	s.expect(http.StatusNotFound, http.MethodGet, path+"?user=nobody", owner, nil)
+	s.expect(http.StatusForbidden, http.MethodGet, path, bob, nil)
+}
@@ -0,0 +1,606 @@
+package GoAPIManager
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Параметры TOTP (RFC 6238), которые понимают все приложения-аутентификаторы
+const (
+	totpIssuer = "GAPi"
+	totpDigits = 6
+	totpModulo = 1_000_000 // 10^totpDigits
+	totpPeriod = 30
+	// Допустимое расхождение часов: на шаг раньше или позже
+	totpSkew = 1
+)
+
+// Сколько кодов восстановления выдаётся при включении 2FA
+const recoveryCodeCount = 10
+
+// Незавершённый вход действует несколько минут и допускает несколько попыток ввода кода
+const (
+	loginChallengeTTL         = 5 * time.Minute
+	loginChallengeMaxAttempts = 5
+)
+
+// Код восстановления 2FA. Сам код показывается один раз, в базе хранится только его SHA-256.
+type RecoveryCode struct {
+	ID       uint   `gorm:"primaryKey"`
+	UserID   uint   `gorm:"not null;index"`
+	CodeHash string `gorm:"not null"`
+	UsedAt   *time.Time
+}
+
+// Незавершённый вход: пароль проверен, ожидается код второго фактора (POST /login/2fa)
+type LoginChallenge struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;index"`
+	TokenHash  string    `gorm:"not null;unique"`
+	DeviceName string    `gorm:"not null"`
+	Attempts   int       `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	UsedAt     *time.Time
+}
+
+// Тело запроса с кодом из приложения-аутентификатора
+type twoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// Тело запроса на отключение 2FA
+type disableTwoFactorRequest struct {
+	Password string `json:"password"`
+	// Код из приложения или код восстановления
+	Code string `json:"code"`
+}
+
+// Тело запроса на завершение входа
+type loginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	// Код из приложения или код восстановления
+	Code string `json:"code"`
+}
+
+var errTwoFactorCodeInvalid = errors.New("invalid two-factor code")
+
+// Новый секрет TOTP (160 бит, base32 без выравнивания, как в otpauth-ссылках)
+func newTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
+
+// Код TOTP для шага step (RFC 4226, HMAC-SHA1)
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%totpModulo)
+}
+
+// Проверка кода TOTP на момент now. Возвращает шаг, которому соответствует код.
+func verifyTOTP(secret, code string, now time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return 0, false
+	}
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if hmac.Equal([]byte(totpCode(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// Ссылка otpauth:// для приложения-аутентификатора (обычно показывается QR-кодом)
+func totpURI(username, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + url.PathEscape(totpIssuer+":"+username) + "?" + query.Encode()
+}
+
+// Код восстановления приводится к одному виду: без пробелов и дефисов, в нижнем регистре
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// Новые коды восстановления пользователя (предыдущие перестают действовать)
+func issueRecoveryCodes(ctx context.Context, codes RecoveryCodeRepository, userID uint) ([]string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	plain := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range plain {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(b))
+		plain[i] = code[:4] + "-" + code[4:]
+		hashes[i] = hashToken(normalizeRecoveryCode(code))
+	}
+	if err := codes.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+// Проверка второго фактора: код из приложения (6 цифр) или код восстановления.
+// Каждый код действует один раз. recovery — был ли использован код восстановления.
+func verifySecondFactor(ctx context.Context, s *Store, user *User, code string) (recovery bool, err error) {
+	code = strings.TrimSpace(code)
+	if user.TOTPEnabledAt == nil || code == "" {
+		return false, errTwoFactorCodeInvalid
+	}
+
+	if step, ok := verifyTOTP(user.TOTPSecret, code, time.Now()); ok {
+		fresh, err := s.Users.UseTOTPStep(ctx, user.ID, step)
+		if err != nil {
+			return false, err
+		}
+		if !fresh {
+			return false, errTwoFactorCodeInvalid
+		}
+		user.TOTPLastStep = step
+		return false, nil
+	}
+
+	used, err := s.RecoveryCodes.Use(ctx, user.ID, hashToken(normalizeRecoveryCode(code)), time.Now())
+	if err != nil {
+		return false, err
+	}
+	if !used {
+		return false, errTwoFactorCodeInvalid
+	}
+	return true, nil
+}
+
+// Отключение 2FA пользователя вместе с кодами восстановления
+func clearTwoFactor(ctx context.Context, s *Store, user *User) error {
+	user.TOTPSecret = ""
+	user.TOTPEnabledAt = nil
+	user.TOTPLastStep = 0
+	if err := s.Users.Update(ctx, user); err != nil {
+		return err
+	}
+	return s.RecoveryCodes.DeleteForUser(ctx, user.ID)
+}
+
+// Обязательна ли 2FA для пользователя с этой глобальной ролью
+func twoFactorRequired(ctx context.Context, role string) (bool, error) {
+	if role != RoleAdmin {
+		return false, nil
+	}
+	settings, err := loadSecuritySettings(ctx)
+	return settings.RequireAdmin2FA, err
+}
+
+// Маршруты, доступные без 2FA, когда она обязательна: только её настройка и выход
+var twoFactorSetupRoutes = map[string]bool{
+	"/user/2fa":         true,
+	"/user/2fa/enroll":  true,
+	"/user/2fa/confirm": true,
+	"/logout":           true,
+}
+
+// Проверка обязательной 2FA: пользователь, которому она нужна, но не включена,
+// может только настроить её
+func checkTwoFactorPolicy(c *gin.Context) bool {
+	if twoFactorSetupRoutes[c.FullPath()] {
+		return true
+	}
+
+	ctx := c.Request.Context()
+	required, err := twoFactorRequired(ctx, c.GetString("role"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		c.Abort()
+		return false
+	}
+	if !required {
+		return true
+	}
+
+	user, err := store.Users.GetByID(ctx, c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		c.Abort()
+		return false
+	}
+	if user.TOTPEnabledAt == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication required", "details": "enable it via /user/2fa/enroll"})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// Создание незавершённого входа, возвращает токен для POST /login/2fa
+func createLoginChallenge(ctx context.Context, user *User, deviceName string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	err := store.Challenges.Create(ctx, &LoginChallenge{
+		UserID:     user.ID,
+		TokenHash:  hashToken(token),
+		DeviceName: deviceName,
+		ExpiresAt:  time.Now().Add(loginChallengeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// @Summary Второй шаг входа
+// @Description Завершает вход пользователя с включённой 2FA: токен из ответа /login и код из приложения-аутентификатора (или код восстановления). Токен действует 5 минут и допускает 5 попыток ввода кода.
+// @Tags Аутентификация
+// @Accept json
+// @Produce json
+// @Param input body loginTwoFactorRequest true "Токен из ответа /login и код"
+// @Success 200 {object} map[string]interface{} "Успешная аутентификация. Возвращает access- и refresh-токен"
+// @Failure 400 {object} map[string]string "Некорректные входные данные"
+// @Failure 401 {object} map[string]string "Неверный код или вход нужно начать заново"
+// @Failure 403 {object} map[string]string "Учётная запись заблокирована"
+// @Failure 429 {object} map[string]interface{} "Слишком много неудачных попыток, вход временно заблокирован (см. заголовок Retry-After)"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /login/2fa [post]
+func loginTwoFactor(c *gin.Context) {
+	var req loginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.ChallengeToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	challenge, err := store.Challenges.GetByHash(ctx, hashToken(req.ChallengeToken))
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if err != nil || challenge.UsedAt != nil || !time.Now().Before(challenge.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge, log in again"})
+		return
+	}
+
+	user, err := store.Users.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		return
+	}
+
+	// Неверные коды учитываются в блокировке входа наравне с неверными паролями
+	if !checkLoginLockout(c, user.Username) {
+		return
+	}
+
+	// Попытка засчитывается до проверки кода, чтобы параллельные запросы не обошли лимит
+	ok, err := store.Challenges.TakeAttempt(ctx, challenge.ID, loginChallengeMaxAttempts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge, log in again"})
+		return
+	}
+
+	recovery, err := verifySecondFactor(ctx, store, user, req.Code)
+	if errors.Is(err, errTwoFactorCodeInvalid) {
+		if err := recordLoginFailure(c, user.Username); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code", "AttemptsLeft": max(loginChallengeMaxAttempts-challenge.Attempts-1, 0)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Один токен — одна сессия, даже если его отправили дважды одновременно
+	if ok, err := store.Challenges.MarkUsed(ctx, challenge.ID, time.Now()); err != nil || !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge, log in again"})
+		return
+	}
+
+	if err := resetLoginFailures(ctx, user.Username); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	tokens, err := startSession(c, user, challenge.DeviceName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session", "details": err.Error()})
+		return
+	}
+	if recovery {
+		left, err := store.RecoveryCodes.CountUnused(ctx, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+			return
+		}
+		tokens["RecoveryCodesLeft"] = left
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Состояние двухфакторной аутентификации
+// @Description Включена ли 2FA у текущего пользователя, сколько осталось кодов восстановления и обязательна ли 2FA для него
+// @Tags Двухфакторная аутентификация
+// @Produce json
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Success 200 {object} map[string]interface{} "Состояние 2FA"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /user/2fa [get]
+func getTwoFactorStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := store.Users.GetByID(ctx, c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	left, err := store.RecoveryCodes.CountUnused(ctx, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	required, err := twoFactorRequired(ctx, user.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"Enabled": user.TOTPEnabledAt != nil, "EnabledAt": user.TOTPEnabledAt, "RecoveryCodesLeft": left, "Required": required})
+}
+
+// @Summary Подключение двухфакторной аутентификации
+// @Description Создаёт секрет TOTP и возвращает ссылку otpauth:// для приложения-аутентификатора (Google Authenticator, Aegis, 1Password и т.п.). 2FA включается после подтверждения кодом через /user/2fa/confirm; повторный вызов до подтверждения создаёт новый секрет.
+// @Tags Двухфакторная аутентификация
+// @Produce json
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Success 200 {object} map[string]interface{} "Секрет и ссылка otpauth://"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 409 {object} map[string]string "2FA уже включена"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /user/2fa/enroll [post]
+func enrollTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := store.Users.GetByID(ctx, c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if user.TOTPEnabledAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication already enabled"})
+		return
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate secret"})
+		return
+	}
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	if err := store.Users.Update(ctx, user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Добавьте ключ в приложение-аутентификатор и подтвердите кодом через /user/2fa/confirm", "Secret": secret, "OtpauthURI": totpURI(user.Username, secret)})
+}
+
+// @Summary Подтверждение двухфакторной аутентификации
+// @Description Включает 2FA по коду из приложения-аутентификатора и возвращает коды восстановления. Коды показываются один раз, каждый действует однократно вместо кода из приложения.
+// @Tags Двухфакторная аутентификация
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Param request body twoFactorCodeRequest true "Код из приложения"
+// @Success 200 {object} map[string]interface{} "2FA включена, возвращаются коды восстановления"
+// @Failure 400 {object} map[string]string "Неверный код или 2FA не подключалась"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 409 {object} map[string]string "2FA уже включена"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /user/2fa/confirm [post]
+func confirmTwoFactor(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := store.Users.GetByID(ctx, c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if user.TOTPEnabledAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication already enabled"})
+		return
+	}
+	if user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start enrollment via /user/2fa/enroll first"})
+		return
+	}
+	step, ok := verifyTOTP(user.TOTPSecret, strings.TrimSpace(req.Code), time.Now())
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+	user.TOTPLastStep = step
+	var codes []string
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if err := tx.Users.Update(ctx, user); err != nil {
+			return err
+		}
+		codes, err = issueRecoveryCodes(ctx, tx.RecoveryCodes, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Двухфакторная аутентификация включена. Сохраните коды восстановления: повторно они не показываются", "RecoveryCodes": codes})
+}
+
+// @Summary Новые коды восстановления
+// @Description Выдаёт новый набор кодов восстановления по коду из приложения, прежние коды перестают действовать
+// @Tags Двухфакторная аутентификация
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Param request body twoFactorCodeRequest true "Код из приложения"
+// @Success 200 {object} map[string]interface{} "Новые коды восстановления"
+// @Failure 400 {object} map[string]string "Неверный код или 2FA не включена"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /user/2fa/recovery-codes [post]
+func regenerateRecoveryCodes(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := store.Users.GetByID(ctx, c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if user.TOTPEnabledAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	// Нужен именно код из приложения: кодом восстановления новые коды не получить
+	step, ok := verifyTOTP(user.TOTPSecret, strings.TrimSpace(req.Code), time.Now())
+	if ok {
+		ok, err = store.Users.UseTOTPStep(ctx, user.ID, step)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	codes, err := issueRecoveryCodes(ctx, store.RecoveryCodes, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Новые коды восстановления созданы, прежние больше не действуют", "RecoveryCodes": codes})
+}
+
+// @Summary Отключение двухфакторной аутентификации
+// @Description Отключает 2FA текущего пользователя. Нужны пароль и код из приложения (или код восстановления). Если 2FA обязательна для роли пользователя, отключить её нельзя.
+// @Tags Двухфакторная аутентификация
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer токен (JWT, полученный при входе)"
+// @Param request body disableTwoFactorRequest true "Пароль и код"
+// @Success 200 {object} map[string]string "2FA отключена"
+// @Failure 400 {object} map[string]string "Неверный код или 2FA не включена"
+// @Failure 401 {object} map[string]string "Неверный пароль"
+// @Failure 403 {object} map[string]string "2FA обязательна"
+// @Failure 500 {object} map[string]string "Ошибка сервера"
+// @Router /user/2fa [delete]
+func disableTwoFactor(c *gin.Context) {
+	var req disableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := store.Users.GetByID(ctx, c.GetUint("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if user.TOTPEnabledAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
+		return
+	}
+	required, err := twoFactorRequired(ctx, user.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	if required {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication is required for your role"})
+		return
+	}
+
+	err = store.Transaction(ctx, func(tx *Store) error {
+		if _, err := verifySecondFactor(ctx, tx, user, req.Code); err != nil {
+			return err
+		}
+		return clearTwoFactor(ctx, tx, user)
+	})
+	if errors.Is(err, errTwoFactorCodeInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Двухфакторная аутентификация отключена"})
+}
@@ -0,0 +1,106 @@
+package GoAPIManager
+
+import (
+	"encoding/base32"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Код из приложения-аутентификатора для секрета secret, смещённый на shift шагов
+func testTOTP(t *testing.T, secret string, shift int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return totpCode(key, time.Now().Unix()/totpPeriod+shift)
+}
+
+// Подключение 2FA; возвращает секрет TOTP
+func (s *testServer) enableTwoFactor(token string) string {
+	s.t.Helper()
+	secret, _ := s.expect(http.StatusOK, http.MethodPost, "/user/2fa/enroll", token, nil)["Secret"].(string)
+	s.expect(http.StatusOK, http.MethodPost, "/user/2fa/confirm", token, gin.H{"code": testTOTP(s.t, secret, 0)})
+	return secret
+}
+
+// Первый шаг входа с 2FA; возвращает токен для /login/2fa
+func (s *testServer) loginChallenge(username string) string {
+	s.t.Helper()
+	out := s.expect(http.StatusOK, http.MethodPost, "/login", "", gin.H{"username": username, "password": testPassword})
+	challenge, _ := out["ChallengeToken"].(string)
+	if challenge == "" {
+		s.t.Fatalf("no challenge token in %v", out)
+	}
+	return challenge
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	s := newTestServer(t)
+	secret := s.enableTwoFactor(s.user("alice"))
+	challenge := s.loginChallenge("alice")
+
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/login/2fa", "", gin.H{"challenge_token": challenge, "code": "wrong"})
+	out := s.expect(http.StatusOK, http.MethodPost, "/login/2fa", "", gin.H{"challenge_token": challenge, "code": testTOTP(t, secret, 1)})
+	s.expect(http.StatusOK, http.MethodGet, "/user/sessions", accessToken(t, out), nil)
+
+	// Токен завершённого входа повторно не принимается
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/login/2fa", "", gin.H{"challenge_token": challenge, "code": testTOTP(t, secret, 1)})
+}
+
+func TestTwoFactorChallengeAttemptLimit(t *testing.T) {
+	s := newTestServer(t)
+	secret := s.enableTwoFactor(s.user("alice"))
+	cfg.Auth.LockoutThreshold = 1000
+	cfg.Auth.IPLockoutThreshold = 1000
+	challenge := s.loginChallenge("alice")
+
+	// Параллельные запросы не получают больше попыток, чем разрешено
+	var wg sync.WaitGroup
+	codes := make(chan int, 3*loginChallengeMaxAttempts)
+	for range cap(codes) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := s.request(http.MethodPost, "/login/2fa", "", gin.H{"challenge_token": challenge, "code": "wrong"})
+			codes <- w.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusUnauthorized {
+			t.Errorf("wrong code: status %d, want %d", code, http.StatusUnauthorized)
+		}
+	}
+	var attempts int
+	if err := db.Model(&LoginChallenge{}).Where("token_hash = ?", hashToken(challenge)).Pluck("attempts", &attempts).Error; err != nil {
+		t.Fatal(err)
+	}
+	if attempts != loginChallengeMaxAttempts {
+		t.Errorf("attempts = %d, want %d", attempts, loginChallengeMaxAttempts)
+	}
+
+	// После исчерпания попыток не принимается и верный код
+	s.expect(http.StatusUnauthorized, http.MethodPost, "/login/2fa", "", gin.H{"challenge_token": challenge, "code": testTOTP(t, secret, 1)})
+}
+
+func TestTwoFactorFailuresLockAccount(t *testing.T) {
+	s := newTestServer(t)
+	secret := s.enableTwoFactor(s.user("alice"))
+	spare := s.loginChallenge("alice")
+
+	// Новый вход с верным паролем не сбрасывает счётчик неверных кодов
+	for range cfg.Auth.LockoutThreshold {
+		challenge := s.loginChallenge("alice")
+		s.expect(http.StatusUnauthorized, http.MethodPost, "/login/2fa", "", gin.H{"challenge_token": challenge, "code": "wrong"})
+	}
+
+	s.expect(http.StatusTooManyRequests, http.MethodPost, "/login", "", gin.H{"username": "alice", "password": testPassword})
+	// Выданный до блокировки токен тоже не позволяет продолжить вход
+	s.expect(http.StatusTooManyRequests, http.MethodPost, "/login/2fa", "", gin.H{"challenge_token": spare, "code": testTOTP(t, secret, 1)})
+}
@@ -0,0 +1,374 @@
+package GoAPIManager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Категории статусов задач: задачи в статусах категории done считаются завершёнными
+const (
+	StatusCategoryTodo       = "todo"
+	StatusCategoryInProgress = "in_progress"
+	StatusCategoryDone       = "done"
+)
+
+var statusCategories = []string{StatusCategoryTodo, StatusCategoryInProgress, StatusCategoryDone}
+
+// Роли, которые может требовать переход между статусами (пустая строка — без требования,
+// достаточно права task.update)
+var transitionRoles = []string{ProjectRoleMember, ProjectRoleMaintainer, ProjectRoleOwner}
+
+// Ограничения процесса проекта
+const maxWorkflowStatuses = 20
+
+// Имя статуса: буквы, цифры, пробел, '_', '-' и '.', до 50 символов
+var statusNamePattern = regexp.MustCompile(`^[\p{L}\p{N}_.\- ]{1,50}$`)
+
+// Статус задачи в процессе проекта
+type WorkflowStatus struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ProjectID uint   `gorm:"not null" json:"-"`
+	Name      string `gorm:"not null" json:"name"`
+	Category  string `gorm:"not null" json:"category"`
+	// Порядок статуса в процессе (с нуля)
+	Position int `gorm:"not null" json:"position"`
+}
+
+func (WorkflowStatus) TableName() string {
+	return "workflow_statuses"
+}
+
+// Разрешённый переход между статусами. В API статусы задаются именами.
+type WorkflowTransition struct {
+	ID           uint   `gorm:"primaryKey" json:"-"`
+	ProjectID    uint   `gorm:"not null" json:"-"`
+	FromStatusID uint   `gorm:"not null" json:"-"`
+	ToStatusID   uint   `gorm:"not null" json:"-"`
+	From         string `gorm:"-" json:"from"`
+	To           string `gorm:"-" json:"to"`
+	// Минимальная роль в проекте для перехода (пустая строка — без требования)
+	Role string `gorm:"not null" json:"role"`
+}
+
+func (WorkflowTransition) TableName() string {
+	return "workflow_transitions"
+}
+
+// Рабочий процесс проекта: статусы задач по порядку и разрешённые переходы между ними
+type Workflow struct {
+	ProjectID   uint                 `json:"project_id"`
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// Тело запроса на замену процесса проекта. Статус с id переименовывается, статус без id
+// сохраняется, если в процессе есть статус с тем же именем, иначе создаётся; порядок статусов —
+// порядок в списке. Задачи удаляемых статусов переводятся в статусы из replacements
+// (имя удаляемого статуса → имя статуса нового процесса).
+type workflowRequest struct {
+	Statuses     []WorkflowStatus     `json:"statuses"`
+	Transitions  []WorkflowTransition `json:"transitions"`
+	Replacements map[string]string    `json:"replacements"`
+}
+
+// Процесс по умолчанию: прежние фиксированные статусы и любые переходы между ними
+func defaultWorkflow(projectID uint) *Workflow {
+	workflow := &Workflow{
+		ProjectID: projectID,
+		Statuses: []WorkflowStatus{
+			{Name: "In_Line", Category: StatusCategoryTodo},
+			{Name: "In_Progress", Category: StatusCategoryInProgress},
+			{Name: "Done", Category: StatusCategoryDone},
+		},
+	}
+	for _, from := range workflow.Statuses {
+		for _, to := range workflow.Statuses {
+			if from.Name != to.Name {
+				workflow.Transitions = append(workflow.Transitions, WorkflowTransition{From: from.Name, To: to.Name})
+			}
+		}
+	}
+	return workflow
+}
+
+// Статус процесса по имени (nil — статуса нет)
+func (w *Workflow) status(name string) *WorkflowStatus {
+	for i := range w.Statuses {
+		if w.Statuses[i].Name == name {
+			return &w.Statuses[i]
+		}
+	}
+	return nil
+}
+
+// Категория статуса (пустая строка — статуса нет в процессе)
+func (w *Workflow) category(name string) string {
+	if s := w.status(name); s != nil {
+		return s.Category
+	}
+	return ""
+}
+
+// Завершена ли задача в статусе name
+func (w *Workflow) isDone(name string) bool {
+	return w.category(name) == StatusCategoryDone
+}
+
+// Статус новых задач, для которых статус не задан: первый статус категории todo
+func (w *Workflow) initialStatus() string {
+	for _, s := range w.Statuses {
+		if s.Category == StatusCategoryTodo {
+			return s.Name
+		}
+	}
+	if len(w.Statuses) > 0 {
+		return w.Statuses[0].Name
+	}
+	return ""
+}
+
+// Переход между статусами (nil — переход не разрешён)
+func (w *Workflow) transition(from, to string) *WorkflowTransition {
+	for i := range w.Transitions {
+		if w.Transitions[i].From == from && w.Transitions[i].To == to {
+			return &w.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// Имена статусов по порядку
+func (w *Workflow) statusNames() []string {
+	names := make([]string, len(w.Statuses))
+	for i, s := range w.Statuses {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Статусы, в которые можно перейти из статуса from
+func (w *Workflow) nextStatuses(from string) []string {
+	names := []string{}
+	for _, t := range w.Transitions {
+		if t.From == from {
+			names = append(names, t.To)
+		}
+	}
+	return names
+}
+
+// Ответ 400 на статус, которого нет в процессе проекта
+func respondInvalidStatus(c *gin.Context, workflow *Workflow) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, allowed values are: " + strings.Join(workflow.statusNames(), ", ")})
+}
+
+// Проверка смены статуса задачи from → to: статус to есть в процессе проекта, переход
+// разрешён, а роль пользователя не ниже требуемой переходом. Задача в статусе, которого
+// нет в процессе, может перейти в любой статус. При отказе отвечает и возвращает false.
+func checkStatusTransition(c *gin.Context, workflow *Workflow, from, to string) bool {
+	if workflow.status(to) == nil {
+		respondInvalidStatus(c, workflow)
+		return false
+	}
+	if from == to || workflow.status(from) == nil {
+		return true
+	}
+
+	t := workflow.transition(from, to)
+	if t == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":           fmt.Sprintf("Transition from %s to %s is not allowed", from, to),
+			"AllowedStatuses": workflow.nextStatuses(from),
+		})
+		return false
+	}
+	if t.Role != "" && !projectRoleAtLeast(c.GetString("project_role"), t.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied", "details": fmt.Sprintf("transition from %s to %s requires role %s", from, to, t.Role)})
+		return false
+	}
+	return true
+}
+
+// Новый процесс проекта по запросу: проверяет статусы и переходы и сопоставляет статусы
+// запроса статусам текущего процесса (у сохраняемых статусов заполняется ID)
+func newWorkflow(current *Workflow, req *workflowRequest) (*Workflow, error) {
+	if len(req.Statuses) == 0 {
+		return nil, errors.New("at least one status is required")
+	}
+	if len(req.Statuses) > maxWorkflowStatuses {
+		return nil, fmt.Errorf("a workflow can have at most %d statuses", maxWorkflowStatuses)
+	}
+
+	existing := make(map[uint]*WorkflowStatus, len(current.Statuses))
+	for i := range current.Statuses {
+		existing[current.Statuses[i].ID] = &current.Statuses[i]
+	}
+
+	// Статусы с id сопоставляются первыми, остальные — по имени среди незанятых
+	claimed := make(map[uint]bool)
+	for _, s := range req.Statuses {
+		if s.ID == 0 {
+			continue
+		}
+		if existing[s.ID] == nil || claimed[s.ID] {
+			return nil, fmt.Errorf("unknown or duplicate status id %d", s.ID)
+		}
+		claimed[s.ID] = true
+	}
+
+	workflow := &Workflow{ProjectID: current.ProjectID, Statuses: []WorkflowStatus{}, Transitions: []WorkflowTransition{}}
+	for _, s := range req.Statuses {
+		name := strings.TrimSpace(s.Name)
+		if !statusNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid status name %q: use 1-50 letters, digits, spaces, '_', '-' or '.'", s.Name)
+		}
+		if workflow.status(name) != nil {
+			return nil, fmt.Errorf("duplicate status %s", name)
+		}
+		if !slices.Contains(statusCategories, s.Category) {
+			return nil, fmt.Errorf("invalid category of status %s, allowed values are: %s", name, strings.Join(statusCategories, ", "))
+		}
+
+		id := s.ID
+		if old := current.status(name); id == 0 && old != nil && !claimed[old.ID] {
+			id = old.ID
+			claimed[id] = true
+		}
+		workflow.Statuses = append(workflow.Statuses, WorkflowStatus{ID: id, Name: name, Category: s.Category, Position: len(workflow.Statuses)})
+	}
+
+	// Задачи создаются в статусе категории todo и где-то должны завершаться
+	if !slices.ContainsFunc(workflow.Statuses, func(s WorkflowStatus) bool { return s.Category == StatusCategoryTodo }) {
+		return nil, errors.New("at least one status must be in category todo")
+	}
+	if !slices.ContainsFunc(workflow.Statuses, func(s WorkflowStatus) bool { return s.Category == StatusCategoryDone }) {
+		return nil, errors.New("at least one status must be in category done")
+	}
+
+	for _, t := range req.Transitions {
+		from, to := strings.TrimSpace(t.From), strings.TrimSpace(t.To)
+		if workflow.status(from) == nil || workflow.status(to) == nil {
+			return nil, fmt.Errorf("transition %s -> %s refers to an unknown status", t.From, t.To)
+		}
+		if from == to {
+			return nil, fmt.Errorf("transition %s -> %s must change the status", from, to)
+		}
+		if workflow.transition(from, to) != nil {
+			return nil, fmt.Errorf("duplicate transition %s -> %s", from, to)
+		}
+		if t.Role != "" && !slices.Contains(transitionRoles, t.Role) {
+			return nil, fmt.Errorf("invalid role of transition %s -> %s, allowed values are: %s", from, to, strings.Join(transitionRoles, ", "))
+		}
+		workflow.Transitions = append(workflow.Transitions, WorkflowTransition{From: from, To: to, Role: t.Role})
+	}
+	return workflow, nil
+}
+
+// @Summary Процесс проекта
+// @Description Возвращает статусы задач проекта по порядку (с категориями todo, in_progress, done) и разрешённые переходы между ними. role перехода — минимальная роль в проекте, которая может его выполнить (пустая строка — любой участник с правом task.update).
+// @Tags Процессы
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Success 200 {object} map[string]interface{} "Процесс проекта"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/workflow [get]
+func getWorkflow(c *gin.Context) {
+	// Проект :id (найден в permissionMiddleware)
+	project := c.MustGet("project").(*Project)
+
+	workflow, err := store.Workflows.Get(c.Request.Context(), project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"Workflow": workflow})
+}
+
+// @Summary Изменение процесса проекта
+// @Description Заменяет процесс проекта (право project.update). statuses — статусы по порядку: не больше 20, хотя бы один категории todo и один категории done; статус с id переименовывается вместе с задачами, статус без id сохраняется, если такое имя уже есть, иначе создаётся. Статусы, которых нет в списке, удаляются; если в них есть задачи, для каждого нужен статус в replacements (иначе 409 со списком StatusesInUse). transitions — разрешённые переходы (from, to и необязательная role: member, maintainer или owner); задачу можно перевести только по разрешённому переходу.
+// @Tags Процессы
+// @Accept json
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Param Authorization header string true "Bearer токен"
+// @Param input body workflowRequest true "Статусы, переходы и замены удаляемых статусов"
+// @Success 200 {object} map[string]interface{} "Процесс обновлён"
+// @Failure 400 {object} map[string]string "Ошибка валидации данных"
+// @Failure 403 {object} map[string]string "Недостаточно прав"
+// @Failure 404 {object} map[string]string "Проект не найден"
+// @Failure 409 {object} map[string]interface{} "В удаляемых статусах есть задачи"
+// @Failure 500 {object} map[string]string "Ошибка базы данных"
+// @Router /projects/{id}/workflow [put]
+func updateWorkflow(c *gin.Context) {
+	// Проект :id (найден в permissionMiddleware)
+	project := c.MustGet("project").(*Project)
+
+	var req workflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	current, err := store.Workflows.Get(ctx, project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+	workflow, err := newWorkflow(current, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	counts, err := store.Workflows.CountTasks(ctx, project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "details": err.Error()})
+		return
+	}
+
+	// Задачи переименованных статусов получают новое имя, задачи удаляемых — статус из replacements
+	moves := make(map[uint]string)
+	inUse := make(map[string]int64)
+	for _, old := range current.Statuses {
+		kept := slices.IndexFunc(workflow.Statuses, func(s WorkflowStatus) bool { return s.ID == old.ID })
+		switch {
+		case kept >= 0:
+			if name := workflow.Statuses[kept].Name; name != old.Name {
+				moves[old.ID] = name
+			}
+		case counts[old.Name] > 0:
+			target, ok := req.Replacements[old.Name]
+			if !ok {
+				inUse[old.Name] = counts[old.Name]
+				continue
+			}
+			if workflow.status(target) == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Replacement for status %s refers to an unknown status", old.Name)})
+				return
+			}
+			moves[old.ID] = target
+		}
+	}
+	if len(inUse) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Removed statuses have tasks, set replacements for them", "StatusesInUse": inUse})
+		return
+	}
+
+	if err := store.Workflows.Save(ctx, workflow, moves); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workflow", "details": err.Error()})
+		return
+	}
+	recordAudit(c, "workflow.update", "workflow", project.ID, project.ID, current, workflow)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Процесс проекта обновлён", "Workflow": workflow})
+}
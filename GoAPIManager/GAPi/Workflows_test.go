@@ -0,0 +1,260 @@
+package GoAPIManager
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Процесс проекта из ответа API
+func (s *testServer) workflow(token string, projectID uint) map[string]interface{} {
+	s.t.Helper()
+	return field(s.t, s.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/projects/%d/workflow", projectID), token, nil), "Workflow").(map[string]interface{})
+}
+
+// ID статуса процесса по имени
+func workflowStatusID(t *testing.T, workflow map[string]interface{}, name string) uint {
+	t.Helper()
+	for _, s := range workflow["statuses"].([]interface{}) {
+		if s := s.(map[string]interface{}); s["name"] == name {
+			return uint(s["id"].(float64))
+		}
+	}
+	t.Fatalf("no status %s in %v", name, workflow)
+	return 0
+}
+
+// Имена статусов процесса по порядку
+func workflowStatusNames(workflow map[string]interface{}) []string {
+	var names []string
+	for _, s := range workflow["statuses"].([]interface{}) {
+		names = append(names, s.(map[string]interface{})["name"].(string))
+	}
+	return names
+}
+
+// Смена статуса задачи
+func (s *testServer) setTaskStatus(token string, projectID, taskID uint, status string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	return s.request(http.MethodPut, fmt.Sprintf("/projects/%d/tasks/%d", projectID, taskID), token, gin.H{
+		"title": "Task", "priority": "High", "status": status, "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+	})
+}
+
+// Статус задачи из списка задач проекта
+func (s *testServer) taskStatus(token string, projectID, taskID uint) string {
+	s.t.Helper()
+	out := s.expect(http.StatusOK, http.MethodGet, fmt.Sprintf("/projects/%d/tasks", projectID), token, nil)
+	list, _ := out["Задачи"].([]interface{})
+	for _, item := range list {
+		if task := item.(map[string]interface{}); task["ID"] == float64(taskID) {
+			return task["status"].(string)
+		}
+	}
+	s.t.Fatalf("task %d not found in %v", taskID, out)
+	return ""
+}
+
+func TestWorkflowRenameStatusWithTasks(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	taskID := s.task(alice, projectID, "First")
+	workflow := s.workflow(alice, projectID)
+	path := fmt.Sprintf("/projects/%d/workflow", projectID)
+
+	// Статусы с id переименовываются вместе с задачами, статус без id с прежним именем сохраняется
+	inLine := workflowStatusID(t, workflow, "In_Line")
+	s.expect(http.StatusOK, http.MethodPut, path, alice, gin.H{
+		"statuses": []gin.H{
+			{"id": inLine, "name": "Backlog", "category": StatusCategoryTodo},
+			{"name": "In_Progress", "category": StatusCategoryInProgress},
+			{"name": "Done", "category": StatusCategoryDone},
+		},
+		"transitions": []gin.H{{"from": "Backlog", "to": "In_Progress"}, {"from": "In_Progress", "to": "Done"}},
+	})
+	workflow = s.workflow(alice, projectID)
+	if got := workflowStatusNames(workflow); !slices.Equal(got, []string{"Backlog", "In_Progress", "Done"}) {
+		t.Errorf("statuses = %v", got)
+	}
+	if workflowStatusID(t, workflow, "Backlog") != inLine {
+		t.Error("renamed status got a new id")
+	}
+	if got := s.taskStatus(alice, projectID, taskID); got != "Backlog" {
+		t.Errorf("task status = %s, want Backlog", got)
+	}
+
+	// Прежнего имени больше нет в процессе; новые задачи без статуса получают первый статус todo
+	if w := s.setTaskStatus(alice, projectID, taskID, "In_Line"); w.Code != http.StatusBadRequest {
+		t.Errorf("old status name: status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	s.expect(http.StatusBadRequest, http.MethodGet, fmt.Sprintf("/projects/%d/tasks?status=In_Line", projectID), alice, nil)
+	out := s.expect(http.StatusCreated, http.MethodPost, fmt.Sprintf("/projects/%d/tasks", projectID), alice, gin.H{
+		"title": "Second", "priority": "Low", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+	})
+	if got := field(t, out, "Task", "status"); got != "Backlog" {
+		t.Errorf("new task status = %v, want Backlog", got)
+	}
+
+	for name, body := range map[string]gin.H{
+		"no statuses":        {"statuses": []gin.H{}},
+		"no todo":            {"statuses": []gin.H{{"name": "Done", "category": StatusCategoryDone}}},
+		"no done":            {"statuses": []gin.H{{"name": "Backlog", "category": StatusCategoryTodo}}},
+		"bad category":       {"statuses": []gin.H{{"name": "Backlog", "category": "later"}, {"name": "Done", "category": StatusCategoryDone}}},
+		"bad name":           {"statuses": []gin.H{{"name": "Back/log", "category": StatusCategoryTodo}, {"name": "Done", "category": StatusCategoryDone}}},
+		"duplicate name":     {"statuses": []gin.H{{"name": "Done", "category": StatusCategoryTodo}, {"name": "Done", "category": StatusCategoryDone}}},
+		"unknown status id":  {"statuses": []gin.H{{"id": 9999, "name": "Backlog", "category": StatusCategoryTodo}, {"name": "Done", "category": StatusCategoryDone}}},
+		"unknown transition": {"statuses": []gin.H{{"name": "Backlog", "category": StatusCategoryTodo}, {"name": "Done", "category": StatusCategoryDone}}, "transitions": []gin.H{{"from": "Backlog", "to": "Review"}}},
+		"bad role": {"statuses": []gin.H{{"name": "Backlog", "category": StatusCategoryTodo}, {"name": "Done", "category": StatusCategoryDone}},
+			"transitions": []gin.H{{"from": "Backlog", "to": "Done", "role": "admin"}}},
+	} {
+		if w := s.request(http.MethodPut, path, alice, body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want %d: %s", name, w.Code, http.StatusBadRequest, w.Body)
+		}
+	}
+}
+
+func TestWorkflowStatusesInUse(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	projectID := s.project(alice, "Alpha")
+	first := s.task(alice, projectID, "First")
+	second := s.task(alice, projectID, "Second")
+	s.task(alice, projectID, "Third")
+	for _, id := range []uint{first, second} {
+		if w := s.setTaskStatus(alice, projectID, id, "In_Progress"); w.Code != http.StatusOK {
+			t.Fatalf("start task: status %d: %s", w.Code, w.Body)
+		}
+	}
+	path := fmt.Sprintf("/projects/%d/workflow", projectID)
+	statuses := []gin.H{
+		{"name": "In_Line", "category": StatusCategoryTodo},
+		{"name": "Review", "category": StatusCategoryInProgress},
+		{"name": "Done", "category": StatusCategoryDone},
+	}
+
+	// Удаляемый статус с задачами требует замены; пустой статус удаляется без неё
+	out := s.expect(http.StatusConflict, http.MethodPut, path, alice, gin.H{"statuses": statuses})
+	if inUse := out["StatusesInUse"].(map[string]interface{}); len(inUse) != 1 || inUse["In_Progress"] != float64(2) {
+		t.Errorf("StatusesInUse = %v, want In_Progress: 2", inUse)
+	}
+	s.expect(http.StatusBadRequest, http.MethodPut, path, alice, gin.H{"statuses": statuses, "replacements": gin.H{"In_Progress": "Testing"}})
+	if got := s.taskStatus(alice, projectID, first); got != "In_Progress" {
+		t.Errorf("rejected workflow moved task to %s", got)
+	}
+
+	s.expect(http.StatusOK, http.MethodPut, path, alice, gin.H{"statuses": statuses, "replacements": gin.H{"In_Progress": "Review"}})
+	for _, id := range []uint{first, second} {
+		if got := s.taskStatus(alice, projectID, id); got != "Review" {
+			t.Errorf("task %d status = %s, want Review", id, got)
+		}
+	}
+	if got := workflowStatusNames(s.workflow(alice, projectID)); !slices.Equal(got, []string{"In_Line", "Review", "Done"}) {
+		t.Errorf("statuses = %v", got)
+	}
+
+	// Без переходов задачу нельзя перевести ни в один статус
+	out = s.expect(http.StatusConflict, http.MethodPut, fmt.Sprintf("/projects/%d/tasks/%d", projectID, first), alice, gin.H{
+		"title": "First", "priority": "High", "status": "Done", "deadline": time.Now().AddDate(0, 0, 7).UTC().Format(time.RFC3339),
+	})
+	if allowed, _ := out["AllowedStatuses"].([]interface{}); len(allowed) != 0 {
+		t.Errorf("AllowedStatuses = %v, want none", allowed)
+	}
+}
+
+func TestWorkflowTransitionRoles(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.user("alice")
+	member := s.user("member")
+	maintainer := s.user("maintainer")
+	projectID := s.project(owner, "Alpha")
+	s.addMember(owner, projectID, "member", ProjectRoleMember)
+	s.addMember(owner, projectID, "maintainer", ProjectRoleMaintainer)
+	taskID := s.task(owner, projectID, "First")
+
+	s.expect(http.StatusForbidden, http.MethodPut, fmt.Sprintf("/projects/%d/workflow", projectID), member, gin.H{})
+	s.expect(http.StatusOK, http.MethodPut, fmt.Sprintf("/projects/%d/workflow", projectID), maintainer, gin.H{
+		"statuses": []gin.H{
+			{"name": "In_Line", "category": StatusCategoryTodo},
+			{"name": "In_Progress", "category": StatusCategoryInProgress},
+			{"name": "Done", "category": StatusCategoryDone},
+		},
+		"transitions": []gin.H{
+			{"from": "In_Line", "to": "In_Progress"},
+			{"from": "In_Progress", "to": "Done", "role": ProjectRoleMaintainer},
+			{"from": "Done", "to": "In_Line", "role": ProjectRoleOwner},
+		},
+	})
+
+	for _, step := range []struct {
+		token, status string
+		want          int
+	}{
+		{member, "Done", http.StatusConflict}, // перехода In_Line → Done нет
+		{member, "In_Progress", http.StatusOK},
+		{member, "Done", http.StatusForbidden},
+		{maintainer, "Done", http.StatusOK},
+		{maintainer, "In_Line", http.StatusForbidden},
+		{member, "Done", http.StatusOK}, // статус не меняется
+		{owner, "In_Line", http.StatusOK},
+	} {
+		if w := s.setTaskStatus(step.token, projectID, taskID, step.status); w.Code != step.want {
+			t.Errorf("move to %s: status %d, want %d: %s", step.status, w.Code, step.want, w.Body)
+		}
+	}
+}
+
+// Миграция 0020 создаёт процесс по умолчанию для проектов, созданных до неё
+func TestWorkflowMigrationDefault(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user("alice")
+	alpha := s.project(alice, "Alpha")
+	beta := s.project(alice, "Beta")
+	taskID := s.task(alice, alpha, "First")
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(migrations, func(m migration) bool { return m.Name == "workflows" })
+	if i < 0 {
+		t.Fatal("no workflows migration")
+	}
+	if err := migrateDown(len(migrations) - i); err != nil {
+		t.Fatal(err)
+	}
+	if db.Migrator().HasTable("workflow_statuses") {
+		t.Fatal("workflow_statuses exists after down")
+	}
+	if err := migrateUp(0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := defaultWorkflow(0)
+	for _, projectID := range []uint{alpha, beta} {
+		workflow := s.workflow(alice, projectID)
+		if got := workflowStatusNames(workflow); !slices.Equal(got, want.statusNames()) {
+			t.Errorf("project %d: statuses = %v, want %v", projectID, got, want.statusNames())
+		}
+		for j, st := range workflow["statuses"].([]interface{}) {
+			if st.(map[string]interface{})["category"] != want.Statuses[j].Category {
+				t.Errorf("project %d: status %v, want category %s", projectID, st, want.Statuses[j].Category)
+			}
+		}
+		if got := workflow["transitions"].([]interface{}); len(got) != len(want.Transitions) {
+			t.Errorf("project %d: transitions = %v, want %d", projectID, got, len(want.Transitions))
+		}
+	}
+
+	// Задачи переходят между прежними статусами в любом порядке
+	for _, status := range []string{"Done", "In_Line", "In_Progress", "Done"} {
+		if w := s.setTaskStatus(alice, alpha, taskID, status); w.Code != http.StatusOK {
+			t.Errorf("move to %s: status %d: %s", status, w.Code, w.Body)
+		}
+	}
+}
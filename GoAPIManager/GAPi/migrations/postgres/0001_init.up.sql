@@ -0,0 +1,33 @@
+-- Базовая схема. IF NOT EXISTS нужен для баз, созданных раньше через AutoMigrate.
+CREATE TABLE IF NOT EXISTS users (
+    id           BIGSERIAL PRIMARY KEY,
+    username     TEXT NOT NULL UNIQUE,
+    password     TEXT NOT NULL,
+    role         TEXT NOT NULL,
+    refreshtoken TEXT
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    description TEXT,
+    created_at  TIMESTAMPTZ,
+    assignee_id BIGINT NOT NULL REFERENCES users (id),
+    file_path   TEXT
+);
+
+-- Колонка, которую AutoMigrate никогда не создавал
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS file_path TEXT;
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id          BIGSERIAL PRIMARY KEY,
+    project_id  BIGINT NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    title       TEXT NOT NULL,
+    description TEXT,
+    status      TEXT NOT NULL,
+    priority    TEXT NOT NULL,
+    deadline    TIMESTAMPTZ NOT NULL,
+    assignee_id BIGINT NOT NULL REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks (project_id);
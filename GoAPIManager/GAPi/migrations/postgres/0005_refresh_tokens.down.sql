@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS refreshtoken TEXT;
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,15 @@
+-- Refresh-токены хранятся только в виде хешей. Цепочка ротаций одной сессии образует семейство:
+-- повторное предъявление уже заменённого токена отзывает всю сессию.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          BIGSERIAL PRIMARY KEY,
+    session_id  BIGINT NOT NULL REFERENCES sessions (id) ON DELETE CASCADE,
+    token_hash  TEXT NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+
+-- Старые refresh-токены (JWT в открытом виде) больше не принимаются
+ALTER TABLE users DROP COLUMN IF EXISTS refreshtoken;
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS must_change_password;
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
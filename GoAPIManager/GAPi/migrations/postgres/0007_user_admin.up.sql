@@ -0,0 +1,8 @@
+-- Управление пользователями: блокировка, принудительная смена пароля, дата регистрации
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+
+-- Единое написание ролей: раньше проверялась роль "admin", а валидатор допускал "Admin"
+UPDATE users SET role = 'Admin' WHERE LOWER(role) = 'admin';
+UPDATE users SET role = 'User' WHERE role <> 'Admin';
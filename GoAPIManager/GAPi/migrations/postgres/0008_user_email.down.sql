@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS email_tokens;
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
@@ -0,0 +1,7 @@
+-- Неудачные попытки входа по имени пользователя (user:<имя>) и по IP (ip:<адрес>)
+CREATE TABLE IF NOT EXISTS login_failures (
+    key            TEXT PRIMARY KEY,
+    failures       INTEGER NOT NULL,
+    last_failed_at TIMESTAMPTZ NOT NULL,
+    locked_until   TIMESTAMPTZ
+);
@@ -0,0 +1,28 @@
+-- Журнал аудита изменений: кто, когда и что изменил. Записи только добавляются:
+-- на пользователей и проекты нет внешних ключей, чтобы история пережила их удаление.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id            BIGSERIAL PRIMARY KEY,
+    created_at    TIMESTAMPTZ NOT NULL,
+    actor_id      BIGINT,
+    actor         TEXT NOT NULL,
+    action        TEXT NOT NULL,
+    resource_type TEXT NOT NULL,
+    resource_id   BIGINT NOT NULL,
+    project_id    BIGINT,
+    changes       TEXT NOT NULL,
+    ip            TEXT NOT NULL,
+    request_id    TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_project_id ON audit_events (project_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id, created_at);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
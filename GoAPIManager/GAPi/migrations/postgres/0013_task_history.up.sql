@@ -0,0 +1,28 @@
+-- История изменений задач: одна запись на изменённое поле, значения в JSON
+CREATE TABLE IF NOT EXISTS task_history (
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    BIGINT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    actor_id   BIGINT,
+    actor      TEXT NOT NULL,
+    field      TEXT NOT NULL,
+    old_value  TEXT NOT NULL,
+    new_value  TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_history_task_id ON task_history (task_id, id);
+
+-- Лента событий проекта: создание задач, смена статуса и исполнителей, загрузка файлов.
+-- Событие остаётся в ленте и после удаления задачи, поэтому на задачу нет внешнего ключа.
+CREATE TABLE IF NOT EXISTS project_activities (
+    id         BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    task_id    BIGINT,
+    actor_id   BIGINT,
+    actor      TEXT NOT NULL,
+    type       TEXT NOT NULL,
+    details    TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_activities_project_id ON project_activities (project_id, id);
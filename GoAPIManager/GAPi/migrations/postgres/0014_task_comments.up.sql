@@ -0,0 +1,24 @@
+-- Комментарии к задачам (текст в Markdown). Удалённый комментарий помечается deleted_at;
+-- комментарии удалённого пользователя остаются без автора.
+CREATE TABLE IF NOT EXISTS task_comments (
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    BIGINT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    author_id  BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    body       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    edited_at  TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments (task_id, id);
+
+-- Упоминания участников проекта (@username) в комментариях
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    id         BIGSERIAL PRIMARY KEY,
+    comment_id BIGINT NOT NULL REFERENCES task_comments (id) ON DELETE CASCADE,
+    user_id    BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (comment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_user_id ON comment_mentions (user_id, id);
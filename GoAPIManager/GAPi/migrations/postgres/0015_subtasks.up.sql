@@ -0,0 +1,16 @@
+-- Подзадачи: задача может входить в другую задачу того же проекта и удаляется вместе с ней
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_task_id BIGINT REFERENCES tasks (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_task_id ON tasks (parent_task_id);
+
+-- Пункты чек-листа задачи
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    BIGINT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    title      TEXT NOT NULL,
+    done       BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL,
+    done_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_id ON task_checklist_items (task_id, id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS task_timers;
+DROP TABLE IF EXISTS task_worklogs;
+ALTER TABLE tasks DROP COLUMN IF EXISTS estimate_minutes;
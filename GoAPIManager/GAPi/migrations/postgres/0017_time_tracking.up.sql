@@ -0,0 +1,25 @@
+-- Оценка трудоёмкости задачи в минутах
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_minutes INTEGER;
+
+-- Затраченное на задачу время. work_date — день работы (YYYY-MM-DD);
+-- записи удалённого пользователя остаются без автора.
+CREATE TABLE IF NOT EXISTS task_worklogs (
+    id               BIGSERIAL PRIMARY KEY,
+    task_id          BIGINT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id          BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0),
+    work_date        TEXT NOT NULL,
+    note             TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_worklogs_task_id ON task_worklogs (task_id, id);
+CREATE INDEX IF NOT EXISTS idx_task_worklogs_work_date ON task_worklogs (work_date);
+
+-- Запущенные таймеры: у пользователя не больше одного таймера
+CREATE TABLE IF NOT EXISTS task_timers (
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    BIGINT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id    BIGINT NOT NULL UNIQUE REFERENCES users (id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_recurrence_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_id;
+DROP TABLE IF EXISTS task_recurrences;
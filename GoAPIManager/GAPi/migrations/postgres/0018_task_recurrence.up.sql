@@ -0,0 +1,25 @@
+-- Серии повторяющихся задач. rule — правило повторения (подмножество RRULE из RFC 5545),
+-- starts_at — срок первой задачи серии, current_at — срок текущей задачи по правилу.
+-- occurrences — сколько задач создано серией, заодно версия для планировщика.
+-- status: active, finished (правило исчерпано) или stopped (остановлена вручную).
+CREATE TABLE IF NOT EXISTS task_recurrences (
+    id              BIGSERIAL PRIMARY KEY,
+    project_id      BIGINT NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    rule            TEXT NOT NULL,
+    starts_at       TIMESTAMPTZ NOT NULL,
+    current_task_id BIGINT REFERENCES tasks (id) ON DELETE SET NULL,
+    current_at      TIMESTAMPTZ NOT NULL,
+    occurrences     INTEGER NOT NULL DEFAULT 1,
+    status          TEXT NOT NULL DEFAULT 'active',
+    created_by      BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    ended_at        TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_recurrences_project_id ON task_recurrences (project_id);
+CREATE INDEX IF NOT EXISTS idx_task_recurrences_status ON task_recurrences (status, current_at);
+
+-- Серия, к которой относится задача
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_id BIGINT REFERENCES task_recurrences (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_recurrence_id ON tasks (recurrence_id);
@@ -0,0 +1,26 @@
+-- Уведомления пользователей (внутренний канал доставки). dedup_key защищает от повторной
+-- отправки одного и того же напоминания; read_at — время прочтения (NULL — не прочитано).
+CREATE TABLE IF NOT EXISTS notifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    project_id BIGINT REFERENCES projects (id) ON DELETE CASCADE,
+    task_id    BIGINT REFERENCES tasks (id) ON DELETE CASCADE,
+    type       TEXT NOT NULL,
+    message    TEXT NOT NULL,
+    details    TEXT NOT NULL DEFAULT 'null',
+    dedup_key  TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    read_at    TIMESTAMPTZ,
+    UNIQUE (user_id, dedup_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id, id);
+
+-- Настройки доставки уведомлений. Без записи уведомления доставляются только внутри сервиса.
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id        BIGINT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    email          BOOLEAN NOT NULL DEFAULT FALSE,
+    webhook_url    TEXT NOT NULL DEFAULT '',
+    webhook_secret TEXT NOT NULL DEFAULT '',
+    updated_at     TIMESTAMPTZ NOT NULL
+);
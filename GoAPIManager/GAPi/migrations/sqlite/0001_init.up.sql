@@ -0,0 +1,30 @@
+-- Базовая схема
+CREATE TABLE IF NOT EXISTS users (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    username     TEXT NOT NULL UNIQUE,
+    password     TEXT NOT NULL,
+    role         TEXT NOT NULL,
+    refreshtoken TEXT
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        TEXT NOT NULL,
+    description TEXT,
+    created_at  DATETIME,
+    assignee_id INTEGER NOT NULL REFERENCES users (id),
+    file_path   TEXT
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id  INTEGER NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    title       TEXT NOT NULL,
+    description TEXT,
+    status      TEXT NOT NULL,
+    priority    TEXT NOT NULL,
+    deadline    DATETIME NOT NULL,
+    assignee_id INTEGER NOT NULL REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks (project_id);
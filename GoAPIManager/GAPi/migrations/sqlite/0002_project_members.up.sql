@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS project_members (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role       TEXT NOT NULL,
+    created_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_members_project_user ON project_members (project_id, user_id);
+
+-- Проекты, созданные до появления участников: автор становится владельцем
+INSERT INTO project_members (project_id, user_id, role, created_at)
+SELECT p.id, p.assignee_id, 'owner', CURRENT_TIMESTAMP FROM projects p
+WHERE NOT EXISTS (SELECT 1 FROM project_members m WHERE m.project_id = p.id AND m.user_id = p.assignee_id);
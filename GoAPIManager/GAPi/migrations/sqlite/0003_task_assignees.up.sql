@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS task_assignees (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id    INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_assignees_task_user ON task_assignees (task_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_task_assignees_user_id ON task_assignees (user_id);
+
+-- Задачи, созданные до появления нескольких исполнителей
+INSERT INTO task_assignees (task_id, user_id, created_at)
+SELECT t.id, t.assignee_id, CURRENT_TIMESTAMP FROM tasks t
+WHERE NOT EXISTS (SELECT 1 FROM task_assignees a WHERE a.task_id = t.id AND a.user_id = t.assignee_id);
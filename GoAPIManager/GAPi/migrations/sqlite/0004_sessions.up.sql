@@ -0,0 +1,15 @@
+-- Сессии пользователей: каждый вход (устройство) получает свою сессию с уникальным jti
+CREATE TABLE IF NOT EXISTS sessions (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    jti          TEXT NOT NULL UNIQUE,
+    device_name  TEXT NOT NULL DEFAULT '',
+    ip           TEXT NOT NULL DEFAULT '',
+    user_agent   TEXT NOT NULL DEFAULT '',
+    created_at   DATETIME NOT NULL,
+    last_used_at DATETIME NOT NULL,
+    expires_at   DATETIME NOT NULL,
+    revoked_at   DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
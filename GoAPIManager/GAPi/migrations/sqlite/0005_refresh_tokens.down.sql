@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN refreshtoken TEXT;
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,15 @@
+-- Персональные токены доступа для скриптов и CI. Хранится только SHA-256 токена.
+CREATE TABLE IF NOT EXISTS personal_tokens (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    token_hash   TEXT NOT NULL UNIQUE,
+    prefix       TEXT NOT NULL,
+    scopes       TEXT NOT NULL,
+    created_at   DATETIME NOT NULL,
+    expires_at   DATETIME,
+    last_used_at DATETIME,
+    revoked_at   DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_tokens_user_id ON personal_tokens (user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN created_at;
+ALTER TABLE users DROP COLUMN must_change_password;
+ALTER TABLE users DROP COLUMN disabled_at;
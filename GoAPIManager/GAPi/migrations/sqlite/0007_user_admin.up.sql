@@ -0,0 +1,8 @@
+-- Управление пользователями: блокировка, принудительная смена пароля, дата регистрации
+ALTER TABLE users ADD COLUMN disabled_at DATETIME;
+ALTER TABLE users ADD COLUMN must_change_password INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN created_at DATETIME;
+
+-- Единое написание ролей: раньше проверялась роль "admin", а валидатор допускал "Admin"
+UPDATE users SET role = 'Admin' WHERE LOWER(role) = 'admin';
+UPDATE users SET role = 'User' WHERE role <> 'Admin';
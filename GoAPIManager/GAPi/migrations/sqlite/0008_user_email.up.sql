@@ -0,0 +1,19 @@
+-- Email пользователя, подтверждение адреса и сброс пароля по почте
+ALTER TABLE users ADD COLUMN email TEXT;
+ALTER TABLE users ADD COLUMN email_verified_at DATETIME;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+-- Одноразовые токены из писем. Хранится только SHA-256 токена.
+CREATE TABLE IF NOT EXISTS email_tokens (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    TEXT NOT NULL,
+    email      TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at    DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_tokens_user_id ON email_tokens (user_id);
@@ -0,0 +1,25 @@
+-- Учётные записи внешнего провайдера входа (OpenID Connect), привязанные к пользователям
+CREATE TABLE IF NOT EXISTS user_identities (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id       INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    issuer        TEXT NOT NULL,
+    subject       TEXT NOT NULL,
+    email         TEXT,
+    created_at    DATETIME NOT NULL,
+    last_login_at DATETIME NOT NULL,
+    UNIQUE (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
+
+-- Начатые входы через OpenID Connect: state, nonce и code_verifier (PKCE) до возврата от провайдера
+CREATE TABLE IF NOT EXISTS oidc_logins (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    state_hash    TEXT NOT NULL UNIQUE,
+    nonce         TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    device_name   TEXT NOT NULL,
+    created_at    DATETIME NOT NULL,
+    expires_at    DATETIME NOT NULL,
+    used_at       DATETIME
+);
@@ -0,0 +1,29 @@
+-- Журнал аудита изменений: кто, когда и что изменил. Записи только добавляются:
+-- на пользователей и проекты нет внешних ключей, чтобы история пережила их удаление.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at    DATETIME NOT NULL,
+    actor_id      INTEGER,
+    actor         TEXT NOT NULL,
+    action        TEXT NOT NULL,
+    resource_type TEXT NOT NULL,
+    resource_id   INTEGER NOT NULL,
+    project_id    INTEGER,
+    changes       TEXT NOT NULL,
+    ip            TEXT NOT NULL,
+    request_id    TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_project_id ON audit_events (project_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id, created_at);
+
+CREATE TRIGGER IF NOT EXISTS audit_events_no_update BEFORE UPDATE ON audit_events
+BEGIN
+    SELECT RAISE(ABORT, 'audit_events is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_events_no_delete BEFORE DELETE ON audit_events
+BEGIN
+    SELECT RAISE(ABORT, 'audit_events is append-only');
+END;
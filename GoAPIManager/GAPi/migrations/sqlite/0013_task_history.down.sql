@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS project_activities;
+DROP TABLE IF EXISTS task_history;
@@ -0,0 +1,24 @@
+-- Комментарии к задачам (текст в Markdown). Удалённый комментарий помечается deleted_at;
+-- комментарии удалённого пользователя остаются без автора.
+CREATE TABLE IF NOT EXISTS task_comments (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id    INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    author_id  INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    body       TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    edited_at  DATETIME,
+    deleted_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments (task_id, id);
+
+-- Упоминания участников проекта (@username) в комментариях
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    comment_id INTEGER NOT NULL REFERENCES task_comments (id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL,
+    UNIQUE (comment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_user_id ON comment_mentions (user_id, id);
@@ -0,0 +1,16 @@
+-- Подзадачи: задача может входить в другую задачу того же проекта и удаляется вместе с ней
+ALTER TABLE tasks ADD COLUMN parent_task_id INTEGER REFERENCES tasks (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_task_id ON tasks (parent_task_id);
+
+-- Пункты чек-листа задачи
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id    INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    title      TEXT NOT NULL,
+    done       INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    done_at    DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_id ON task_checklist_items (task_id, id);
@@ -0,0 +1,14 @@
+-- Связи задач проекта: source блокирует target (blocks), дублирует её (duplicates)
+-- или связана с ней (relates_to, хранится с source_task_id < target_task_id)
+CREATE TABLE IF NOT EXISTS task_links (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    target_task_id INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    type           TEXT NOT NULL,
+    created_by     INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at     DATETIME NOT NULL,
+    UNIQUE (source_task_id, target_task_id, type),
+    CHECK (source_task_id <> target_task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_links_target_task_id ON task_links (target_task_id);
@@ -0,0 +1,26 @@
+-- Уведомления пользователей (внутренний канал доставки). dedup_key защищает от повторной
+-- отправки одного и того же напоминания; read_at — время прочтения (NULL — не прочитано).
+CREATE TABLE IF NOT EXISTS notifications (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    project_id INTEGER REFERENCES projects (id) ON DELETE CASCADE,
+    task_id    INTEGER REFERENCES tasks (id) ON DELETE CASCADE,
+    type       TEXT NOT NULL,
+    message    TEXT NOT NULL,
+    details    TEXT NOT NULL DEFAULT 'null',
+    dedup_key  TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    read_at    DATETIME,
+    UNIQUE (user_id, dedup_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id, id);
+
+-- Настройки доставки уведомлений. Без записи уведомления доставляются только внутри сервиса.
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id        INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    email          INTEGER NOT NULL DEFAULT 0,
+    webhook_url    TEXT NOT NULL DEFAULT '',
+    webhook_secret TEXT NOT NULL DEFAULT '',
+    updated_at     DATETIME NOT NULL
+);
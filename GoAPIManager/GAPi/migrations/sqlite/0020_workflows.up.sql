@@ -0,0 +1,36 @@
+-- Статусы задач проекта по порядку. category — todo, in_progress или done:
+-- задачи в статусах категории done считаются завершёнными.
+CREATE TABLE IF NOT EXISTS workflow_statuses (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    category   TEXT NOT NULL,
+    position   INTEGER NOT NULL,
+    UNIQUE (project_id, name)
+);
+
+-- Разрешённые переходы между статусами. role — минимальная роль в проекте для перехода
+-- (пустая строка — достаточно права task.update).
+CREATE TABLE IF NOT EXISTS workflow_transitions (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id     INTEGER NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    from_status_id INTEGER NOT NULL REFERENCES workflow_statuses (id) ON DELETE CASCADE,
+    to_status_id   INTEGER NOT NULL REFERENCES workflow_statuses (id) ON DELETE CASCADE,
+    role           TEXT NOT NULL DEFAULT '',
+    UNIQUE (from_status_id, to_status_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_transitions_project_id ON workflow_transitions (project_id);
+
+-- Процесс по умолчанию для существующих проектов: прежние статусы и любые переходы между ними
+INSERT INTO workflow_statuses (project_id, name, category, position)
+SELECT id, 'In_Line', 'todo', 0 FROM projects
+UNION ALL
+SELECT id, 'In_Progress', 'in_progress', 1 FROM projects
+UNION ALL
+SELECT id, 'Done', 'done', 2 FROM projects;
+
+INSERT INTO workflow_transitions (project_id, from_status_id, to_status_id)
+SELECT a.project_id, a.id, b.id
+FROM workflow_statuses a
+JOIN workflow_statuses b ON b.project_id = a.project_id AND b.id <> a.id;
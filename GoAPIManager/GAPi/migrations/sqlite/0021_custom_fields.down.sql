@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_field_values;
+DROP TABLE IF EXISTS custom_fields;
@@ -0,0 +1,26 @@
+-- Пользовательские поля задач проекта. type — text, number, date, select, multi_select или user;
+-- options — варианты значений (JSON-массив строк) для select и multi_select.
+CREATE TABLE IF NOT EXISTS custom_fields (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    type       TEXT NOT NULL,
+    options    TEXT NOT NULL DEFAULT '[]',
+    required   INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    UNIQUE (project_id, name)
+);
+
+-- Значения полей задач. text_value — для text, date (YYYY-MM-DD), select и multi_select
+-- (по строке на выбранный вариант), number_value — для number и user (ID пользователя).
+CREATE TABLE IF NOT EXISTS task_field_values (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id      INTEGER NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    field_id     INTEGER NOT NULL REFERENCES custom_fields (id) ON DELETE CASCADE,
+    text_value   TEXT,
+    number_value REAL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_field_values_task_id ON task_field_values (task_id);
+CREATE INDEX IF NOT EXISTS idx_task_field_values_field_text ON task_field_values (field_id, text_value);
+CREATE INDEX IF NOT EXISTS idx_task_field_values_field_number ON task_field_values (field_id, number_value);
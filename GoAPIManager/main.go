@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	GoAPIManager "GoAPIManager/GAPi"
 	_ "GoAPIManager/docs"
 )
@@ -12,5 +14,6 @@ import (
 // @BasePath        /
 
 func main() {
-	GoAPIManager.Controller()
+	// Запуск сервера или подкоманды (migrate, config print), см. GoAPIManager.Run
+	GoAPIManager.Run(os.Args[1:])
 }